@@ -10,7 +10,12 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.22
 )
 
-require github.com/gorilla/websocket v1.5.3
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/gorilla/websocket v1.5.3
+)
+
+require golang.org/x/sys v0.33.0 // indirect
 
 require (
 	github.com/hashicorp/errwrap v1.1.0 // indirect
@@ -0,0 +1,174 @@
+// backend/pkg/auth/access_token.go
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+)
+
+// AccessTokenPrefix marks personal access tokens so they are easy to recognise in scripts and secret scanners
+const AccessTokenPrefix = "rpl_"
+
+const accessTokenColumns = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at"
+
+// CreateAccessToken issues a personal access token for the user. The returned token
+// is only available here; the database keeps its hash.
+func (sm *SessionManager) CreateAccessToken(userID int, name string, scopes []string, expiresAt time.Time) (string, *models.PersonalAccessToken, error) {
+	secret, _, err := GenerateToken()
+	if err != nil {
+		return "", nil, err
+	}
+	token := AccessTokenPrefix + secret
+
+	accessToken := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := sm.db.Exec(query, userID, name, accessToken.TokenHash, strings.Join(scopes, " "), expiresAt, accessToken.CreatedAt)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get access token ID: %w", err)
+	}
+	accessToken.ID = int(id)
+
+	return token, accessToken, nil
+}
+
+// GetAccessToken validates a bearer token and records that it was used
+func (sm *SessionManager) GetAccessToken(token string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, AccessTokenPrefix) {
+		return nil, fmt.Errorf("access token not found")
+	}
+
+	accessToken := &models.PersonalAccessToken{}
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = ?`
+	if err := scanAccessToken(sm.db.QueryRow(query, HashToken(token)), accessToken); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("access token not found")
+		}
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	now := time.Now()
+	if now.After(accessToken.ExpiresAt) {
+		return nil, fmt.Errorf("access token expired")
+	}
+
+	// Avoid a write on every request from busy scripts
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= sessionRefreshThreshold {
+		if _, err := sm.db.Exec(`UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?`, now, accessToken.ID); err != nil {
+			log.Printf("Failed to update last used time of access token ID %d: %v", accessToken.ID, err)
+		} else {
+			accessToken.LastUsedAt = &now
+		}
+	}
+
+	return accessToken, nil
+}
+
+// GetUserAccessTokens lists the user's tokens that have not expired
+func (sm *SessionManager) GetUserAccessTokens(userID int) ([]*models.PersonalAccessToken, error) {
+	query := `
+		SELECT ` + accessTokenColumns + `
+		FROM personal_access_tokens
+		WHERE user_id = ? AND expires_at > ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := sm.db.Query(query, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*models.PersonalAccessToken
+	for rows.Next() {
+		accessToken := &models.PersonalAccessToken{}
+		if err := scanAccessToken(rows, accessToken); err != nil {
+			return nil, fmt.Errorf("failed to scan access token: %w", err)
+		}
+		tokens = append(tokens, accessToken)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during access token rows iteration: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeAccessToken deletes one of the user's tokens
+func (sm *SessionManager) RevokeAccessToken(userID, tokenID int) error {
+	result, err := sm.db.Exec(`DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?`, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf(constants.ErrAccessTokenNotFound)
+	}
+	return nil
+}
+
+// DeleteUserAccessTokens revokes every token of the user
+func (sm *SessionManager) DeleteUserAccessTokens(userID int) error {
+	if _, err := sm.db.Exec(`DELETE FROM personal_access_tokens WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete access tokens: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredAccessTokens removes tokens that can no longer be used
+func (sm *SessionManager) CleanupExpiredAccessTokens() error {
+	if _, err := sm.db.Exec(`DELETE FROM personal_access_tokens WHERE expires_at <= ?`, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup access tokens: %w", err)
+	}
+	return nil
+}
+
+func scanAccessToken(row interface{ Scan(...any) error }, accessToken *models.PersonalAccessToken) error {
+	var scopes string
+	var lastUsedAt sql.NullTime
+	err := row.Scan(
+		&accessToken.ID,
+		&accessToken.UserID,
+		&accessToken.Name,
+		&accessToken.TokenHash,
+		&scopes,
+		&accessToken.ExpiresAt,
+		&lastUsedAt,
+		&accessToken.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	accessToken.Scopes = strings.Fields(scopes)
+	if lastUsedAt.Valid {
+		accessToken.LastUsedAt = &lastUsedAt.Time
+	}
+	return nil
+}
@@ -6,47 +6,208 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"ripple/pkg/constants"
+	"ripple/pkg/logging"
+	"ripple/pkg/models"
 	"ripple/pkg/utils"
+	"strings"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey      contextKey = "userID"
+	SessionIDKey   contextKey = "sessionID"
+	AccessTokenKey contextKey = "accessToken"
+	RoleKey        contextKey = "role"
+)
 
 func (sm *SessionManager) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("AuthMiddleware: Processing request to %s", r.URL.Path)
+		logging.FromContext(r.Context()).Debug("AuthMiddleware: processing request", "path", r.URL.Path)
+
+		// Scripts and bots authenticate with a personal access token instead of the cookie
+		if token, ok := bearerToken(r); ok {
+			accessToken, err := sm.GetAccessToken(token)
+			if err != nil {
+				log.Printf("AuthMiddleware: Access token validation failed: %v", err)
+				utils.WriteErrorResponse(w, http.StatusUnauthorized, "Invalid or expired access token")
+				return
+			}
+
+			logging.SetUserID(r.Context(), accessToken.UserID)
+			ctx := context.WithValue(r.Context(), UserIDKey, accessToken.UserID)
+			ctx = context.WithValue(ctx, AccessTokenKey, accessToken)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
 
 		// Get session cookie
-		cookie, err := r.Cookie("session_id")
+		cookie, err := r.Cookie(SessionCookieName)
 		if err != nil {
 			log.Printf("AuthMiddleware: No session cookie found: %v", err)
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "Authentication required")
 			return
 		}
 
-		log.Printf("AuthMiddleware: Found session cookie: %s", cookie.Value)
-
 		// Validate session
 		session, err := sm.GetSession(cookie.Value)
 		if err != nil {
-			log.Printf("AuthMiddleware: Session validation failed for %s: %v", cookie.Value, err)
+			log.Printf("AuthMiddleware: Session validation failed for session %s: %v", logging.Fingerprint(cookie.Value), err)
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "Invalid or expired session")
 			return
 		}
 
-		log.Printf("AuthMiddleware: Session validated successfully: UserID=%d", session.UserID)
+		logging.FromContext(r.Context()).Debug("AuthMiddleware: session validated", "user_id", session.UserID)
+		logging.SetUserID(r.Context(), session.UserID)
+
+		// Slide the idle expiry forward for sessions that are still in use
+		extended, err := sm.ExtendSession(session)
+		if err != nil {
+			log.Printf("AuthMiddleware: Failed to extend session for UserID=%d: %v", session.UserID, err)
+		} else if extended {
+			SetSessionCookie(w, session.ID, session.ExpiresAt)
+		}
 
 		// Add user ID to request context
 		ctx := context.WithValue(r.Context(), UserIDKey, session.UserID)
+		ctx = context.WithValue(ctx, SessionIDKey, session.ID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireVerifiedEmail rejects requests from users who have not confirmed their
+// email address. It must run after AuthMiddleware.
+func (sm *SessionManager) RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := GetUserIDFromContext(r.Context())
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		verified, err := sm.IsEmailVerified(userID)
+		if err != nil {
+			log.Printf("RequireVerifiedEmail: Failed to check user %d: %v", userID, err)
+			utils.WriteInternalErrorResponse(w, err)
+			return
+		}
+		if !verified {
+			utils.WriteErrorResponseWithCode(w, http.StatusForbidden, constants.ErrEmailNotVerified, constants.CodeEmailNotVerified)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole rejects requests from users whose site-wide role ranks below
+// minRole and stores the role in the request context. It must run after AuthMiddleware.
+func (sm *SessionManager) RequireRole(minRole string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserIDFromContext(r.Context())
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			role, err := sm.GetUserRole(userID)
+			if err != nil {
+				log.Printf("RequireRole: Failed to get role of user %d: %v", userID, err)
+				utils.WriteInternalErrorResponse(w, err)
+				return
+			}
+			if !RoleAtLeast(role, minRole) {
+				log.Printf("RequireRole: User %d with role %q denied %s %s", userID, role, r.Method, r.URL.Path)
+				utils.WriteErrorResponseWithCode(w, http.StatusForbidden, constants.ErrInsufficientPermissions, constants.CodeInsufficientRole)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), RoleKey, role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScopes limits requests authenticated with a personal access token to the
+// token's scopes: GET and HEAD need readScope, other methods need writeScope.
+// An empty scope means tokens cannot use the route at all. Cookie sessions are
+// not restricted. It must run after AuthMiddleware.
+func RequireScopes(readScope, writeScope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accessToken, ok := GetAccessTokenFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scope := writeScope
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = readScope
+			}
+			if scope == "" || !accessToken.HasScope(scope) {
+				log.Printf("RequireScopes: Token %d of user %d lacks scope %q for %s %s", accessToken.ID, accessToken.UserID, scope, r.Method, r.URL.Path)
+				utils.WriteErrorResponseWithCode(w, http.StatusForbidden, constants.ErrInsufficientScope, constants.CodeInsufficientScope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects requests authenticated with a personal access token.
+// It protects account management routes that only a signed-in user may use.
+func RequireSession(next http.Handler) http.Handler {
+	return RequireScopes("", "")(next)
+}
+
 func GetUserIDFromContext(ctx context.Context) (int, error) {
 	userID, ok := ctx.Value(UserIDKey).(int)
 	if !ok {
 		return 0, fmt.Errorf("user ID not found in context")
 	}
 	return userID, nil
-}
\ No newline at end of file
+}
+
+func GetSessionIDFromContext(ctx context.Context) (string, error) {
+	sessionID, ok := ctx.Value(SessionIDKey).(string)
+	if !ok || sessionID == "" {
+		return "", fmt.Errorf("session ID not found in context")
+	}
+	return sessionID, nil
+}
+
+// GetRoleFromContext returns the role stored by RequireRole
+func GetRoleFromContext(ctx context.Context) (string, error) {
+	role, ok := ctx.Value(RoleKey).(string)
+	if !ok || role == "" {
+		return "", fmt.Errorf("role not found in context")
+	}
+	return role, nil
+}
+
+// GetAccessTokenFromContext returns the personal access token that authenticated
+// the request, if any.
+func GetAccessTokenFromContext(ctx context.Context) (*models.PersonalAccessToken, bool) {
+	accessToken, ok := ctx.Value(AccessTokenKey).(*models.PersonalAccessToken)
+	return accessToken, ok && accessToken != nil
+}
+
+// HasBearerToken reports whether the request authenticates with a personal access
+// token rather than the session cookie
+func HasBearerToken(r *http.Request) bool {
+	_, ok := bearerToken(r)
+	return ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(header[7:])
+	return token, token != ""
+}
@@ -2,26 +2,196 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const bcryptCost = 12
+// Password hashes are stored in a self-describing format so the algorithm and
+// its parameters can change without invalidating existing passwords:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>   (current)
+//	$2a$12$...                                    (bcrypt, verified only)
+const argon2idPrefix = "$argon2id$"
+
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// Argon2Params controls the cost of new argon2id hashes. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the RFC 9106 recommendation for memory-constrained environments
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+var (
+	paramsMu      sync.RWMutex
+	currentParams = DefaultArgon2Params
+)
+
+// NewArgon2Params checks configured cost parameters against the limits of
+// argon2id, which panics outside them, and returns them with the default salt
+// and key lengths. Memory is in KiB.
+func NewArgon2Params(memory, iterations, parallelism int64) (Argon2Params, error) {
+	switch {
+	case iterations < 1 || iterations > math.MaxUint32:
+		return Argon2Params{}, fmt.Errorf("argon2 iterations must be between 1 and %d, got %d", uint32(math.MaxUint32), iterations)
+	case parallelism < 1 || parallelism > math.MaxUint8:
+		return Argon2Params{}, fmt.Errorf("argon2 parallelism must be between 1 and %d, got %d", math.MaxUint8, parallelism)
+	case memory < 8*parallelism || memory > math.MaxUint32:
+		return Argon2Params{}, fmt.Errorf("argon2 memory must be between %d (8 KiB per lane) and %d KiB, got %d", 8*parallelism, uint32(math.MaxUint32), memory)
+	}
+
+	return Argon2Params{
+		Memory:      uint32(memory),
+		Iterations:  uint32(iterations),
+		Parallelism: uint8(parallelism),
+		SaltLength:  DefaultArgon2Params.SaltLength,
+		KeyLength:   DefaultArgon2Params.KeyLength,
+	}, nil
+}
+
+// validate reports parameters argon2.IDKey cannot hash with
+func (params Argon2Params) validate() error {
+	if params.Iterations < 1 || params.Parallelism < 1 || params.Memory < 8*uint32(params.Parallelism) {
+		return fmt.Errorf("invalid argon2 parameters m=%d,t=%d,p=%d", params.Memory, params.Iterations, params.Parallelism)
+	}
+	return nil
+}
+
+// SetArgon2Params changes the parameters used for new hashes. Existing hashes
+// with weaker parameters are upgraded the next time their owner logs in.
+func SetArgon2Params(params Argon2Params) error {
+	if err := params.validate(); err != nil {
+		return err
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2Params.KeyLength
+	}
+
+	paramsMu.Lock()
+	defer paramsMu.Unlock()
+	currentParams = params
+	return nil
+}
+
+func getArgon2Params() Argon2Params {
+	paramsMu.RLock()
+	defer paramsMu.RUnlock()
+	return currentParams
+}
 
 func HashPassword(password string) (string, error) {
 	if len(password) < 8 {
 		return "", fmt.Errorf("password must be at least 8 characters long")
 	}
-	
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
-	if err != nil {
+
+	params := getArgon2Params()
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
-	
-	return string(hash), nil
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
 }
 
+// CheckPassword verifies password against an argon2id or bcrypt hash
 func CheckPassword(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash uses an older algorithm or weaker parameters
+// than new hashes would. Call it after a successful CheckPassword and store a
+// fresh hash of the password when it returns true.
+func NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+
+	params, _, _, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+
+	current := getArgon2Params()
+	return params.Memory < current.Memory ||
+		params.Iterations < current.Iterations ||
+		params.Parallelism < current.Parallelism ||
+		params.KeyLength < current.KeyLength
 }
 
+func decodeArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash parameters: %w", err)
+	}
+	if err := params.validate(); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,28 @@
+// backend/pkg/auth/roles.go
+package auth
+
+import "ripple/pkg/constants"
+
+var roleRanks = map[string]int{
+	constants.RoleUser:      0,
+	constants.RoleModerator: 1,
+	constants.RoleAdmin:     2,
+}
+
+// IsValidRole reports whether role is one of the site-wide roles
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants at least the privileges of minRole
+func RoleAtLeast(role, minRole string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[minRole]
+}
+
+// RoleOutranks reports whether role is strictly more privileged than other.
+// Staff can only act on accounts below their own role.
+func RoleOutranks(role, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
@@ -0,0 +1,44 @@
+// backend/pkg/auth/scopes.go
+package auth
+
+// Scopes that can be granted to personal access tokens. Each API area has a
+// read scope for GET requests and a write scope for everything else.
+const (
+	ScopeProfileRead        = "profile:read"
+	ScopeProfileWrite       = "profile:write"
+	ScopeFollowsRead        = "follows:read"
+	ScopeFollowsWrite       = "follows:write"
+	ScopePostsRead          = "posts:read"
+	ScopePostsWrite         = "posts:write"
+	ScopeGroupsRead         = "groups:read"
+	ScopeGroupsWrite        = "groups:write"
+	ScopeEventsRead         = "events:read"
+	ScopeEventsWrite        = "events:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	ScopeChatRead           = "chat:read"
+	ScopeChatWrite          = "chat:write"
+	ScopeUploadsWrite       = "uploads:write"
+)
+
+// AvailableScopes lists every scope a token can be granted
+var AvailableScopes = []string{
+	ScopeProfileRead, ScopeProfileWrite,
+	ScopeFollowsRead, ScopeFollowsWrite,
+	ScopePostsRead, ScopePostsWrite,
+	ScopeGroupsRead, ScopeGroupsWrite,
+	ScopeEventsRead, ScopeEventsWrite,
+	ScopeNotificationsRead, ScopeNotificationsWrite,
+	ScopeChatRead, ScopeChatWrite,
+	ScopeUploadsWrite,
+}
+
+// IsValidScope reports whether scope is one of AvailableScopes
+func IsValidScope(scope string) bool {
+	for _, s := range AvailableScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
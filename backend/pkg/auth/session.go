@@ -6,64 +6,101 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"fmt"
-	"time"
+	"log"
+	"ripple/pkg/constants"
 	"ripple/pkg/models"
+	"time"
+)
+
+const (
+	DefaultIdleTimeout     = 24 * time.Hour * 7  // 7 days
+	DefaultAbsoluteTimeout = 24 * time.Hour * 30 // 30 days
+
+	// Sliding expiry and last use are only written back once they have moved by
+	// at least this much, so busy sessions don't cause a write on every request.
+	sessionRefreshThreshold = time.Minute
 )
 
-const sessionDuration = 24 * time.Hour * 30 // 30 days
+const maxUserAgentLength = 512
+
+const sessionColumns = `id, user_id, user_agent, ip_address, last_used_at, expires_at, absolute_expires_at, created_at`
 
 type SessionManager struct {
-	db *sql.DB
+	db              *sql.DB
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
 }
 
 func NewSessionManager(db *sql.DB) *SessionManager {
-	return &SessionManager{db: db}
+	return &SessionManager{
+		db:              db,
+		idleTimeout:     DefaultIdleTimeout,
+		absoluteTimeout: DefaultAbsoluteTimeout,
+	}
 }
 
-func (sm *SessionManager) CreateSession(userID int) (*models.Session, error) {
+// SetTimeouts configures how long a session may stay unused (idle) and how long
+// it may live in total (absolute). Non-positive values keep the current setting.
+func (sm *SessionManager) SetTimeouts(idle, absolute time.Duration) {
+	if idle > 0 {
+		sm.idleTimeout = idle
+	}
+	if absolute > 0 {
+		sm.absoluteTimeout = absolute
+	}
+}
+
+// nextExpiry returns the idle expiry for a session used at now, capped by its absolute expiry.
+func (sm *SessionManager) nextExpiry(now, absoluteExpiresAt time.Time) time.Time {
+	expiresAt := now.Add(sm.idleTimeout)
+	if expiresAt.After(absoluteExpiresAt) {
+		return absoluteExpiresAt
+	}
+	return expiresAt
+}
+
+func (sm *SessionManager) CreateSession(userID int, userAgent, ipAddress string) (*models.Session, error) {
 	// Generate random session ID
 	sessionID, err := generateSessionID()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate session ID: %w", err)
 	}
 
+	now := time.Now()
+	absoluteExpiresAt := now.Add(sm.absoluteTimeout)
 	session := &models.Session{
-		ID:        sessionID,
-		UserID:    userID,
-		ExpiresAt: time.Now().Add(sessionDuration),
-		CreatedAt: time.Now(),
+		ID:                sessionID,
+		UserID:            userID,
+		UserAgent:         truncate(userAgent, maxUserAgentLength),
+		IPAddress:         ipAddress,
+		LastUsedAt:        now,
+		ExpiresAt:         sm.nextExpiry(now, absoluteExpiresAt),
+		AbsoluteExpiresAt: absoluteExpiresAt,
+		CreatedAt:         now,
 	}
 
-	// Insert session into database
-	query := `
-		INSERT INTO sessions (id, user_id, expires_at, created_at)
-		VALUES (?, ?, ?, ?)
-	`
-	
-	_, err = sm.db.Exec(query, session.ID, session.UserID, session.ExpiresAt, session.CreatedAt)
-	if err != nil {
+	if err := insertSession(sm.db, session); err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
 	return session, nil
 }
 
+// GetSession returns a valid session and records that it has just been used.
+// The last use is written at most once per sessionRefreshThreshold, and failing
+// to write it does not fail the lookup.
 func (sm *SessionManager) GetSession(sessionID string) (*models.Session, error) {
 	session := &models.Session{}
-	
+
 	query := `
-		SELECT id, user_id, expires_at, created_at
+		SELECT ` + sessionColumns + `
 		FROM sessions
-		WHERE id = ? AND expires_at > ?
+		WHERE id = ? AND expires_at > ? AND (absolute_expires_at IS NULL OR absolute_expires_at > ?)
 	`
-	
-	err := sm.db.QueryRow(query, sessionID, time.Now()).Scan(
-		&session.ID,
-		&session.UserID,
-		&session.ExpiresAt,
-		&session.CreatedAt,
-	)
-	
+
+	now := time.Now()
+	err := scanSession(sm.db.QueryRow(query, sessionID, now, now), session)
+
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("session not found or expired")
@@ -71,9 +108,90 @@ func (sm *SessionManager) GetSession(sessionID string) (*models.Session, error)
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
+	if now.Sub(session.LastUsedAt) >= sessionRefreshThreshold {
+		if _, err := sm.db.Exec(`UPDATE sessions SET last_used_at = ? WHERE id = ?`, now, session.ID); err != nil {
+			log.Printf("Failed to update last used time of session for user ID %d: %v", session.UserID, err)
+		} else {
+			session.LastUsedAt = now
+		}
+	}
+
 	return session, nil
 }
 
+// GetUserSessions returns all active sessions of a user, most recently used first.
+func (sm *SessionManager) GetUserSessions(userID int) ([]*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = ? AND expires_at > ? AND (absolute_expires_at IS NULL OR absolute_expires_at > ?)
+		ORDER BY last_used_at DESC
+	`
+
+	now := time.Now()
+	rows, err := sm.db.Query(query, userID, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		session := &models.Session{}
+		if err := scanSession(rows, session); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during session rows iteration: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeUserSession deletes the user's session identified by its public ID.
+func (sm *SessionManager) RevokeUserSession(userID int, publicID string) error {
+	sessions, err := sm.GetUserSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.PublicID() == publicID {
+			return sm.DeleteSession(session.ID)
+		}
+	}
+
+	return fmt.Errorf(constants.ErrSessionNotFound)
+}
+
+// DeleteOtherSessions deletes every session of the user except keepSessionID.
+func (sm *SessionManager) DeleteOtherSessions(userID int, keepSessionID string) (int64, error) {
+	query := `DELETE FROM sessions WHERE user_id = ? AND id != ?`
+	result, err := sm.db.Exec(query, userID, keepSessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete other sessions: %w", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteUserSessions deletes every session of the user
+func (sm *SessionManager) DeleteUserSessions(userID int) error {
+	query := `DELETE FROM sessions WHERE user_id = ?`
+	if _, err := sm.db.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return nil
+}
+
 func (sm *SessionManager) DeleteSession(sessionID string) error {
 	query := `DELETE FROM sessions WHERE id = ?`
 	_, err := sm.db.Exec(query, sessionID)
@@ -83,19 +201,154 @@ func (sm *SessionManager) DeleteSession(sessionID string) error {
 	return nil
 }
 
+// IsEmailVerified reports whether the user has confirmed their email address
+func (sm *SessionManager) IsEmailVerified(userID int) (bool, error) {
+	var verified bool
+	query := `SELECT email_verified_at IS NOT NULL FROM users WHERE id = ?`
+	if err := sm.db.QueryRow(query, userID).Scan(&verified); err != nil {
+		return false, fmt.Errorf("failed to check email verification: %w", err)
+	}
+	return verified, nil
+}
+
+// GetUserRole returns the site-wide role of the user
+func (sm *SessionManager) GetUserRole(userID int) (string, error) {
+	var role string
+	query := `SELECT role FROM users WHERE id = ?`
+	if err := sm.db.QueryRow(query, userID).Scan(&role); err != nil {
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	return role, nil
+}
+
+// ExtendSession slides the idle expiry of a session that is still in use.
+// It reports whether the expiry changed so callers can refresh the cookie.
+func (sm *SessionManager) ExtendSession(session *models.Session) (bool, error) {
+	expiresAt := sm.nextExpiry(time.Now(), session.AbsoluteExpiresAt)
+	if expiresAt.Sub(session.ExpiresAt) < sessionRefreshThreshold {
+		return false, nil
+	}
+
+	query := `UPDATE sessions SET expires_at = ? WHERE id = ?`
+	if _, err := sm.db.Exec(query, expiresAt, session.ID); err != nil {
+		return false, fmt.Errorf("failed to extend session: %w", err)
+	}
+
+	session.ExpiresAt = expiresAt
+	return true, nil
+}
+
+// RotateSession replaces the session ID while keeping the session's owner,
+// device metadata and absolute expiry. The old ID stops working immediately.
+func (sm *SessionManager) RotateSession(sessionID string) (*models.Session, error) {
+	current, err := sm.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	newID, err := generateSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	now := time.Now()
+	rotated := *current
+	rotated.ID = newID
+	rotated.LastUsedAt = now
+	rotated.ExpiresAt = sm.nextExpiry(now, current.AbsoluteExpiresAt)
+
+	tx, err := sm.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSession(tx, &rotated); err != nil {
+		return nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE id = ?`, current.ID); err != nil {
+		return nil, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session rotation: %w", err)
+	}
+
+	return &rotated, nil
+}
+
 func (sm *SessionManager) CleanupExpiredSessions() error {
-	query := `DELETE FROM sessions WHERE expires_at <= ?`
-	_, err := sm.db.Exec(query, time.Now())
+	query := `DELETE FROM sessions WHERE expires_at <= ? OR absolute_expires_at <= ?`
+	now := time.Now()
+	_, err := sm.db.Exec(query, now, now)
 	if err != nil {
 		return fmt.Errorf("failed to cleanup expired sessions: %w", err)
 	}
 	return nil
 }
 
+func insertSession(exec interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := exec.Exec(query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IPAddress,
+		session.LastUsedAt,
+		session.ExpiresAt,
+		session.AbsoluteExpiresAt,
+		session.CreatedAt,
+	)
+	return err
+}
+
+// scanSession reads a row selected with sessionColumns.
+func scanSession(row interface{ Scan(...any) error }, session *models.Session) error {
+	var lastUsedAt, absoluteExpiresAt sql.NullTime
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&lastUsedAt,
+		&session.ExpiresAt,
+		&absoluteExpiresAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Sessions created before these columns existed fall back to older values
+	session.LastUsedAt = session.CreatedAt
+	if lastUsedAt.Valid {
+		session.LastUsedAt = lastUsedAt.Time
+	}
+	session.AbsoluteExpiresAt = session.ExpiresAt
+	if absoluteExpiresAt.Valid {
+		session.AbsoluteExpiresAt = absoluteExpiresAt.Time
+	}
+	return nil
+}
+
 func generateSessionID() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
 	return hex.EncodeToString(bytes), nil
-}
\ No newline at end of file
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
@@ -0,0 +1,26 @@
+// backend/pkg/auth/token.go
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateToken returns a random token to hand to the user together with the
+// hash that should be stored. Only the hash is ever persisted.
+func GenerateToken() (token, tokenHash string, err error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = hex.EncodeToString(bytes)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the storage hash of a token produced by GenerateToken
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,53 @@
+// backend/pkg/auth/websocket_ticket.go
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WebSocketTicketTTL is how long a WebSocket ticket can be redeemed. Clients
+// request a ticket right before opening the connection.
+const WebSocketTicketTTL = 30 * time.Second
+
+// CreateWebSocketTicket issues a single-use ticket that authenticates one
+// WebSocket connection as the session's user. Only the hash is stored, and the
+// ticket is removed together with the session.
+func (sm *SessionManager) CreateWebSocketTicket(userID int, sessionID string) (string, time.Time, error) {
+	ticket, ticketHash, err := GenerateToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(WebSocketTicketTTL)
+	query := `INSERT INTO websocket_tickets (ticket_hash, user_id, session_id, expires_at) VALUES (?, ?, ?, ?)`
+	if _, err := sm.db.Exec(query, ticketHash, userID, sessionID, expiresAt); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create websocket ticket: %w", err)
+	}
+
+	return ticket, expiresAt, nil
+}
+
+// ConsumeWebSocketTicket redeems a ticket and returns its user. A ticket works
+// only once, even when two connections race to use it.
+func (sm *SessionManager) ConsumeWebSocketTicket(ticket string) (int, error) {
+	var userID int
+	query := `DELETE FROM websocket_tickets WHERE ticket_hash = ? AND expires_at > ? RETURNING user_id`
+	err := sm.db.QueryRow(query, HashToken(ticket), time.Now()).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("websocket ticket is invalid or expired")
+		}
+		return 0, fmt.Errorf("failed to consume websocket ticket: %w", err)
+	}
+	return userID, nil
+}
+
+// CleanupExpiredWebSocketTickets removes tickets that were never redeemed
+func (sm *SessionManager) CleanupExpiredWebSocketTickets() error {
+	if _, err := sm.db.Exec(`DELETE FROM websocket_tickets WHERE expires_at <= ?`, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup websocket tickets: %w", err)
+	}
+	return nil
+}
@@ -5,16 +5,91 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// RateLimit allows Requests per Period for each user or client IP. A zero
+// value disables the limit.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
 type Config struct {
 	DatabasePath   string
 	MigrationsPath string
 	ServerPort     string
 	SessionSecret  string
 	UploadsPath    string
-	AllowedOrigins []string
+	ExportsPath    string
 	MaxFileSize    int64
+
+	// Frontends allowed to call the API from a browser: exact origins such as
+	// "https://app.example.com" or subdomain patterns such as "https://*.example.com"
+	AllowedOrigins     []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSExposedHeaders []string
+	// How long browsers may cache a preflight response
+	CORSMaxAge time.Duration
+
+	// How long /readyz fails before shutdown begins, so load balancers drain traffic
+	ShutdownDrainDelay time.Duration
+
+	SessionIdleTimeout     time.Duration
+	SessionAbsoluteTimeout time.Duration
+
+	// Cost of argon2id password hashes; memory is in KiB
+	Argon2Memory      int64
+	Argon2Iterations  int64
+	Argon2Parallelism int64
+
+	// How long a deleted account can still be restored by logging in
+	AccountDeletionGracePeriod time.Duration
+	// How long the download link of a personal data export stays valid
+	DataExportLinkTTL time.Duration
+	// Notifications older than this many days are removed
+	NotificationRetentionDays int64
+
+	FrontendURL string
+	APIURL      string
+	MailDriver  string
+	MailFrom    string
+	MailDir     string
+
+	// Minimum level (debug, info, warn, error) and format (text, json) of log output
+	LogLevel  string
+	LogFormat string
+
+	// Request rate limits, written as "requests/period" (e.g. "10/1m")
+	RateLimitDefault  RateLimit // every API request, per client IP
+	RateLimitAuth     RateLimit // login, registration and password reset
+	RateLimitPosts    RateLimit // creating posts and comments
+	RateLimitMessages RateLimit // sending chat messages
+
+	// Accounts promoted to admin at startup, to bootstrap the first staff members
+	AdminEmails []string
+
+	// /metrics is served to these client IPs or CIDR ranges, and to requests
+	// carrying MetricsToken as a bearer token when one is set
+	MetricsAllowlist []string
+	MetricsToken     string
+
+	// Reject JSON request bodies that do not match the OpenAPI document
+	// before they reach the handlers
+	ValidateRequests bool
+
+	// Removal date of the unversioned /api/ aliases of the /api/v1/ routes,
+	// announced in their Sunset header; zero until one is planned
+	LegacyAPISunset time.Time
+
+	// OpenID Connect login; disabled when OIDCIssuerURL is empty
+	OIDCProviderName string
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
 }
 
 func LoadConfig() *Config {
@@ -24,10 +99,56 @@ func LoadConfig() *Config {
 		ServerPort:     getEnv("SERVER_PORT", "8000"),
 		SessionSecret:  getEnv("SESSION_SECRET", "your-super-secret-key-change-this"),
 		UploadsPath:    getEnv("UPLOADS_PATH", "./uploads"),
-		AllowedOrigins: []string{
-			getEnv("FRONTEND_URL", "http://localhost:3000"),
-		},
-		MaxFileSize: parseIntEnv("MAX_FILE_SIZE", 10<<20), // 10MB default
+		ExportsPath:    getEnv("EXPORTS_PATH", "./data/exports"),
+		MaxFileSize:    parseIntEnv("MAX_FILE_SIZE", 10<<20), // 10MB default
+
+		AllowedOrigins:     parseListEnv("CORS_ALLOWED_ORIGINS", getEnv("FRONTEND_URL", "http://localhost:3000")),
+		CORSAllowedMethods: parseListEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
+		CORSAllowedHeaders: parseListEnv("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Request-ID"),
+		CORSExposedHeaders: parseListEnv("CORS_EXPOSED_HEADERS",
+			"X-Request-ID,ETag,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset,Retry-After,Deprecation,Sunset,Link"),
+		CORSMaxAge: parseDurationEnv("CORS_MAX_AGE", 10*time.Minute),
+
+		ShutdownDrainDelay: parseDurationEnv("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+
+		SessionIdleTimeout:     parseDurationEnv("SESSION_IDLE_TIMEOUT", 7*24*time.Hour),
+		SessionAbsoluteTimeout: parseDurationEnv("SESSION_ABSOLUTE_TIMEOUT", 30*24*time.Hour),
+
+		Argon2Memory:      parseIntEnv("PASSWORD_ARGON2_MEMORY", 64*1024),
+		Argon2Iterations:  parseIntEnv("PASSWORD_ARGON2_ITERATIONS", 3),
+		Argon2Parallelism: parseIntEnv("PASSWORD_ARGON2_PARALLELISM", 2),
+
+		AccountDeletionGracePeriod: parseDurationEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		DataExportLinkTTL:          parseDurationEnv("DATA_EXPORT_LINK_TTL", 7*24*time.Hour),
+		NotificationRetentionDays:  parseIntEnv("NOTIFICATION_RETENTION_DAYS", 90),
+
+		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+		APIURL:      getEnv("API_URL", "http://localhost:8000"),
+		MailDriver:  getEnv("MAIL_DRIVER", "log"),
+		MailFrom:    getEnv("MAIL_FROM", "Ripple <no-reply@ripple.local>"),
+		MailDir:     getEnv("MAIL_DIR", "./data/mail"),
+
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		LogFormat: getEnv("LOG_FORMAT", "text"),
+
+		RateLimitDefault:  parseRateLimitEnv("RATE_LIMIT_DEFAULT", RateLimit{Requests: 300, Period: time.Minute}),
+		RateLimitAuth:     parseRateLimitEnv("RATE_LIMIT_AUTH", RateLimit{Requests: 10, Period: time.Minute}),
+		RateLimitPosts:    parseRateLimitEnv("RATE_LIMIT_POSTS", RateLimit{Requests: 10, Period: time.Minute}),
+		RateLimitMessages: parseRateLimitEnv("RATE_LIMIT_MESSAGES", RateLimit{Requests: 60, Period: time.Minute}),
+
+		AdminEmails: parseListEnv("ADMIN_EMAILS", ""),
+
+		MetricsAllowlist: parseListEnv("METRICS_ALLOWLIST", "127.0.0.1,::1"),
+		MetricsToken:     getEnv("METRICS_TOKEN", ""),
+
+		ValidateRequests: parseBoolEnv("OPENAPI_VALIDATE_REQUESTS", false),
+		LegacyAPISunset:  parseDateEnv("LEGACY_API_SUNSET"),
+
+		OIDCProviderName: getEnv("OIDC_PROVIDER_NAME", "oidc"),
+		OIDCIssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8000/api/v1/auth/oidc/callback"),
 	}
 
 	// Create uploads directory if it doesn't exist
@@ -62,3 +183,70 @@ func parseIntEnv(key string, defaultValue int64) int64 {
 	}
 	return defaultValue
 }
+
+func parseDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid duration for %s: %q, using default %v", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func parseBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid boolean for %s: %q, using default %v", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// parseDateEnv reads a date written as YYYY-MM-DD, in UTC
+func parseDateEnv(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid date for %s: %q, ignoring it", key, value)
+	}
+	return time.Time{}
+}
+
+// parseListEnv reads a comma-separated list, ignoring empty entries
+func parseListEnv(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseRateLimitEnv reads a limit written as "requests/period", e.g. "10/1m".
+// "0" or "off" disables the limit.
+func parseRateLimitEnv(key string, defaultValue RateLimit) RateLimit {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	if value == "0" || value == "off" {
+		return RateLimit{}
+	}
+
+	requests, period, found := strings.Cut(value, "/")
+	parsedRequests, err := strconv.Atoi(requests)
+	if !found || err != nil || parsedRequests < 0 {
+		log.Printf("Warning: invalid rate limit for %s: %q, using default", key, value)
+		return defaultValue
+	}
+	parsedPeriod, err := time.ParseDuration(period)
+	if err != nil || parsedPeriod <= 0 {
+		log.Printf("Warning: invalid rate limit for %s: %q, using default", key, value)
+		return defaultValue
+	}
+	return RateLimit{Requests: parsedRequests, Period: parsedPeriod}
+}
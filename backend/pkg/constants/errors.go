@@ -8,6 +8,25 @@ const (
 	ErrEmailExists       = "email already exists"
 	ErrUnauthorized      = "unauthorized access"
 	ErrSessionExpired    = "session expired"
+	ErrSessionNotFound   = "session not found"
+	ErrInvalidCurrentPassword = "current password is incorrect"
+	ErrInvalidResetToken      = "reset token is invalid or expired"
+	ErrInvalidTwoFactorCode      = "invalid two-factor code"
+	ErrInvalidTwoFactorChallenge = "two-factor challenge is invalid or expired"
+	ErrTwoFactorAlreadyEnabled   = "two-factor authentication is already enabled"
+	ErrEmailNotVerified          = "please verify your email address first"
+	ErrEmailAlreadyVerified      = "email address is already verified"
+	ErrInvalidVerificationToken  = "verification token is invalid or expired"
+	ErrTooManyLoginAttempts      = "too many failed login attempts, please try again later"
+	ErrAccessTokenNotFound       = "access token not found"
+	ErrInsufficientScope         = "access token does not have the required scope"
+	ErrIdentityEmailUnverified   = "an account with this email exists and the provider did not verify the email"
+	ErrIdentityAccountUnverified = "an account with this email exists and has not verified its email"
+	ErrDataExportPending         = "a data export is already being prepared"
+	ErrDataExportNotFound        = "data export not found or link expired"
+	ErrCrossOriginRequest        = "cross-origin request blocked"
+	ErrAccountSuspended          = "this account has been suspended"
+	ErrRateLimited               = "too many requests, please slow down"
 
 	// Validation errors
 	ErrInvalidEmail      = "invalid email format"
@@ -15,6 +34,8 @@ const (
 	ErrRequiredField     = "this field is required"
 	ErrInvalidFileType   = "file type not supported"
 	ErrFileTooLarge      = "file size exceeds limit"
+	ErrFileTypeMismatch  = "file content does not match its type"
+	ErrUnsafeFileContent = "file contains markup or an archive and cannot be uploaded"
 
 	// Business logic errors
 	ErrCannotFollowSelf    = "cannot follow yourself"
@@ -23,4 +44,20 @@ const (
 	ErrCannotMessageUser   = "cannot send message to this user"
 	ErrNotGroupMember      = "not a member of this group"
 	ErrInsufficientPermissions = "insufficient permissions"
+	ErrInvalidRole             = "invalid role"
+	ErrCannotModerateUser      = "you cannot perform this action on this user"
+	ErrInvalidContentType      = "invalid content type"
+	ErrContentNotFound         = "content not found"
+)
+
+const (
+	// Machine-readable error codes returned in APIError.Code
+	CodeEmailNotVerified     = "EMAIL_NOT_VERIFIED"
+	CodeAccountLocked        = "ACCOUNT_LOCKED"
+	CodeTooManyLoginAttempts = "TOO_MANY_LOGIN_ATTEMPTS"
+	CodeInsufficientScope    = "INSUFFICIENT_SCOPE"
+	CodeCSRFCheckFailed      = "CSRF_CHECK_FAILED"
+	CodeAccountSuspended     = "ACCOUNT_SUSPENDED"
+	CodeInsufficientRole     = "INSUFFICIENT_ROLE"
+	CodeRateLimited          = "RATE_LIMITED"
 )
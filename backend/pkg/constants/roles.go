@@ -0,0 +1,9 @@
+// backend/pkg/constants/roles.go
+package constants
+
+const (
+	// Site-wide user roles, from least to most privileged
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
@@ -0,0 +1,21 @@
+-- backend/pkg/db/migrations/sqlite/000021_add_session_metadata.down.sql
+-- SQLite doesn't support DROP COLUMN, so we need to recreate the table
+CREATE TABLE sessions_backup AS SELECT
+    id, user_id, expires_at, created_at
+FROM sessions;
+
+DROP TABLE sessions;
+
+CREATE TABLE sessions (
+    id VARCHAR(128) PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO sessions SELECT * FROM sessions_backup;
+DROP TABLE sessions_backup;
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
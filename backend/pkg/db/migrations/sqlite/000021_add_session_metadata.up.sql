@@ -0,0 +1,6 @@
+-- backend/pkg/db/migrations/sqlite/000021_add_session_metadata.up.sql
+ALTER TABLE sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN ip_address VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN last_used_at DATETIME;
+
+UPDATE sessions SET last_used_at = created_at WHERE last_used_at IS NULL;
@@ -0,0 +1,26 @@
+-- backend/pkg/db/migrations/sqlite/000022_add_session_absolute_expiry.down.sql
+DROP INDEX IF EXISTS idx_sessions_absolute_expires_at;
+
+-- SQLite doesn't support DROP COLUMN, so we need to recreate the table
+CREATE TABLE sessions_backup AS SELECT
+    id, user_id, expires_at, created_at, user_agent, ip_address, last_used_at
+FROM sessions;
+
+DROP TABLE sessions;
+
+CREATE TABLE sessions (
+    id VARCHAR(128) PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    last_used_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO sessions SELECT * FROM sessions_backup;
+DROP TABLE sessions_backup;
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
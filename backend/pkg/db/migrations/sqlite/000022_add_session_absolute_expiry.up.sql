@@ -0,0 +1,8 @@
+-- backend/pkg/db/migrations/sqlite/000022_add_session_absolute_expiry.up.sql
+-- expires_at now slides forward while a session is in use (idle timeout);
+-- absolute_expires_at caps the total lifetime of a session.
+ALTER TABLE sessions ADD COLUMN absolute_expires_at DATETIME;
+
+UPDATE sessions SET absolute_expires_at = expires_at WHERE absolute_expires_at IS NULL;
+
+CREATE INDEX idx_sessions_absolute_expires_at ON sessions(absolute_expires_at);
@@ -0,0 +1,2 @@
+-- backend/pkg/db/migrations/sqlite/000023_create_password_reset_tokens_table.down.sql
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,4 @@
+-- backend/pkg/db/migrations/sqlite/000024_create_two_factor_tables.down.sql
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TABLE IF EXISTS totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,5 @@
+-- backend/pkg/db/migrations/sqlite/000025_add_email_verification.down.sql
+DROP TABLE IF EXISTS email_verification_tokens;
+
+-- Remove email_verified_at (Note: SQLite doesn't support DROP COLUMN directly)
+-- The column is left in place; it is ignored by older code.
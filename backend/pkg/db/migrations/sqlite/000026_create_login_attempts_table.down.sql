@@ -0,0 +1,3 @@
+-- backend/pkg/db/migrations/sqlite/000026_create_login_attempts_table.down.sql
+DROP INDEX IF EXISTS idx_login_attempts_last_failure_at;
+DROP TABLE IF EXISTS login_attempts;
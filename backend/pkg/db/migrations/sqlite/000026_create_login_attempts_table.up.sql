@@ -0,0 +1,10 @@
+-- backend/pkg/db/migrations/sqlite/000026_create_login_attempts_table.up.sql
+-- Failed login counters, keyed by "account:<email>" or "ip:<address>"
+CREATE TABLE login_attempts (
+    attempt_key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at DATETIME NOT NULL,
+    locked_until DATETIME
+);
+
+CREATE INDEX idx_login_attempts_last_failure_at ON login_attempts(last_failure_at);
@@ -0,0 +1,4 @@
+-- backend/pkg/db/migrations/sqlite/000027_create_personal_access_tokens_table.down.sql
+DROP INDEX IF EXISTS idx_personal_access_tokens_expires_at;
+DROP INDEX IF EXISTS idx_personal_access_tokens_user_id;
+DROP TABLE IF EXISTS personal_access_tokens;
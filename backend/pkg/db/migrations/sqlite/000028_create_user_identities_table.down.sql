@@ -0,0 +1,3 @@
+-- backend/pkg/db/migrations/sqlite/000028_create_user_identities_table.down.sql
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,5 @@
+-- backend/pkg/db/migrations/sqlite/000029_add_account_deletion.down.sql
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+
+-- Remove deletion_scheduled_at (Note: SQLite doesn't support DROP COLUMN directly)
+-- The column is left in place; it is ignored by older code.
@@ -0,0 +1,6 @@
+-- backend/pkg/db/migrations/sqlite/000029_add_account_deletion.up.sql
+-- Set when the user asks for their account to be deleted; the account is
+-- purged once this time has passed unless the user logs in again before then
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at);
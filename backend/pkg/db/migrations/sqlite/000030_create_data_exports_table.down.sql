@@ -0,0 +1,2 @@
+-- backend/pkg/db/migrations/sqlite/000030_create_data_exports_table.down.sql
+DROP TABLE IF EXISTS data_exports;
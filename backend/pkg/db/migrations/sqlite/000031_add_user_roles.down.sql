@@ -0,0 +1,5 @@
+-- backend/pkg/db/migrations/sqlite/000031_add_user_roles.down.sql
+DROP INDEX IF EXISTS idx_users_role;
+
+-- Remove role, suspended_at and suspension_reason (Note: SQLite doesn't support DROP COLUMN directly)
+-- The columns are left in place; they are ignored by older code.
@@ -0,0 +1,8 @@
+-- backend/pkg/db/migrations/sqlite/000031_add_user_roles.up.sql
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Suspended users cannot log in until a moderator lifts the suspension
+ALTER TABLE users ADD COLUMN suspended_at DATETIME;
+ALTER TABLE users ADD COLUMN suspension_reason TEXT;
+
+CREATE INDEX idx_users_role ON users(role);
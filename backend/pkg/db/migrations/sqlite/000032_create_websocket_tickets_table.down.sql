@@ -0,0 +1,2 @@
+-- backend/pkg/db/migrations/sqlite/000032_create_websocket_tickets_table.down.sql
+DROP TABLE IF EXISTS websocket_tickets;
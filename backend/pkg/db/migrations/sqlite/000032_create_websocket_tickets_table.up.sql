@@ -0,0 +1,15 @@
+-- backend/pkg/db/migrations/sqlite/000032_create_websocket_tickets_table.up.sql
+-- Single-use tickets that authenticate a WebSocket connection without putting
+-- the session ID in the URL. A ticket dies with the session that issued it.
+CREATE TABLE websocket_tickets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_hash VARCHAR(64) UNIQUE NOT NULL,
+    user_id INTEGER NOT NULL,
+    session_id VARCHAR(128) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_websocket_tickets_expires_at ON websocket_tickets(expires_at);
@@ -0,0 +1,3 @@
+-- backend/pkg/db/migrations/sqlite/000033_remove_data_export_tokens.down.sql
+-- Revoked download tokens cannot be restored; nothing to undo.
+SELECT 1;
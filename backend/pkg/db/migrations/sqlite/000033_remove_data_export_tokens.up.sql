@@ -0,0 +1,9 @@
+-- backend/pkg/db/migrations/sqlite/000033_remove_data_export_tokens.up.sql
+-- Exports are downloaded by ID by their signed-in owner; the download tokens
+-- were also stored in plain text in the notifications, so they are revoked and
+-- removed from the links. The token_hash column is left in place.
+UPDATE data_exports SET token_hash = NULL;
+
+UPDATE notifications
+SET message = substr(message, 1, instr(message, '?token=') - 1) || '?id=' || related_id
+WHERE type = 'data_export_ready' AND instr(message, '?token=') > 0;
@@ -0,0 +1,152 @@
+// backend/pkg/handlers/access_token.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+const (
+	defaultAccessTokenDays = 30
+	maxAccessTokenDays     = 365
+	maxAccessTokenName     = 100
+)
+
+// GetAccessTokens lists the current user's personal access tokens
+func (ah *AuthHandler) GetAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	tokens, err := ah.sessionManager.GetUserAccessTokens(userID)
+	if err != nil {
+		log.Printf("GetAccessTokens failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	tokenResponses := make([]*models.AccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		tokenResponses = append(tokenResponses, token.ToResponse())
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]any{
+		"tokens":           tokenResponses,
+		"count":            len(tokenResponses),
+		"available_scopes": auth.AvailableScopes,
+	})
+}
+
+// CreateAccessToken issues a new personal access token. The token itself is
+// only returned in this response.
+func (ah *AuthHandler) CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	scopes, errors := validateCreateAccessTokenRequest(&req)
+	if errors.HasErrors() {
+		utils.WriteValidationErrorResponse(w, errors)
+		return
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+	token, accessToken, err := ah.sessionManager.CreateAccessToken(userID, strings.TrimSpace(req.Name), scopes, expiresAt)
+	if err != nil {
+		log.Printf("CreateAccessToken failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Access token %d created for user ID %d with scopes %v", accessToken.ID, userID, scopes)
+	utils.WriteSuccessResponse(w, http.StatusCreated, map[string]any{
+		"token":        token,
+		"access_token": accessToken.ToResponse(),
+		"message":      "Store this token now, it will not be shown again",
+	})
+}
+
+// RevokeAccessToken deletes one of the current user's personal access tokens
+func (ah *AuthHandler) RevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	tokenID, ok := pathID(w, r, "id", "token")
+	if !ok {
+		return
+	}
+
+	if err := ah.sessionManager.RevokeAccessToken(userID, tokenID); err != nil {
+		if strings.Contains(err.Error(), constants.ErrAccessTokenNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Access token not found")
+			return
+		}
+		log.Printf("RevokeAccessToken failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Access token %d revoked for user ID %d", tokenID, userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Access token revoked successfully",
+	})
+}
+
+// validateCreateAccessTokenRequest checks the request, fills in the default
+// expiry and returns the de-duplicated scopes.
+func validateCreateAccessTokenRequest(req *models.CreateAccessTokenRequest) ([]string, utils.ValidationErrors) {
+	var errors utils.ValidationErrors
+
+	if err := utils.ValidateRequired(req.Name, "name"); err != nil {
+		errors = append(errors, *err)
+	} else if err := utils.ValidateOptionalString(strings.TrimSpace(req.Name), maxAccessTokenName, "name"); err != nil {
+		errors = append(errors, *err)
+	}
+
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !auth.IsValidScope(scope) {
+			errors = append(errors, utils.ValidationError{Field: "scopes", Message: fmt.Sprintf("unknown scope %q", scope)})
+			continue
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(req.Scopes) == 0 {
+		errors = append(errors, utils.ValidationError{Field: "scopes", Message: "at least one scope is required"})
+	}
+
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = defaultAccessTokenDays
+	}
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > maxAccessTokenDays {
+		errors = append(errors, utils.ValidationError{Field: "expires_in_days", Message: fmt.Sprintf("must be between 1 and %d", maxAccessTokenDays)})
+	}
+
+	return scopes, errors
+}
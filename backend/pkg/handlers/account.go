@@ -0,0 +1,162 @@
+// backend/pkg/handlers/account.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+// AccountHandler handles account deletion. A deletion request signs the user out
+// everywhere and hides the profile; the data is purged once the grace period is
+// over unless the user logs in again before then.
+type AccountHandler struct {
+	userRepo       *models.UserRepository
+	deletionRepo   *models.AccountDeletionRepository
+	sessionManager *auth.SessionManager
+	mailer         mail.Mailer
+	uploadsPath    string
+	gracePeriod    time.Duration
+}
+
+func NewAccountHandler(userRepo *models.UserRepository, deletionRepo *models.AccountDeletionRepository, sessionManager *auth.SessionManager, mailer mail.Mailer, uploadsPath string, gracePeriod time.Duration) *AccountHandler {
+	return &AccountHandler{
+		userRepo:       userRepo,
+		deletionRepo:   deletionRepo,
+		sessionManager: sessionManager,
+		mailer:         mailer,
+		uploadsPath:    uploadsPath,
+		gracePeriod:    gracePeriod,
+	}
+}
+
+// DeleteAccountRequest confirms a deletion with the account password. Accounts
+// created by an OIDC login have a random password nobody knows; their owners
+// set one with the password reset flow (POST /api/v1/auth/password/forgot)
+// before they can delete the account.
+type DeleteAccountRequest struct {
+	Password string `json:"password" openapi:"required,format=password"`
+}
+
+// RequestDeletion schedules the current user's account for deletion after checking their password
+func (ach *AccountHandler) RequestDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := utils.ValidateRequired(req.Password, "password"); err != nil {
+		utils.WriteValidationErrorResponse(w, utils.ValidationErrors{*err})
+		return
+	}
+
+	user, err := ach.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Printf("RequestDeletion - failed to get user %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := auth.CheckPassword(req.Password, user.PasswordHash); err != nil {
+		log.Printf("RequestDeletion failed - invalid password for user ID %d", userID)
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCurrentPassword)
+		return
+	}
+
+	purgeAt := time.Now().Add(ach.gracePeriod)
+	if err := ach.userRepo.RequestDeletion(userID, purgeAt); err != nil {
+		log.Printf("RequestDeletion - failed to schedule deletion for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	// Logging in again is how a deletion is cancelled, so nothing may stay signed in
+	if err := ach.sessionManager.DeleteUserSessions(userID); err != nil {
+		log.Printf("RequestDeletion - failed to revoke sessions for user ID %d: %v", userID, err)
+	}
+	if err := ach.sessionManager.DeleteUserAccessTokens(userID); err != nil {
+		log.Printf("RequestDeletion - failed to revoke access tokens for user ID %d: %v", userID, err)
+	}
+	auth.ClearSessionCookie(w)
+
+	sendMail(ach.mailer, &mail.Message{
+		To:      user.Email,
+		Subject: "Your Ripple account will be deleted",
+		Body: fmt.Sprintf("Your Ripple account and everything you shared will be permanently deleted on %s.\n\n"+
+			"Changed your mind? Log in before then and the deletion is cancelled.\n", purgeAt.UTC().Format("January 2, 2006 15:04 MST")),
+	})
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"message":      "Account scheduled for deletion",
+		"scheduled_at": purgeAt,
+	})
+	log.Printf("Account deletion requested for user ID %d, purge at %s", userID, purgeAt.Format(time.RFC3339))
+}
+
+// PurgeDueAccounts permanently deletes every account whose grace period has
+// ended, together with its uploaded files. It is meant to run periodically.
+func (ach *AccountHandler) PurgeDueAccounts() error {
+	userIDs, err := ach.deletionRepo.GetAccountsDueForPurge()
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		paths, err := ach.deletionRepo.PurgeAccount(userID)
+		if err != nil {
+			log.Printf("PurgeDueAccounts - failed to purge user ID %d: %v", userID, err)
+			continue
+		}
+
+		removed := 0
+		for _, path := range paths {
+			if err := utils.RemoveUploadedFile(ach.uploadsPath, path); err != nil {
+				log.Printf("PurgeDueAccounts - failed to remove %s of user ID %d: %v", path, userID, err)
+				continue
+			}
+			removed++
+		}
+		log.Printf("Purged account of user ID %d (%d files removed)", userID, removed)
+	}
+
+	return nil
+}
+
+// accountPurgeDue reports whether the user's deletion grace period is over.
+// Such accounts are treated as already gone until the purge job removes them.
+func accountPurgeDue(user *models.User) bool {
+	return user.IsPendingDeletion() && !user.DeletionScheduledAt.After(time.Now())
+}
+
+// cancelAccountDeletion withdraws a pending deletion request when the user logs in again
+func cancelAccountDeletion(userRepo *models.UserRepository, user *models.User) bool {
+	if !user.IsPendingDeletion() {
+		return false
+	}
+
+	cancelled, err := userRepo.CancelDeletion(user.ID)
+	if err != nil {
+		log.Printf("Failed to cancel account deletion for user ID %d: %v", user.ID, err)
+		return false
+	}
+	if cancelled {
+		user.DeletionScheduledAt = nil
+		log.Printf("Account deletion cancelled by login for user ID %d", user.ID)
+	}
+	return cancelled
+}
@@ -0,0 +1,335 @@
+// backend/pkg/handlers/admin.go
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/scheduler"
+	"ripple/pkg/utils"
+	"ripple/pkg/websocket"
+)
+
+// AdminHandler serves the moderation and admin API. Routes are protected by
+// SessionManager.RequireRole; staff can only act on accounts and content of
+// users below their own role, and never on themselves.
+type AdminHandler struct {
+	adminRepo      *models.AdminRepository
+	sessionManager *auth.SessionManager
+	hub            *websocket.Hub
+	scheduler      *scheduler.Scheduler
+	uploadsPath    string
+}
+
+func NewAdminHandler(adminRepo *models.AdminRepository, sessionManager *auth.SessionManager, hub *websocket.Hub, jobScheduler *scheduler.Scheduler, uploadsPath string) *AdminHandler {
+	return &AdminHandler{
+		adminRepo:      adminRepo,
+		sessionManager: sessionManager,
+		hub:            hub,
+		scheduler:      jobScheduler,
+		uploadsPath:    uploadsPath,
+	}
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" openapi:"required"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" openapi:"required,enum=user|moderator|admin"`
+}
+
+// ListUsers lists accounts, including suspended ones and those pending deletion
+func (adh *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	limit := 50
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	users, err := adh.adminRepo.ListUsers(query, limit, offset)
+	if err != nil {
+		log.Printf("Admin ListUsers - failed to list users: %v", err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if users == nil {
+		users = []*models.AdminUser{}
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"users":  users,
+		"limit":  limit,
+		"offset": offset,
+		"count":  len(users),
+	})
+}
+
+// SuspendUser blocks a user from logging in and signs them out everywhere
+func (adh *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := pathID(w, r, "id", "user")
+	if !ok {
+		return
+	}
+	actorID, ok := adh.authorizeTarget(w, r, targetID)
+	if !ok {
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := utils.ValidateRequired(req.Reason, "reason"); err != nil {
+		utils.WriteValidationErrorResponse(w, utils.ValidationErrors{*err})
+		return
+	}
+
+	if err := adh.adminRepo.SuspendUser(targetID, req.Reason); err != nil {
+		log.Printf("Admin SuspendUser - failed to suspend user ID %d: %v", targetID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	adh.revokeAccess(targetID)
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "User suspended",
+	})
+	log.Printf("Admin action: user ID %d suspended user ID %d (reason: %s)", actorID, targetID, req.Reason)
+}
+
+// UnsuspendUser lifts a suspension
+func (adh *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := pathID(w, r, "id", "user")
+	if !ok {
+		return
+	}
+	actorID, ok := adh.authorizeTarget(w, r, targetID)
+	if !ok {
+		return
+	}
+
+	unsuspended, err := adh.adminRepo.UnsuspendUser(targetID)
+	if err != nil {
+		log.Printf("Admin UnsuspendUser - failed to unsuspend user ID %d: %v", targetID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if !unsuspended {
+		utils.WriteErrorResponse(w, http.StatusConflict, "User is not suspended")
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "User unsuspended",
+	})
+	log.Printf("Admin action: user ID %d unsuspended user ID %d", actorID, targetID)
+}
+
+// ForceLogout revokes every session and access token of a user and closes their WebSocket
+func (adh *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := pathID(w, r, "id", "user")
+	if !ok {
+		return
+	}
+	actorID, ok := adh.authorizeTarget(w, r, targetID)
+	if !ok {
+		return
+	}
+
+	adh.revokeAccess(targetID)
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "User signed out everywhere",
+	})
+	log.Printf("Admin action: user ID %d forced logout of user ID %d", actorID, targetID)
+}
+
+// SetRole changes a user's site-wide role. Staff cannot grant a role above their own.
+func (adh *AdminHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := pathID(w, r, "id", "user")
+	if !ok {
+		return
+	}
+	actorID, ok := adh.authorizeTarget(w, r, targetID)
+	if !ok {
+		return
+	}
+
+	var req SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+	if !auth.IsValidRole(req.Role) {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidRole)
+		return
+	}
+	actorRole, _ := auth.GetRoleFromContext(r.Context())
+	if !auth.RoleAtLeast(actorRole, req.Role) {
+		utils.WriteErrorResponse(w, http.StatusForbidden, constants.ErrInsufficientPermissions)
+		return
+	}
+
+	if err := adh.adminRepo.SetRole(targetID, req.Role); err != nil {
+		log.Printf("Admin SetRole - failed to set role of user ID %d: %v", targetID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Role updated",
+		"role":    req.Role,
+	})
+	log.Printf("Admin action: user ID %d set role of user ID %d to %s", actorID, targetID, req.Role)
+}
+
+// DeleteContent removes a post or comment together with uploads nothing else uses
+func (adh *AdminHandler) DeleteContent(w http.ResponseWriter, r *http.Request) {
+	actorID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	actorRole, _ := auth.GetRoleFromContext(r.Context())
+
+	contentType := r.PathValue("type")
+	if !models.IsModeratedContentType(contentType) {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidContentType)
+		return
+	}
+	contentID, ok := pathID(w, r, "id", "content")
+	if !ok {
+		return
+	}
+
+	authorID, authorRole, err := adh.adminRepo.GetContentAuthor(contentType, contentID)
+	if err != nil {
+		if strings.Contains(err.Error(), constants.ErrContentNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, constants.ErrContentNotFound)
+			return
+		}
+		log.Printf("Admin DeleteContent - failed to get author of %s %d: %v", contentType, contentID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if authorID != actorID && !auth.RoleOutranks(actorRole, authorRole) {
+		utils.WriteErrorResponse(w, http.StatusForbidden, constants.ErrCannotModerateUser)
+		return
+	}
+
+	paths, err := adh.adminRepo.DeleteContent(contentType, contentID)
+	if err != nil {
+		if strings.Contains(err.Error(), constants.ErrContentNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, constants.ErrContentNotFound)
+			return
+		}
+		log.Printf("Admin DeleteContent - failed to delete %s %d: %v", contentType, contentID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	for _, path := range paths {
+		if err := utils.RemoveUploadedFile(adh.uploadsPath, path); err != nil {
+			log.Printf("Admin DeleteContent - failed to remove %s: %v", path, err)
+		}
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Content deleted",
+	})
+	log.Printf("Admin action: user ID %d deleted %s %d of user ID %d", actorID, contentType, contentID, authorID)
+}
+
+// GetStats returns site-wide counts
+func (adh *AdminHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := adh.adminRepo.GetStats()
+	if err != nil {
+		log.Printf("Admin GetStats - failed to get stats: %v", err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, stats)
+}
+
+// GetJobs returns the last-run status of every background job
+func (adh *AdminHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := []scheduler.JobStatus{}
+	if adh.scheduler != nil {
+		jobs = adh.scheduler.Status()
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"jobs": jobs,
+	})
+}
+
+// authorizeTarget checks that the acting staff member may act on the target
+// user and writes the error response if not
+func (adh *AdminHandler) authorizeTarget(w http.ResponseWriter, r *http.Request, targetID int) (int, bool) {
+	actorID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return 0, false
+	}
+	actorRole, err := auth.GetRoleFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusForbidden, constants.ErrInsufficientPermissions)
+		return 0, false
+	}
+
+	if targetID == actorID {
+		utils.WriteErrorResponse(w, http.StatusForbidden, constants.ErrCannotModerateUser)
+		return 0, false
+	}
+
+	targetRole, err := adh.adminRepo.GetUserRole(targetID)
+	if err != nil {
+		if strings.Contains(err.Error(), constants.ErrUserNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, constants.ErrUserNotFound)
+			return 0, false
+		}
+		log.Printf("Admin - failed to get role of user ID %d: %v", targetID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return 0, false
+	}
+	if !auth.RoleOutranks(actorRole, targetRole) {
+		log.Printf("Admin - user ID %d (%s) denied action on user ID %d (%s)", actorID, actorRole, targetID, targetRole)
+		utils.WriteErrorResponse(w, http.StatusForbidden, constants.ErrCannotModerateUser)
+		return 0, false
+	}
+
+	return actorID, true
+}
+
+// revokeAccess signs the user out of every session, revokes their access tokens
+// and closes their WebSocket connection
+func (adh *AdminHandler) revokeAccess(userID int) {
+	if err := adh.sessionManager.DeleteUserSessions(userID); err != nil {
+		log.Printf("Admin - failed to revoke sessions for user ID %d: %v", userID, err)
+	}
+	if err := adh.sessionManager.DeleteUserAccessTokens(userID); err != nil {
+		log.Printf("Admin - failed to revoke access tokens for user ID %d: %v", userID, err)
+	}
+	if adh.hub != nil {
+		adh.hub.DisconnectUser(userID)
+	}
+}
@@ -11,39 +11,70 @@ import (
 
 	"ripple/pkg/auth"
 	"ripple/pkg/constants"
+	"ripple/pkg/logging"
+	"ripple/pkg/mail"
 	"ripple/pkg/models"
 	"ripple/pkg/utils"
 )
 
 type AuthHandler struct {
-	userRepo       *models.UserRepository
-	followRepo     *models.FollowRepository
-	postRepo       *models.PostRepository
-	sessionManager *auth.SessionManager
+	userRepo         *models.UserRepository
+	followRepo       *models.FollowRepository
+	postRepo         *models.PostRepository
+	twoFactorRepo    *models.TwoFactorRepository
+	verificationRepo *models.EmailVerificationRepository
+	loginAttemptRepo *models.LoginAttemptRepository
+	sessionManager   *auth.SessionManager
+	mailer           mail.Mailer
+	frontendURL      string
 }
 
-func NewAuthHandler(userRepo *models.UserRepository, followRepo *models.FollowRepository, postRepo *models.PostRepository, sessionManager *auth.SessionManager) *AuthHandler {
+func NewAuthHandler(userRepo *models.UserRepository, followRepo *models.FollowRepository, postRepo *models.PostRepository, twoFactorRepo *models.TwoFactorRepository, verificationRepo *models.EmailVerificationRepository, loginAttemptRepo *models.LoginAttemptRepository, sessionManager *auth.SessionManager, mailer mail.Mailer, frontendURL string) *AuthHandler {
 	return &AuthHandler{
-		userRepo:       userRepo,
-		followRepo:     followRepo,
-		postRepo:       postRepo,
-		sessionManager: sessionManager,
+		userRepo:         userRepo,
+		followRepo:       followRepo,
+		postRepo:         postRepo,
+		twoFactorRepo:    twoFactorRepo,
+		verificationRepo: verificationRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		sessionManager:   sessionManager,
+		mailer:           mailer,
+		frontendURL:      strings.TrimRight(frontendURL, "/"),
 	}
 }
 
 type RegisterRequest struct {
-	Email       string  `json:"email"`
-	Password    string  `json:"password"`
-	FirstName   string  `json:"first_name"`
-	LastName    string  `json:"last_name"`
-	DateOfBirth string  `json:"date_of_birth"`
+	Email       string  `json:"email" openapi:"required,format=email"`
+	Password    string  `json:"password" openapi:"required,format=password"`
+	FirstName   string  `json:"first_name" openapi:"required"`
+	LastName    string  `json:"last_name" openapi:"required"`
+	DateOfBirth string  `json:"date_of_birth" openapi:"required,format=date"`
 	Nickname    *string `json:"nickname"`
 	AboutMe     *string `json:"about_me"`
 }
 
+// UpdateProfileRequest lists the fields UpdateProfile accepts. Fields left
+// out of the request keep their current value.
+type UpdateProfileRequest struct {
+	FirstName  *string `json:"first_name,omitempty"`
+	LastName   *string `json:"last_name,omitempty"`
+	Nickname   *string `json:"nickname,omitempty"`
+	AboutMe    *string `json:"about_me,omitempty"`
+	IsPublic   *bool   `json:"is_public,omitempty"`
+	AvatarPath *string `json:"avatar_path,omitempty"`
+	CoverPath  *string `json:"cover_path,omitempty"`
+}
+
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" openapi:"required,format=email"`
+	Password string `json:"password" openapi:"required,format=password"`
+}
+
+// SearchUserResponse is a user search result with the follow status of the searcher
+type SearchUserResponse struct {
+	*models.UserResponse
+	IsFollowing  bool   `json:"is_following"`
+	FollowStatus string `json:"follow_status"`
 }
 
 type AuthResponse struct {
@@ -52,11 +83,6 @@ type AuthResponse struct {
 }
 
 func (ah *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("Registration JSON decode error: %v", err)
@@ -115,16 +141,21 @@ func (ah *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("User created successfully: ID=%d, Email=%s", user.ID, user.Email)
 
+	// Account stays restricted until the emailed link is followed
+	if err := ah.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email for user ID %d: %v", user.ID, err)
+	}
+
 	// Create session
-	session, err := ah.sessionManager.CreateSession(user.ID)
+	session, err := ah.sessionManager.CreateSession(user.ID, r.UserAgent(), utils.GetClientIP(r))
 	if err != nil {
 		log.Printf("Session creation failed for user ID %d: %v", user.ID, err)
 		utils.WriteInternalErrorResponse(w, err)
 		return
 	}
 
-	log.Printf("Session created successfully: ID=%s, UserID=%d, Expires=%v",
-		session.ID, session.UserID, session.ExpiresAt)
+	log.Printf("Session created successfully: Session=%s, UserID=%d, Expires=%v",
+		logging.Fingerprint(session.ID), session.UserID, session.ExpiresAt)
 
 	// Set session cookie
 	ah.setSessionCookie(w, session.ID, session.ExpiresAt)
@@ -141,11 +172,6 @@ func (ah *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ah *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("Login JSON decode error: %v", err)
@@ -163,12 +189,23 @@ func (ah *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	clientIP := utils.GetClientIP(r)
+
+	// Refuse locked accounts and clients before looking at the password
+	if ah.checkLoginLockout(w, email, clientIP) {
+		log.Printf("Login rejected - locked out: %s", req.Email)
+		return
+	}
+
 	// Get user by email
-	user, err := ah.userRepo.GetUserByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	user, err := ah.userRepo.GetUserByEmail(email)
 	if err != nil {
 		if strings.Contains(err.Error(), constants.ErrUserNotFound) {
 			log.Printf("Login failed - user not found: %s", req.Email)
-			utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCredentials)
+			if !ah.recordLoginFailure(w, email, clientIP, nil) {
+				utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCredentials)
+			}
 			return
 		}
 		log.Printf("Error retrieving user %s: %v", req.Email, err)
@@ -176,22 +213,69 @@ func (ah *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Accounts past their deletion grace period are only waiting for the purge job
+	if accountPurgeDue(user) {
+		log.Printf("Login failed - account pending purge: %s", req.Email)
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCredentials)
+		return
+	}
+
 	// Check password
 	if err := auth.CheckPassword(req.Password, user.PasswordHash); err != nil {
 		log.Printf("Login failed - invalid password for %s", req.Email)
-		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCredentials)
+		if !ah.recordLoginFailure(w, email, clientIP, user) {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCredentials)
+		}
+		return
+	}
+	ah.clearLoginFailures(email)
+	ah.upgradePasswordHash(user, req.Password)
+
+	if user.IsSuspended() {
+		log.Printf("Login rejected - account suspended: user ID %d", user.ID)
+		utils.WriteErrorResponseWithCode(w, http.StatusForbidden, constants.ErrAccountSuspended, constants.CodeAccountSuspended)
 		return
 	}
 
+	// Accounts with two-factor authentication only get a session after the second factor
+	twoFactorEnabled, err := ah.twoFactorRepo.IsEnabled(user.ID)
+	if err != nil {
+		log.Printf("Login - failed to check two-factor status for user ID %d: %v", user.ID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if twoFactorEnabled {
+		ah.startTwoFactorChallenge(w, user)
+		return
+	}
+
+	ah.completeLogin(w, r, user, "Login successful")
+}
+
+// completeLogin creates a session for an authenticated user, sets the session
+// cookie and writes the login response.
+func (ah *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, user *models.User, message string) {
+	// Never reuse a session ID that existed before authentication
+	if cookie, err := r.Cookie(auth.SessionCookieName); err == nil && cookie.Value != "" {
+		if err := ah.sessionManager.DeleteSession(cookie.Value); err != nil {
+			log.Printf("Failed to discard previous session during login for user ID %d: %v", user.ID, err)
+		}
+	}
+
 	// Create session
-	session, err := ah.sessionManager.CreateSession(user.ID)
+	session, err := ah.sessionManager.CreateSession(user.ID, r.UserAgent(), utils.GetClientIP(r))
 	if err != nil {
 		log.Printf("Session creation failed during login for user ID %d: %v", user.ID, err)
 		utils.WriteInternalErrorResponse(w, err)
 		return
 	}
 
-	log.Printf("Login session created: ID=%s, UserID=%d", session.ID, session.UserID)
+	log.Printf("Login session created: Session=%s, UserID=%d", logging.Fingerprint(session.ID), session.UserID)
+
+	// Logging in during the grace period keeps the account
+	if cancelAccountDeletion(ah.userRepo, user) {
+		message += ". Your account deletion request was cancelled"
+	}
 
 	// Set session cookie
 	ah.setSessionCookie(w, session.ID, session.ExpiresAt)
@@ -199,19 +283,34 @@ func (ah *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Return success response
 	response := &AuthResponse{
 		User:    user.ToResponse(),
-		Message: "Login successful",
+		Message: message,
 	}
 
 	utils.WriteSuccessResponse(w, http.StatusOK, response)
 	log.Printf("Login completed successfully for user ID %d", user.ID)
 }
 
-func (ah *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+// upgradePasswordHash stores a fresh hash when the user's hash uses an older
+// algorithm or weaker parameters. Failures are logged; the old hash keeps working.
+func (ah *AuthHandler) upgradePasswordHash(user *models.User, password string) {
+	if !auth.NeedsRehash(user.PasswordHash) {
 		return
 	}
 
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Printf("Login - failed to rehash password for user ID %d: %v", user.ID, err)
+		return
+	}
+	if err := ah.userRepo.UpdatePassword(user.ID, passwordHash); err != nil {
+		log.Printf("Login - failed to store upgraded password hash for user ID %d: %v", user.ID, err)
+		return
+	}
+	user.PasswordHash = passwordHash
+	log.Printf("Upgraded password hash for user ID %d", user.ID)
+}
+
+func (ah *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Get session cookie
 	cookie, err := r.Cookie("session_id")
 	if err != nil {
@@ -220,11 +319,11 @@ func (ah *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Logout attempt for session: %s", cookie.Value)
+	log.Printf("Logout attempt for session: %s", logging.Fingerprint(cookie.Value))
 
 	// Delete session from database
 	if err := ah.sessionManager.DeleteSession(cookie.Value); err != nil {
-		log.Printf("Failed to delete session %s: %v", cookie.Value, err)
+		log.Printf("Failed to delete session %s: %v", logging.Fingerprint(cookie.Value), err)
 		utils.WriteInternalErrorResponse(w, err)
 		return
 	}
@@ -235,15 +334,10 @@ func (ah *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
 		"message": "Logout successful",
 	})
-	log.Printf("Logout completed for session: %s", cookie.Value)
+	log.Printf("Logout completed for session: %s", logging.Fingerprint(cookie.Value))
 }
 
 func (ah *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	// Get user ID from context (set by auth middleware)
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
@@ -296,11 +390,6 @@ func (ah *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ah *AuthHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	// Get current user ID from context
 	currentUserID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
@@ -309,16 +398,8 @@ func (ah *AuthHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get target user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	targetUserID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	targetUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -337,6 +418,13 @@ func (ah *AuthHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Accounts scheduled for deletion are hidden as if they were already gone
+	if user.IsPendingDeletion() {
+		log.Printf("GetUserProfile failed - user pending deletion: %d", targetUserID)
+		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+
 	// Get follow stats
 	followStats, err := ah.followRepo.GetFollowStats(targetUserID)
 	if err != nil {
@@ -378,11 +466,6 @@ func (ah *AuthHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 func (ah *AuthHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	// Get user ID from context
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
@@ -504,41 +587,23 @@ func (ah *AuthHandler) validateLoginRequest(req *LoginRequest) utils.ValidationE
 }
 
 func (ah *AuthHandler) setSessionCookie(w http.ResponseWriter, sessionID string, expiresAt time.Time) {
-	cookie := &http.Cookie{
-		Name:     "session_id",
-		Value:    sessionID,
-		Expires:  expiresAt,
-		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
-		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
-	}
-	http.SetCookie(w, cookie)
-	log.Printf("Session cookie set: Name=%s, Value=%s, Expires=%v",
-		cookie.Name, sessionID, cookie.Expires)
+	auth.SetSessionCookie(w, sessionID, expiresAt)
+	log.Printf("Session cookie set: Expires=%v", expiresAt)
 }
 
 func (ah *AuthHandler) clearSessionCookie(w http.ResponseWriter) {
-	cookie := &http.Cookie{
-		Name:     "session_id",
-		Value:    "",
-		Expires:  time.Unix(0, 0),
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
-	}
-	http.SetCookie(w, cookie)
+	auth.ClearSessionCookie(w)
 	log.Printf("Session cookie cleared")
 }
 
+// rotateSession issues a new ID for the session making the request and sends
+// it to the client. It is used after security-sensitive account changes.
+func (ah *AuthHandler) rotateSession(w http.ResponseWriter, r *http.Request) error {
+	return rotateRequestSession(w, r, ah.sessionManager)
+}
+
 // SearchUsers searches for users by name or email
 func (ah *AuthHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -576,12 +641,6 @@ func (ah *AuthHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert to response format with follow status
-	type SearchUserResponse struct {
-		*models.UserResponse
-		IsFollowing  bool   `json:"is_following"`
-		FollowStatus string `json:"follow_status"`
-	}
-
 	var userResponses []*SearchUserResponse
 	for _, user := range users {
 		if user.ID != userID { // Exclude current user from search results
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"ripple/pkg/auth"
 	"ripple/pkg/models"
@@ -13,6 +12,13 @@ import (
 	"ripple/pkg/websocket"
 )
 
+// TypingIndicatorRequest announces that the user started or stopped typing
+type TypingIndicatorRequest struct {
+	Type     string `json:"type" openapi:"required,enum=private|group"`
+	TargetID int    `json:"target_id" openapi:"required,minimum=1"` // user ID or group ID
+	IsTyping bool   `json:"is_typing"`                              // true when typing, false when stopped
+}
+
 type ChatHandler struct {
 	messageRepo *models.MessageRepository
 	followRepo  *models.FollowRepository
@@ -33,27 +39,14 @@ func NewChatHandler(messageRepo *models.MessageRepository, followRepo *models.Fo
 
 // GetPrivateMessages gets message history between two users
 func (ch *ChatHandler) GetPrivateMessages(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get other user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 6 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Other user ID required")
-		return
-	}
-
-	otherUserID, err := strconv.Atoi(pathParts[5])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	otherUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -109,27 +102,14 @@ func (ch *ChatHandler) GetPrivateMessages(w http.ResponseWriter, r *http.Request
 
 // GetGroupMessages gets message history for a group
 func (ch *ChatHandler) GetGroupMessages(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 6 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[5])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -178,11 +158,6 @@ func (ch *ChatHandler) GetGroupMessages(w http.ResponseWriter, r *http.Request)
 
 // GetConversations gets list of conversations for current user
 func (ch *ChatHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -223,11 +198,6 @@ func (ch *ChatHandler) GetConversations(w http.ResponseWriter, r *http.Request)
 
 // GetOnlineUsers gets list of currently online friends (followers or following)
 func (ch *ChatHandler) GetOnlineUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -282,23 +252,13 @@ func (ch *ChatHandler) GetOnlineUsers(w http.ResponseWriter, r *http.Request) {
 
 // TypingIndicator handles typing indicator events
 func (ch *ChatHandler) TypingIndicator(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	var req struct {
-		Type     string `json:"type"`      // "private" or "group"
-		TargetID int    `json:"target_id"` // user ID or group ID
-		IsTyping bool   `json:"is_typing"` // true when typing, false when stopped
-	}
-
+	var req TypingIndicatorRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
 		return
@@ -343,11 +303,6 @@ func (ch *ChatHandler) TypingIndicator(w http.ResponseWriter, r *http.Request) {
 
 // GetUnreadCounts gets unread message counts for user
 func (ch *ChatHandler) GetUnreadCounts(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -366,11 +321,6 @@ func (ch *ChatHandler) GetUnreadCounts(w http.ResponseWriter, r *http.Request) {
 
 // GetFollowedUsers gets a list of users that the current user follows for starting new chats.
 func (ch *ChatHandler) GetFollowedUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -392,11 +342,6 @@ func (ch *ChatHandler) GetFollowedUsers(w http.ResponseWriter, r *http.Request)
 
 // CreatePrivateMessage creates a new private message
 func (ch *ChatHandler) CreatePrivateMessage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -464,11 +409,6 @@ func (ch *ChatHandler) CreatePrivateMessage(w http.ResponseWriter, r *http.Reque
 
 // CreateGroupMessage creates a new group message
 func (ch *ChatHandler) CreateGroupMessage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
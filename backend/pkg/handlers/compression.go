@@ -0,0 +1,257 @@
+// backend/pkg/handlers/compression.go
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressionMinSize is the smallest response worth compressing; below it the
+// encoding overhead outweighs the savings
+const compressionMinSize = 1024
+
+// compressibleTypes are the content types that are compressed. Images,
+// archives and other already compressed formats are sent as they are.
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"text/",
+}
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	// Level 4 keeps brotli close to gzip in speed for dynamic responses
+	brotliWriters = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(io.Discard, 4)
+	}}
+)
+
+// CompressionMiddleware compresses responses with brotli or gzip, whichever
+// the client prefers in Accept-Encoding, favouring brotli on a tie. Small
+// responses, responses without a body and incompressible content types are
+// sent uncompressed. WebSocket upgrades pass through untouched.
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Not deferred: after a panic the recovery middleware answers on w itself
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		next.ServeHTTP(cw, r)
+		cw.Close()
+	})
+}
+
+// negotiateEncoding picks "br" or "gzip" from an Accept-Encoding header, or ""
+// when neither is acceptable
+func negotiateEncoding(header string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = quality
+	}
+
+	// An explicit entry overrides the wildcard
+	qualityOf := func(encoding string) float64 {
+		if quality, ok := qualities[encoding]; ok {
+			return quality
+		}
+		return qualities["*"]
+	}
+	brQuality, gzipQuality := qualityOf("br"), qualityOf("gzip")
+	switch {
+	case brQuality > 0 && brQuality >= gzipQuality:
+		return "br"
+	case gzipQuality > 0:
+		return "gzip"
+	}
+	return ""
+}
+
+// compressWriter holds back the first compressionMinSize bytes of a response
+// to decide whether compressing it is worthwhile
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+
+	headerWritten bool // WriteHeader was called by the handler
+	decided       bool // the response has been committed, compressed or not
+	buffer        []byte
+	encoder       io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.headerWritten || cw.decided {
+		return
+	}
+	if status < http.StatusOK {
+		// Informational responses are sent right away
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	cw.status = status
+	cw.headerWritten = true
+	if !cw.compressible() {
+		cw.commit(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.headerWritten {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buffer = append(cw.buffer, b...)
+	if len(cw.buffer) >= compressionMinSize {
+		if err := cw.commit(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// compressible reports whether the response may be compressed, judging by
+// its status and headers
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+	if cw.status == http.StatusNoContent || cw.status == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// commit sends the headers and the buffered bytes, compressed or not
+func (cw *compressWriter) commit(compress bool) error {
+	cw.decided = true
+	if compress {
+		header := cw.Header()
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			// The compressed body is no longer byte-for-byte the tagged one
+			header.Set("ETag", "W/"+etag)
+		}
+		cw.encoder = cw.newEncoder()
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buffered := cw.buffer
+	cw.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(buffered)
+	} else {
+		_, err = cw.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+func (cw *compressWriter) newEncoder() io.WriteCloser {
+	if cw.encoding == "br" {
+		bw := brotliWriters.Get().(*brotli.Writer)
+		bw.Reset(cw.ResponseWriter)
+		return bw
+	}
+	gw := gzipWriters.Get().(*gzip.Writer)
+	gw.Reset(cw.ResponseWriter)
+	return gw
+}
+
+// Close finishes the response: a body that stayed below compressionMinSize
+// is sent as it is, otherwise the encoder is flushed and returned to its pool
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.headerWritten {
+			// The handler wrote nothing; net/http sends an empty 200
+			return nil
+		}
+		return cw.commit(false)
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	switch encoder := cw.encoder.(type) {
+	case *brotli.Writer:
+		brotliWriters.Put(encoder)
+	case *gzip.Writer:
+		gzipWriters.Put(encoder)
+	}
+	cw.encoder = nil
+	return err
+}
+
+// Flush sends what has been written so far, compressing it when the
+// response is compressible even if it is still small
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.headerWritten {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if !cw.decided {
+			cw.commit(true)
+		}
+	}
+	if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	cw.decided = true
+	return hijacker.Hijack()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
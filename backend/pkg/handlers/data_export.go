@@ -0,0 +1,329 @@
+// backend/pkg/handlers/data_export.go
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+// Archives without a matching export record (e.g. of purged accounts) are
+// removed once they are older than this
+const orphanedExportAge = time.Hour
+
+// Archives include every upload and can outlast the server's write timeout, so
+// downloads get a deadline of exportDownloadTimeout plus a second for every
+// exportDownloadMinRate bytes
+const (
+	exportDownloadTimeout = time.Minute
+	exportDownloadMinRate = 64 * 1024
+)
+
+// DataExportHandler builds ZIP archives of everything stored about a user.
+// Archives are written to exportsPath, which must not be publicly served, and
+// can only be downloaded by their owner, who is signed in and notified with a
+// link to the export.
+type DataExportHandler struct {
+	exportRepo       *models.DataExportRepository
+	notificationRepo *models.NotificationRepository
+	exportsPath      string
+	uploadsPath      string
+	apiURL           string
+	linkTTL          time.Duration
+
+	// Only one goroutine builds archives at a time
+	processing sync.Mutex
+}
+
+func NewDataExportHandler(exportRepo *models.DataExportRepository, notificationRepo *models.NotificationRepository, exportsPath, uploadsPath, apiURL string, linkTTL time.Duration) *DataExportHandler {
+	return &DataExportHandler{
+		exportRepo:       exportRepo,
+		notificationRepo: notificationRepo,
+		exportsPath:      exportsPath,
+		uploadsPath:      uploadsPath,
+		apiURL:           strings.TrimRight(apiURL, "/"),
+		linkTTL:          linkTTL,
+	}
+}
+
+// RequestExport queues an export of the current user's data. The archive is
+// built in the background and the user is notified when it is ready.
+func (deh *DataExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	pending, err := deh.exportRepo.HasPendingExport(userID)
+	if err != nil {
+		log.Printf("RequestExport - failed to check pending exports for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if pending {
+		utils.WriteErrorResponse(w, http.StatusConflict, constants.ErrDataExportPending)
+		return
+	}
+
+	export, err := deh.exportRepo.CreateExport(userID)
+	if err != nil {
+		log.Printf("RequestExport - failed to create export for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	go func() {
+		if err := deh.ProcessPendingExports(); err != nil {
+			log.Printf("Data export processing failed: %v", err)
+		}
+	}()
+
+	utils.WriteSuccessResponse(w, http.StatusAccepted, export)
+	log.Printf("Data export %d requested by user ID %d", export.ID, userID)
+}
+
+// GetExports lists the current user's exports
+func (deh *DataExportHandler) GetExports(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	exports, err := deh.exportRepo.GetUserExports(userID)
+	if err != nil {
+		log.Printf("GetExports - failed to get exports for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if exports == nil {
+		exports = []*models.DataExport{}
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"exports": exports,
+	})
+}
+
+// DownloadExport sends the archive behind a download link to its owner
+func (deh *DataExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	exportID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid export ID")
+		return
+	}
+
+	export, err := deh.exportRepo.GetReadyExport(exportID, userID)
+	if err != nil || export.FileName == nil {
+		if err != nil && !strings.Contains(err.Error(), constants.ErrDataExportNotFound) {
+			log.Printf("DownloadExport - failed to get export: %v", err)
+		}
+		utils.WriteErrorResponse(w, http.StatusNotFound, constants.ErrDataExportNotFound)
+		return
+	}
+
+	file, err := os.Open(filepath.Join(deh.exportsPath, *export.FileName))
+	if err != nil {
+		log.Printf("DownloadExport - failed to open archive of export %d: %v", export.ID, err)
+		utils.WriteErrorResponse(w, http.StatusNotFound, constants.ErrDataExportNotFound)
+		return
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil {
+		deadline := exportDownloadTimeout + time.Duration(info.Size()/exportDownloadMinRate)*time.Second
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(deadline)); err != nil {
+			log.Printf("DownloadExport - failed to extend write deadline for export %d: %v", export.ID, err)
+		}
+	}
+
+	downloadName := fmt.Sprintf("ripple-data-export-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, downloadName))
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, downloadName, *export.CompletedAt, file)
+	log.Printf("Data export %d downloaded by user ID %d", export.ID, userID)
+}
+
+// ProcessPendingExports builds every queued archive and notifies its owner.
+// It returns immediately if another call is already processing.
+func (deh *DataExportHandler) ProcessPendingExports() error {
+	if !deh.processing.TryLock() {
+		return nil
+	}
+	defer deh.processing.Unlock()
+
+	exports, err := deh.exportRepo.GetPendingExports()
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := deh.buildExport(export); err != nil {
+			log.Printf("Data export %d for user ID %d failed: %v", export.ID, export.UserID, err)
+			if err := deh.exportRepo.MarkFailed(export.ID); err != nil {
+				log.Printf("Failed to mark data export %d failed: %v", export.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (deh *DataExportHandler) buildExport(export *models.DataExport) error {
+	sections, err := deh.exportRepo.CollectUserData(export.UserID)
+	if err != nil {
+		return err
+	}
+	uploadPaths, err := deh.exportRepo.CollectUserUploadPaths(export.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(deh.exportsPath, 0700); err != nil {
+		return fmt.Errorf("failed to create exports directory: %w", err)
+	}
+
+	// Write to a temporary file so a half-written archive is never served
+	tmp, err := os.CreateTemp(deh.exportsPath, "export-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := deh.writeArchive(tmp, sections, uploadPaths); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fileName := fmt.Sprintf("export_%d_%d.zip", export.ID, time.Now().UnixNano())
+	if err := os.Rename(tmp.Name(), filepath.Join(deh.exportsPath, fileName)); err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	expiresAt := time.Now().Add(deh.linkTTL)
+	if err := deh.exportRepo.MarkReady(export.ID, fileName, expiresAt); err != nil {
+		os.Remove(filepath.Join(deh.exportsPath, fileName))
+		return err
+	}
+
+	downloadURL := fmt.Sprintf("%s/api/v1/auth/account/export/download?id=%d", deh.apiURL, export.ID)
+	if err := deh.notificationRepo.CreateDataExportNotification(export.UserID, export.ID, downloadURL, expiresAt); err != nil {
+		log.Printf("Failed to notify user ID %d about data export %d: %v", export.UserID, export.ID, err)
+	}
+
+	log.Printf("Data export %d for user ID %d is ready (%d files)", export.ID, export.UserID, len(uploadPaths))
+	return nil
+}
+
+// writeArchive writes one JSON file per section and the uploaded files under uploads/
+func (deh *DataExportHandler) writeArchive(w io.Writer, sections []models.ExportSection, uploadPaths []string) error {
+	archive := zip.NewWriter(w)
+
+	for _, section := range sections {
+		entry, err := archive.Create(section.Name + ".json")
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", section.Name, err)
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.Data); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", section.Name, err)
+		}
+	}
+
+	for _, uploadPath := range uploadPaths {
+		fullPath, err := utils.ResolveUploadPath(deh.uploadsPath, uploadPath)
+		if err != nil {
+			log.Printf("Data export - skipping %s: %v", uploadPath, err)
+			continue
+		}
+		if err := addFileToArchive(archive, fullPath, strings.TrimPrefix(uploadPath, "/")); err != nil {
+			log.Printf("Data export - skipping %s: %v", uploadPath, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+func addFileToArchive(archive *zip.Writer, fullPath, name string) error {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// CleanupExpiredExports removes archives whose download link has expired, failed
+// exports, and archives left behind by deleted accounts. It is meant to run periodically.
+func (deh *DataExportHandler) CleanupExpiredExports() error {
+	fileNames, err := deh.exportRepo.DeleteExpiredExports(deh.linkTTL)
+	if err != nil {
+		return err
+	}
+	for _, fileName := range fileNames {
+		if err := os.Remove(filepath.Join(deh.exportsPath, fileName)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove expired data export %s: %v", fileName, err)
+		}
+	}
+
+	known, err := deh.exportRepo.GetExportFileNames()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(deh.exportsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read exports directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || known[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < orphanedExportAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(deh.exportsPath, entry.Name())); err != nil {
+			log.Printf("Failed to remove orphaned data export %s: %v", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
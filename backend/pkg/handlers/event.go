@@ -29,27 +29,14 @@ func NewEventHandler(eventRepo *models.EventRepository, groupRepo *models.GroupR
 
 // CreateEvent creates a new event in a group
 func (eh *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -121,27 +108,14 @@ func (eh *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 
 // GetEvent gets a single event by ID
 func (eh *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get event ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Event ID required")
-		return
-	}
-
-	eventID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+	eventID, ok := pathID(w, r, "id", "event")
+	if !ok {
 		return
 	}
 
@@ -171,27 +145,14 @@ func (eh *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
 
 // GetGroupEvents gets events for a group
 func (eh *EventHandler) GetGroupEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -239,27 +200,14 @@ func (eh *EventHandler) GetGroupEvents(w http.ResponseWriter, r *http.Request) {
 
 // RespondToEvent responds to an event (going/not going)
 func (eh *EventHandler) RespondToEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get event ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Event ID required")
-		return
-	}
-
-	eventID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+	eventID, ok := pathID(w, r, "id", "event")
+	if !ok {
 		return
 	}
 
@@ -313,27 +261,14 @@ func (eh *EventHandler) RespondToEvent(w http.ResponseWriter, r *http.Request) {
 
 // GetEventResponses gets responses for an event
 func (eh *EventHandler) GetEventResponses(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get event ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Event ID required")
-		return
-	}
-
-	eventID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+	eventID, ok := pathID(w, r, "id", "event")
+	if !ok {
 		return
 	}
 
@@ -380,11 +315,6 @@ func (eh *EventHandler) GetEventResponses(w http.ResponseWriter, r *http.Request
 
 // GetUserEvents gets all events for a user from their groups
 func (eh *EventHandler) GetUserEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
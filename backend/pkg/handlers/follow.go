@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"ripple/pkg/auth"
@@ -28,21 +27,16 @@ func NewFollowHandler(followRepo *models.FollowRepository, userRepo *models.User
 }
 
 type FollowUserRequest struct {
-	UserID int `json:"user_id"`
+	UserID int `json:"user_id" openapi:"required,minimum=1"`
 }
 
 type FollowActionRequest struct {
-	FollowID int    `json:"follow_id"`
-	Action   string `json:"action"` // "accept" or "decline"
+	FollowID int    `json:"follow_id" openapi:"required,minimum=1"`
+	Action   string `json:"action" openapi:"required,enum=accept|decline"` // "accept" or "decline"
 }
 
 // FollowUser sends a follow request or immediately follows if public user
 func (fh *FollowHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -116,11 +110,6 @@ func (fh *FollowHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 
 // UnfollowUser unfollows a user
 func (fh *FollowHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -155,11 +144,6 @@ func (fh *FollowHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 
 // HandleFollowRequest accepts or declines a follow request
 func (fh *FollowHandler) HandleFollowRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -207,11 +191,6 @@ func (fh *FollowHandler) HandleFollowRequest(w http.ResponseWriter, r *http.Requ
 
 // GetFollowRequests gets pending follow requests for the current user
 func (fh *FollowHandler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -236,21 +215,8 @@ func (fh *FollowHandler) GetFollowRequests(w http.ResponseWriter, r *http.Reques
 
 // GetFollowers gets followers for a user
 func (fh *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Get user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	targetUserID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	targetUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -272,21 +238,8 @@ func (fh *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
 
 // GetFollowing gets users that a user is following
 func (fh *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Get user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	targetUserID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	targetUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -308,21 +261,8 @@ func (fh *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 
 // GetFollowStats gets follow statistics for a user
 func (fh *FollowHandler) GetFollowStats(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	// Get user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	targetUserID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	targetUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -337,27 +277,14 @@ func (fh *FollowHandler) GetFollowStats(w http.ResponseWriter, r *http.Request)
 
 // GetFollowStatus gets the follow relationship status between two users
 func (fh *FollowHandler) GetFollowStatus(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get target user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	targetUserID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	targetUserID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
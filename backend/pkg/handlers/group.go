@@ -13,6 +13,20 @@ import (
 	"ripple/pkg/utils"
 )
 
+type UpdateGroupPostRequest struct {
+	PostID  int    `json:"post_id" openapi:"required,minimum=1"`
+	Content string `json:"content" openapi:"required,maxLength=2000"`
+}
+
+type GroupPostLikeRequest struct {
+	PostID int `json:"post_id" openapi:"required,minimum=1"`
+}
+
+// InviteUsersRequest lists the users to invite to the group in the URL
+type InviteUsersRequest struct {
+	UserIDs []int `json:"user_ids" openapi:"required"`
+}
+
 type GroupHandler struct {
 	groupRepo        *models.GroupRepository
 	groupPostRepo    *models.GroupPostRepository
@@ -31,11 +45,6 @@ func NewGroupHandler(groupRepo *models.GroupRepository, groupPostRepo *models.Gr
 
 // CreateGroup creates a new group
 func (gh *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -73,27 +82,14 @@ func (gh *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 
 // GetGroup gets a single group by ID
 func (gh *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -112,27 +108,14 @@ func (gh *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 
 // UpdateGroup updates an existing group
 func (gh *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -171,11 +154,6 @@ func (gh *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 
 // GetAllGroups gets all groups for browsing
 func (gh *GroupHandler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -215,11 +193,6 @@ func (gh *GroupHandler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
 
 // GetUserGroups gets groups that the user is a member of
 func (gh *GroupHandler) GetUserGroups(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -259,11 +232,6 @@ func (gh *GroupHandler) GetUserGroups(w http.ResponseWriter, r *http.Request) {
 
 // SearchGroups searches for groups by title and description
 func (gh *GroupHandler) SearchGroups(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -311,11 +279,6 @@ func (gh *GroupHandler) SearchGroups(w http.ResponseWriter, r *http.Request) {
 
 // InviteToGroup invites users to join a group
 func (gh *GroupHandler) InviteToGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -381,11 +344,6 @@ func (gh *GroupHandler) InviteToGroup(w http.ResponseWriter, r *http.Request) {
 
 // JoinGroup requests to join a group
 func (gh *GroupHandler) JoinGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -449,11 +407,6 @@ func (gh *GroupHandler) JoinGroup(w http.ResponseWriter, r *http.Request) {
 
 // HandleMembershipRequest accepts or declines membership requests/invitations
 func (gh *GroupHandler) HandleMembershipRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -504,27 +457,14 @@ func (gh *GroupHandler) HandleMembershipRequest(w http.ResponseWriter, r *http.R
 
 // GetGroupMembers gets members of a group
 func (gh *GroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -552,11 +492,6 @@ func (gh *GroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request)
 
 // GetPendingInvitations gets pending group invitations for current user
 func (gh *GroupHandler) GetPendingInvitations(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -577,28 +512,14 @@ func (gh *GroupHandler) GetPendingInvitations(w http.ResponseWriter, r *http.Req
 
 // LeaveGroup allows a user to leave a group
 func (gh *GroupHandler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-	// fmt.Printf("leave group pathParts: %q\n", pathParts)
-
-	groupID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -638,27 +559,14 @@ func (gh *GroupHandler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
 
 // GetPendingJoinRequests gets pending join requests for a group (creator only)
 func (gh *GroupHandler) GetPendingJoinRequests(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -686,27 +594,14 @@ func (gh *GroupHandler) GetPendingJoinRequests(w http.ResponseWriter, r *http.Re
 
 // CreateGroupPost creates a new post in a group
 func (gh *GroupHandler) CreateGroupPost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -753,27 +648,14 @@ func (gh *GroupHandler) CreateGroupPost(w http.ResponseWriter, r *http.Request)
 
 // GetGroupPosts gets posts for a group
 func (gh *GroupHandler) GetGroupPosts(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get group ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Group ID required")
-		return
-	}
-
-	groupID, err := strconv.Atoi(pathParts[5])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
@@ -821,27 +703,14 @@ func (gh *GroupHandler) GetGroupPosts(w http.ResponseWriter, r *http.Request) {
 
 // CreateGroupComment creates a comment on a group post
 func (gh *GroupHandler) CreateGroupComment(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -898,27 +767,14 @@ func (gh *GroupHandler) CreateGroupComment(w http.ResponseWriter, r *http.Reques
 
 // GetGroupComments gets comments for a group post
 func (gh *GroupHandler) GetGroupComments(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 6 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[5])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -977,22 +833,13 @@ func (gh *GroupHandler) GetGroupComments(w http.ResponseWriter, r *http.Request)
 
 // UpdateGroupPost updates an existing group post
 func (gh *GroupHandler) UpdateGroupPost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	var req struct {
-		PostID  int    `json:"post_id"`
-		Content string `json:"content"`
-	}
-
+	var req UpdateGroupPostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
 		return
@@ -1037,27 +884,14 @@ func (gh *GroupHandler) UpdateGroupPost(w http.ResponseWriter, r *http.Request)
 
 // DeleteGroupPost deletes a group post
 func (gh *GroupHandler) DeleteGroupPost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 6 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[5])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -1083,20 +917,13 @@ func (gh *GroupHandler) DeleteGroupPost(w http.ResponseWriter, r *http.Request)
 
 // ToggleGroupPostLike toggles like/unlike for a group post
 func (gh *GroupHandler) ToggleGroupPostLike(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	var req struct {
-		PostID int `json:"post_id"`
-	}
+	var req GroupPostLikeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
 		return
@@ -1226,31 +1053,19 @@ func (gh *GroupHandler) validateUpdateGroupRequest(req *models.UpdateGroupReques
 
 // InviteUsers invites users to join a group (new endpoint for frontend)
 func (gh *GroupHandler) InviteUsers(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Extract group ID from URL
-	groupIDStr := strings.TrimPrefix(r.URL.Path, "/api/groups/")
-	groupIDStr = strings.TrimSuffix(groupIDStr, "/invite")
-	groupID, err := strconv.Atoi(groupIDStr)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid group ID")
+	groupID, ok := pathID(w, r, "id", "group")
+	if !ok {
 		return
 	}
 
 	// Parse request body
-	var req struct {
-		UserIDs []int `json:"user_ids"`
-	}
-
+	var req InviteUsersRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
@@ -1320,11 +1135,6 @@ func (gh *GroupHandler) InviteUsers(w http.ResponseWriter, r *http.Request) {
 
 // GetRecommendedGroups gets intelligent group recommendations for the current user
 func (gh *GroupHandler) GetRecommendedGroups(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
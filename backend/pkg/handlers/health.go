@@ -0,0 +1,120 @@
+// backend/pkg/handlers/health.go
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"ripple/pkg/db"
+	"ripple/pkg/utils"
+	"ripple/pkg/websocket"
+)
+
+// readinessTimeout bounds all readiness checks together, so a stuck
+// dependency fails the probe instead of hanging it
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler serves the orchestrator probes: /healthz reports that the
+// process is alive, /readyz that it can serve traffic
+type HealthHandler struct {
+	database         *db.Database
+	migrationVersion uint
+	uploadsPath      string
+	hub              *websocket.Hub
+
+	shuttingDown atomic.Bool
+}
+
+// HealthResponse is the body of both probes. Check details are only logged,
+// since the probes are public.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// NewHealthHandler expects the schema to be at migrationVersion, the latest
+// migration shipped with this build
+func NewHealthHandler(database *db.Database, migrationVersion uint, uploadsPath string, hub *websocket.Hub) *HealthHandler {
+	return &HealthHandler{
+		database:         database,
+		migrationVersion: migrationVersion,
+		uploadsPath:      uploadsPath,
+		hub:              hub,
+	}
+}
+
+// SetShuttingDown makes /readyz fail so load balancers stop sending traffic
+// while in-flight requests finish
+func (hh *HealthHandler) SetShuttingDown() {
+	hh.shuttingDown.Store(true)
+}
+
+// Healthz reports that the process is running
+func (hh *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJSONResponse(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readyz checks the dependencies needed to serve requests and answers 503
+// when any of them fails or the server is shutting down
+func (hh *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	// Draining: dependencies such as the hub are about to stop anyway
+	if hh.shuttingDown.Load() {
+		utils.WriteJSONResponse(w, http.StatusServiceUnavailable, HealthResponse{Status: "shutting_down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := map[string]error{
+		"database":   hh.database.DB.PingContext(ctx),
+		"migrations": hh.checkMigrations(),
+		"uploads":    hh.checkUploads(),
+		"websocket":  hh.hub.Ping(ctx),
+	}
+
+	response := HealthResponse{Status: "ready", Checks: make(map[string]string, len(checks))}
+	status := http.StatusOK
+	for name, err := range checks {
+		if err != nil {
+			log.Printf("Readyz - %s check failed: %v", name, err)
+			response.Checks[name] = "failing"
+			response.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[name] = "ok"
+	}
+
+	utils.WriteJSONResponse(w, status, response)
+}
+
+func (hh *HealthHandler) checkMigrations() error {
+	version, dirty, err := hh.database.MigrationVersion()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d failed and needs manual repair", version)
+	}
+	if version != hh.migrationVersion {
+		return fmt.Errorf("schema is at version %d, expected %d", version, hh.migrationVersion)
+	}
+	return nil
+}
+
+// checkUploads writes and removes a probe file, since a read-only mount or a
+// full disk only shows up on write
+func (hh *HealthHandler) checkUploads() error {
+	file, err := os.CreateTemp(hh.uploadsPath, ".readyz-*")
+	if err != nil {
+		return fmt.Errorf("uploads directory is not writable: %w", err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
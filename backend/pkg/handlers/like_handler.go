@@ -20,7 +20,7 @@ func NewLikeHandler(likeRepo *models.LikeRepository, postRepo *models.PostReposi
 
 // ToggleLikeRequest represents the request body for toggling a like
 type ToggleLikeRequest struct {
-	PostID int `json:"post_id"`
+	PostID int `json:"post_id" openapi:"required,minimum=1"`
 }
 
 // ToggleLikeResponse represents the response body for toggling a like
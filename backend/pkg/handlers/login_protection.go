@@ -0,0 +1,152 @@
+// backend/pkg/handlers/login_protection.go
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ripple/pkg/constants"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+// loginFailureWindow is how long a failed login counts towards a lockout
+const loginFailureWindow = 24 * time.Hour
+
+// lockoutPolicy locks a key once it reaches threshold failures. Every further
+// failure after a lockout expires doubles the lockout, up to maxLockout.
+type lockoutPolicy struct {
+	threshold   int
+	baseLockout time.Duration
+	maxLockout  time.Duration
+}
+
+var (
+	accountLockoutPolicy = lockoutPolicy{threshold: 5, baseLockout: 5 * time.Minute, maxLockout: 24 * time.Hour}
+	ipLockoutPolicy      = lockoutPolicy{threshold: 20, baseLockout: 5 * time.Minute, maxLockout: time.Hour}
+)
+
+func (p lockoutPolicy) lockoutFor(failures int) time.Duration {
+	if failures < p.threshold {
+		return 0
+	}
+
+	lockout := p.baseLockout
+	for i := p.threshold; i < failures && lockout < p.maxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > p.maxLockout {
+		lockout = p.maxLockout
+	}
+	return lockout
+}
+
+// Accounts are keyed by the normalized email so unknown addresses are throttled the same way
+func accountAttemptKey(email string) string { return "account:" + email }
+func ipAttemptKey(ip string) string         { return "ip:" + ip }
+
+// checkLoginLockout writes a 429 response and returns true if the account or client IP is locked
+func (ah *AuthHandler) checkLoginLockout(w http.ResponseWriter, email, ip string) bool {
+	checks := []struct {
+		key  string
+		code string
+	}{
+		{accountAttemptKey(email), constants.CodeAccountLocked},
+		{ipAttemptKey(ip), constants.CodeTooManyLoginAttempts},
+	}
+
+	for _, check := range checks {
+		lockedUntil, err := ah.loginAttemptRepo.GetLockedUntil(check.key)
+		if err != nil {
+			log.Printf("Login - failed to check lockout for %s: %v", check.key, err)
+			continue
+		}
+		if lockedUntil != nil {
+			writeLockedResponse(w, *lockedUntil, check.code)
+			return true
+		}
+	}
+	return false
+}
+
+// recordLoginFailure counts a failed login for the account and the client IP. If this
+// failure locks either of them, the locked response is written and true is returned.
+// user is nil when the email does not belong to an account.
+func (ah *AuthHandler) recordLoginFailure(w http.ResponseWriter, email, ip string, user *models.User) bool {
+	now := time.Now()
+
+	accountLockedUntil := ah.applyLockoutPolicy(accountAttemptKey(email), accountLockoutPolicy, now)
+	ipLockedUntil := ah.applyLockoutPolicy(ipAttemptKey(ip), ipLockoutPolicy, now)
+
+	if accountLockedUntil != nil {
+		log.Printf("Login locked for account %s until %s", email, accountLockedUntil.Format(time.RFC3339))
+		if user != nil {
+			ah.sendLockoutEmail(user, ip, *accountLockedUntil)
+		}
+		writeLockedResponse(w, *accountLockedUntil, constants.CodeAccountLocked)
+		return true
+	}
+	if ipLockedUntil != nil {
+		log.Printf("Login locked for IP %s until %s", ip, ipLockedUntil.Format(time.RFC3339))
+		writeLockedResponse(w, *ipLockedUntil, constants.CodeTooManyLoginAttempts)
+		return true
+	}
+	return false
+}
+
+// applyLockoutPolicy records a failure for key and locks it if the policy says so.
+// It returns the end of the new lockout, or nil if key was not locked.
+func (ah *AuthHandler) applyLockoutPolicy(key string, policy lockoutPolicy, now time.Time) *time.Time {
+	failures, err := ah.loginAttemptRepo.RecordFailure(key, loginFailureWindow)
+	if err != nil {
+		log.Printf("Login - failed to record failure for %s: %v", key, err)
+		return nil
+	}
+
+	lockout := policy.lockoutFor(failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	lockedUntil := now.Add(lockout)
+	if err := ah.loginAttemptRepo.Lock(key, lockedUntil); err != nil {
+		log.Printf("Login - failed to lock %s: %v", key, err)
+		return nil
+	}
+	return &lockedUntil
+}
+
+// clearLoginFailures resets the account counter after a correct password.
+// The IP counter is left alone so one valid account cannot be used to reset it.
+func (ah *AuthHandler) clearLoginFailures(email string) {
+	if err := ah.loginAttemptRepo.Clear(accountAttemptKey(email)); err != nil {
+		log.Printf("Login - failed to clear failures for %s: %v", email, err)
+	}
+}
+
+func (ah *AuthHandler) sendLockoutEmail(user *models.User, ip string, lockedUntil time.Time) {
+	sendMail(ah.mailer, &mail.Message{
+		To:      user.Email,
+		Subject: "Your Ripple account was temporarily locked",
+		Body: fmt.Sprintf("We blocked sign-ins to your Ripple account after several failed login attempts (last from %s).\n\n"+
+			"You can try again after %s.\n\n"+
+			"If this was not you, we recommend resetting your password and enabling two-factor authentication.\n",
+			ip, lockedUntil.UTC().Format("2006-01-02 15:04 MST")),
+	})
+}
+
+func writeLockedResponse(w http.ResponseWriter, lockedUntil time.Time, code string) {
+	retryAfter := int(time.Until(lockedUntil).Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	utils.WriteErrorResponseWithCode(w, http.StatusTooManyRequests, constants.ErrTooManyLoginAttempts, code)
+}
+
+// CleanupLoginAttempts removes failure records that no longer count towards a
+// lockout. It is meant to run periodically.
+func (ah *AuthHandler) CleanupLoginAttempts() error {
+	return ah.loginAttemptRepo.CleanupStale(loginFailureWindow)
+}
@@ -0,0 +1,15 @@
+// backend/pkg/handlers/mail.go
+package handlers
+
+import (
+	"log"
+
+	"ripple/pkg/mail"
+)
+
+// sendMail delivers an email without failing the request; delivery problems are only logged.
+func sendMail(mailer mail.Mailer, msg *mail.Message) {
+	if err := mailer.Send(msg); err != nil {
+		log.Printf("Failed to send %q email: %v", msg.Subject, err)
+	}
+}
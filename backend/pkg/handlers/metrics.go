@@ -0,0 +1,74 @@
+// backend/pkg/handlers/metrics.go
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"ripple/pkg/metrics"
+	"ripple/pkg/utils"
+)
+
+// MetricsHandler serves the metrics registry to Prometheus. Scrapers must
+// connect from an allowlisted address or present the configured bearer token.
+type MetricsHandler struct {
+	registry  *metrics.Registry
+	allowlist []*net.IPNet
+	token     string
+}
+
+// NewMetricsHandler accepts allowlist entries as single IPs or CIDR ranges;
+// invalid entries are logged and ignored. An empty token disables token access.
+func NewMetricsHandler(registry *metrics.Registry, allowlist []string, token string) *MetricsHandler {
+	mh := &MetricsHandler{registry: registry, token: token}
+	for _, entry := range allowlist {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("Metrics: ignoring invalid allowlist entry %q: %v", entry, err)
+			continue
+		}
+		mh.allowlist = append(mh.allowlist, network)
+	}
+	return mh
+}
+
+// ServeMetrics writes all metrics in the Prometheus text format
+func (mh *MetricsHandler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	if !mh.authorized(r) {
+		utils.WriteErrorResponse(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	mh.registry.Handler().ServeHTTP(w, r)
+}
+
+func (mh *MetricsHandler) authorized(r *http.Request) bool {
+	if mh.token != "" {
+		header := r.Header.Get("Authorization")
+		if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimSpace(header[7:])), []byte(mh.token)) == 1 {
+			return true
+		}
+	}
+
+	ip := net.ParseIP(utils.GetClientIP(r))
+	if ip == nil {
+		return false
+	}
+	for _, network := range mh.allowlist {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
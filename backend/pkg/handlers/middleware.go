@@ -2,8 +2,18 @@
 package handlers
 
 import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"ripple/pkg/logging"
+	"ripple/pkg/metrics"
 	"ripple/pkg/utils"
+	"strconv"
+	"time"
 )
 
 func JSONMiddleware(next http.Handler) http.Handler {
@@ -13,12 +23,6 @@ func JSONMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func RateLimitMiddleware(next http.Handler) http.Handler {
-	// Simple rate limiting can be implemented here
-	// For now, just pass through
-	return next
-}
-
 func SecurityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -32,9 +36,140 @@ func PanicRecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				logging.FromContext(r.Context()).Error("panic while handling request",
+					"method", r.Method, "path", r.URL.Path, "panic", fmt.Sprint(err))
 				utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 			}
 		}()
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequestIDMiddleware gives every request an ID, reusing a well-formed
+// X-Request-ID from the client or proxy, and echoes it in the response
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if !logging.ValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// AccessLogMiddleware writes one record per request once it completes. route
+// names the matched route pattern so records group by endpoint rather than by
+// path; the user ID is filled in by AuthMiddleware further down the chain.
+func AccessLogMiddleware(route func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			info := &logging.RequestInfo{}
+			r = r.WithContext(logging.WithRequestInfo(r.Context(), info))
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			attrs := []any{
+				"method", r.Method,
+				"route", route(r),
+				"path", r.URL.Path,
+				"status", recorder.status,
+				"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"bytes", recorder.bytes,
+			}
+			if info.UserID != 0 {
+				attrs = append(attrs, "user_id", info.UserID)
+			}
+
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logging.FromContext(r.Context()).Log(r.Context(), level, "request", attrs...)
+		})
+	}
+}
+
+// MetricsMiddleware counts requests and records their latency by route
+// pattern, method and status. Unmatched paths and unknown methods share one
+// label value so that scanners cannot create unbounded series.
+func MetricsMiddleware(route func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			pattern := route(r)
+			if pattern == "" {
+				pattern = "unmatched"
+			}
+			method := r.Method
+			switch method {
+			case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+				http.MethodPatch, http.MethodDelete, http.MethodOptions:
+			default:
+				method = "other"
+			}
+			status := strconv.Itoa(recorder.status)
+			metrics.HTTPRequests.Inc(pattern, method, status)
+			metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), pattern, method, status)
+		})
+	}
+}
+
+// statusRecorder captures the status code and size of a response. It passes
+// Flush and Hijack through so streaming and WebSocket upgrades keep working.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rec *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	rec.status = http.StatusSwitchingProtocols
+	rec.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
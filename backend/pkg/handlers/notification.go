@@ -23,11 +23,6 @@ func NewNotificationHandler(notificationRepo *models.NotificationRepository) *No
 
 // GetNotifications gets notifications for the current user
 func (nh *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -75,27 +70,14 @@ func (nh *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.R
 
 // MarkAsRead marks a notification as read
 func (nh *NotificationHandler) MarkAsRead(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get notification ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Notification ID required")
-		return
-	}
-
-	notificationID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid notification ID")
+	notificationID, ok := pathID(w, r, "id", "notification")
+	if !ok {
 		return
 	}
 
@@ -116,11 +98,6 @@ func (nh *NotificationHandler) MarkAsRead(w http.ResponseWriter, r *http.Request
 
 // MarkAllAsRead marks all notifications as read
 func (nh *NotificationHandler) MarkAllAsRead(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -140,27 +117,14 @@ func (nh *NotificationHandler) MarkAllAsRead(w http.ResponseWriter, r *http.Requ
 
 // DeleteNotification deletes a notification
 func (nh *NotificationHandler) DeleteNotification(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get notification ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Notification ID required")
-		return
-	}
-
-	notificationID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid notification ID")
+	notificationID, ok := pathID(w, r, "id", "notification")
+	if !ok {
 		return
 	}
 
@@ -0,0 +1,325 @@
+// backend/pkg/handlers/oidc.go
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/oidc"
+	"ripple/pkg/utils"
+)
+
+const (
+	// How long the user has to finish logging in at the provider
+	oidcLoginStateTTL = 10 * time.Minute
+
+	// Used when the provider does not share a birthdate; the user can correct it in their profile
+	oidcUnknownDateOfBirth = "0001-01-01"
+
+	// Binds the login state to the browser that started the flow
+	oidcStateCookieName = "oidc_state"
+)
+
+// OIDCHandler signs users in through an external OpenID Connect provider using
+// the authorization code flow with PKCE.
+type OIDCHandler struct {
+	provider       *oidc.Provider
+	userRepo       *models.UserRepository
+	identityRepo   *models.IdentityRepository
+	twoFactorRepo  *models.TwoFactorRepository
+	sessionManager *auth.SessionManager
+	frontendURL    string
+}
+
+func NewOIDCHandler(provider *oidc.Provider, userRepo *models.UserRepository, identityRepo *models.IdentityRepository, twoFactorRepo *models.TwoFactorRepository, sessionManager *auth.SessionManager, frontendURL string) *OIDCHandler {
+	return &OIDCHandler{
+		provider:       provider,
+		userRepo:       userRepo,
+		identityRepo:   identityRepo,
+		twoFactorRepo:  twoFactorRepo,
+		sessionManager: sessionManager,
+		frontendURL:    strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// Login starts the flow by redirecting the browser to the provider
+func (oh *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	state, stateHash, err := auth.GenerateToken()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	nonce, _, err := auth.GenerateToken()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	loginState := &models.OIDCLoginState{
+		Provider:     oh.provider.Name(),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	}
+	if err := oh.identityRepo.CreateLoginState(stateHash, loginState, time.Now().Add(oidcLoginStateTTL)); err != nil {
+		log.Printf("OIDC login - failed to store state: %v", err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	authURL, err := oh.provider.AuthCodeURL(r.Context(), state, nonce, oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		log.Printf("OIDC login - provider unavailable: %v", err)
+		utils.WriteErrorResponse(w, http.StatusBadGateway, "Identity provider is unavailable")
+		return
+	}
+
+	// The callback only accepts the state together with this cookie, so an
+	// attacker cannot finish their own login in the user's browser
+	oh.setStateCookie(w, stateHash, int(oidcLoginStateTTL.Seconds()))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback finishes the flow when the provider redirects back. On success the
+// user gets a session cookie and is sent to the frontend.
+func (oh *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	stateCookie, cookieErr := r.Cookie(oidcStateCookieName)
+	oh.setStateCookie(w, "", -1)
+
+	if providerError := query.Get("error"); providerError != "" {
+		log.Printf("OIDC callback - provider returned error: %s", providerError)
+		oh.redirectWithError(w, r, "oidc_denied")
+		return
+	}
+
+	stateHash := auth.HashToken(query.Get("state"))
+	if cookieErr != nil || subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(stateHash)) != 1 {
+		log.Printf("OIDC callback - state does not match the browser that started the login")
+		oh.redirectWithError(w, r, "oidc_invalid_state")
+		return
+	}
+
+	loginState, err := oh.identityRepo.ConsumeLoginState(stateHash)
+	if err != nil || loginState.Provider != oh.provider.Name() {
+		log.Printf("OIDC callback - invalid state: %v", err)
+		oh.redirectWithError(w, r, "oidc_invalid_state")
+		return
+	}
+
+	claims, err := oh.provider.Exchange(r.Context(), query.Get("code"), loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("OIDC callback - code exchange failed: %v", err)
+		oh.redirectWithError(w, r, "oidc_failed")
+		return
+	}
+
+	user, err := oh.resolveUser(claims)
+	if err != nil {
+		log.Printf("OIDC callback - could not resolve user for subject %s: %v", claims.Subject, err)
+		if strings.Contains(err.Error(), constants.ErrIdentityEmailUnverified) ||
+			strings.Contains(err.Error(), constants.ErrIdentityAccountUnverified) {
+			oh.redirectWithError(w, r, "oidc_account_exists")
+			return
+		}
+		oh.redirectWithError(w, r, "oidc_failed")
+		return
+	}
+
+	if accountPurgeDue(user) {
+		log.Printf("OIDC callback - account pending purge for user ID %d", user.ID)
+		oh.redirectWithError(w, r, "oidc_failed")
+		return
+	}
+
+	if user.IsSuspended() {
+		log.Printf("OIDC callback - account suspended for user ID %d", user.ID)
+		oh.redirectWithError(w, r, "account_suspended")
+		return
+	}
+
+	// Accounts with two-factor authentication still need their second factor
+	twoFactorEnabled, err := oh.twoFactorRepo.IsEnabled(user.ID)
+	if err != nil {
+		log.Printf("OIDC callback - failed to check two-factor status for user ID %d: %v", user.ID, err)
+		oh.redirectWithError(w, r, "oidc_failed")
+		return
+	}
+	if twoFactorEnabled {
+		token, _, err := createTwoFactorChallenge(oh.twoFactorRepo, user.ID)
+		if err != nil {
+			log.Printf("OIDC callback - failed to create two-factor challenge for user ID %d: %v", user.ID, err)
+			oh.redirectWithError(w, r, "oidc_failed")
+			return
+		}
+		http.Redirect(w, r, oh.frontendURL+"/login?two_factor_challenge="+url.QueryEscape(token), http.StatusFound)
+		return
+	}
+
+	// Never reuse a session ID that existed before authentication
+	if cookie, err := r.Cookie(auth.SessionCookieName); err == nil && cookie.Value != "" {
+		oh.sessionManager.DeleteSession(cookie.Value)
+	}
+
+	session, err := oh.sessionManager.CreateSession(user.ID, r.UserAgent(), utils.GetClientIP(r))
+	if err != nil {
+		log.Printf("OIDC callback - session creation failed for user ID %d: %v", user.ID, err)
+		oh.redirectWithError(w, r, "oidc_failed")
+		return
+	}
+	auth.SetSessionCookie(w, session.ID, session.ExpiresAt)
+	cancelAccountDeletion(oh.userRepo, user)
+
+	log.Printf("OIDC login completed for user ID %d via %s", user.ID, oh.provider.Name())
+	http.Redirect(w, r, oh.frontendURL+"/", http.StatusFound)
+}
+
+// resolveUser finds the user linked to the external identity. An unknown identity is
+// linked to the account with the same email if both the provider and the account
+// verified that email, otherwise a new account is created from the ID token claims.
+func (oh *OIDCHandler) resolveUser(claims *oidc.Claims) (*models.User, error) {
+	provider := oh.provider.Name()
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+
+	userID, err := oh.identityRepo.GetUserIDByIdentity(provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 {
+		if err := oh.identityRepo.RecordLogin(provider, claims.Subject, email); err != nil {
+			log.Printf("OIDC - failed to record login for user ID %d: %v", userID, err)
+		}
+		return oh.userRepo.GetUserByID(userID)
+	}
+
+	if email == "" || utils.ValidateEmail(email) != nil {
+		return nil, fmt.Errorf("provider did not return a usable email address")
+	}
+
+	user, err := oh.userRepo.GetUserByEmail(email)
+	if err != nil && !strings.Contains(err.Error(), constants.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if user != nil {
+		// Linking on an unverified email would let anyone with a provider account take over ours
+		if !claims.EmailVerified {
+			return nil, fmt.Errorf(constants.ErrIdentityEmailUnverified)
+		}
+		// An unverified account may have been registered by someone else who knows
+		// its password; the owner has to verify the email before linking
+		if !user.IsEmailVerified() {
+			return nil, fmt.Errorf(constants.ErrIdentityAccountUnverified)
+		}
+	} else {
+		user, err = oh.createUser(claims, email)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("OIDC - created user ID %d for %s subject %s", user.ID, provider, claims.Subject)
+	}
+
+	if err := oh.identityRepo.LinkIdentity(user.ID, provider, claims.Subject, email); err != nil {
+		return nil, err
+	}
+	if claims.EmailVerified && !user.IsEmailVerified() {
+		if err := oh.userRepo.MarkEmailVerified(user.ID); err != nil {
+			log.Printf("OIDC - failed to mark email verified for user ID %d: %v", user.ID, err)
+		}
+	}
+	return user, nil
+}
+
+// createUser registers a new account with the profile data from the provider.
+// The random password is never shown; the user can set one with the reset flow.
+func (oh *OIDCHandler) createUser(claims *oidc.Claims, email string) (*models.User, error) {
+	firstName, lastName := oidcNames(claims, email)
+
+	dateOfBirth := oidcUnknownDateOfBirth
+	if _, err := time.Parse("2006-01-02", claims.Birthdate); err == nil {
+		dateOfBirth = claims.Birthdate
+	}
+
+	var nickname *string
+	if username := strings.TrimSpace(claims.PreferredUsername); username != "" && len(username) <= 50 {
+		nickname = &username
+	}
+
+	password, _, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return oh.userRepo.CreateUser(&models.CreateUserRequest{
+		Email:       email,
+		FirstName:   firstName,
+		LastName:    lastName,
+		DateOfBirth: dateOfBirth,
+		Nickname:    nickname,
+	}, passwordHash)
+}
+
+func (oh *OIDCHandler) redirectWithError(w http.ResponseWriter, r *http.Request, code string) {
+	http.Redirect(w, r, oh.frontendURL+"/login?error="+url.QueryEscape(code), http.StatusFound)
+}
+
+// setStateCookie sets the cookie holding the hash of the login state, scoped to
+// the callback; a negative maxAge clears it.
+func (oh *OIDCHandler) setStateCookie(w http.ResponseWriter, stateHash string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    stateHash,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   false, // Set to true in production with HTTPS
+		SameSite: http.SameSiteLaxMode,
+		Path:     oh.provider.RedirectPath(),
+	})
+}
+
+// oidcNames picks first and last name from the claims, falling back to the full
+// name and finally to the email address.
+func oidcNames(claims *oidc.Claims, email string) (string, string) {
+	firstName := strings.TrimSpace(claims.GivenName)
+	lastName := strings.TrimSpace(claims.FamilyName)
+
+	if firstName == "" && lastName == "" {
+		parts := strings.Fields(claims.Name)
+		if len(parts) > 0 {
+			firstName = parts[0]
+			lastName = strings.Join(parts[1:], " ")
+		}
+	}
+	if firstName == "" {
+		firstName = strings.SplitN(email, "@", 2)[0]
+	}
+	if lastName == "" {
+		lastName = "-"
+	}
+
+	return truncateName(firstName), truncateName(lastName)
+}
+
+func truncateName(name string) string {
+	const maxLength = 100
+	if len(name) > maxLength {
+		return name[:maxLength]
+	}
+	return name
+}
@@ -0,0 +1,21 @@
+// backend/pkg/handlers/params.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ripple/pkg/utils"
+)
+
+// pathID reads a numeric path parameter such as {id} in "GET /api/groups/{id}"
+// and answers 400 when it is not a positive integer. label names the ID in the
+// error message, e.g. "group" for "Invalid group ID".
+func pathID(w http.ResponseWriter, r *http.Request, name, label string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid "+label+" ID")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,244 @@
+// backend/pkg/handlers/password.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+const passwordResetTokenTTL = time.Hour
+
+type PasswordHandler struct {
+	userRepo       *models.UserRepository
+	resetRepo      *models.PasswordResetRepository
+	sessionManager *auth.SessionManager
+	mailer         mail.Mailer
+	frontendURL    string
+}
+
+func NewPasswordHandler(userRepo *models.UserRepository, resetRepo *models.PasswordResetRepository, sessionManager *auth.SessionManager, mailer mail.Mailer, frontendURL string) *PasswordHandler {
+	return &PasswordHandler{
+		userRepo:       userRepo,
+		resetRepo:      resetRepo,
+		sessionManager: sessionManager,
+		mailer:         mailer,
+		frontendURL:    strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" openapi:"required,format=password"`
+	NewPassword     string `json:"new_password" openapi:"required,format=password"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" openapi:"required,format=email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" openapi:"required"`
+	NewPassword string `json:"new_password" openapi:"required,format=password"`
+}
+
+// ChangePassword updates the password of the current user after checking the current one.
+// All other sessions are signed out and the current session gets a new ID.
+func (ph *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	var errors utils.ValidationErrors
+	if err := utils.ValidateRequired(req.CurrentPassword, "current_password"); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := validateNewPassword(req.NewPassword); err != nil {
+		errors = append(errors, *err)
+	}
+	if errors.HasErrors() {
+		utils.WriteValidationErrorResponse(w, errors)
+		return
+	}
+
+	user, err := ph.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Printf("ChangePassword - failed to get user %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := auth.CheckPassword(req.CurrentPassword, user.PasswordHash); err != nil {
+		log.Printf("ChangePassword failed - invalid current password for user ID %d", userID)
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCurrentPassword)
+		return
+	}
+
+	if err := ph.setPassword(user.ID, req.NewPassword); err != nil {
+		log.Printf("ChangePassword - failed to update password for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	currentSessionID, _ := auth.GetSessionIDFromContext(r.Context())
+	if _, err := ph.sessionManager.DeleteOtherSessions(userID, currentSessionID); err != nil {
+		log.Printf("ChangePassword - failed to revoke other sessions for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := rotateRequestSession(w, r, ph.sessionManager); err != nil {
+		log.Printf("ChangePassword - failed to rotate session for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	sendMail(ph.mailer, &mail.Message{
+		To:      user.Email,
+		Subject: "Your Ripple password was changed",
+		Body: "The password for your Ripple account was just changed and all other devices were signed out.\n\n" +
+			"If you did not make this change, reset your password immediately.\n",
+	})
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Password changed successfully",
+	})
+	log.Printf("Password changed for user ID %d", userID)
+}
+
+// ForgotPassword emails a single-use reset link. The response is the same whether
+// or not the email belongs to an account, so it cannot be used to probe for users.
+func (ph *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := utils.ValidateEmail(req.Email); err != nil {
+		utils.WriteValidationErrorResponse(w, utils.ValidationErrors{*err})
+		return
+	}
+
+	response := map[string]string{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	}
+
+	user, err := ph.userRepo.GetUserByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		if !strings.Contains(err.Error(), constants.ErrUserNotFound) {
+			log.Printf("ForgotPassword - failed to look up user: %v", err)
+		}
+		utils.WriteSuccessResponse(w, http.StatusOK, response)
+		return
+	}
+
+	token, tokenHash, err := auth.GenerateToken()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ph.resetRepo.CreateToken(user.ID, tokenHash, time.Now().Add(passwordResetTokenTTL)); err != nil {
+		log.Printf("ForgotPassword - failed to store reset token for user ID %d: %v", user.ID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s", ph.frontendURL, url.QueryEscape(token))
+	sendMail(ph.mailer, &mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Ripple password",
+		Body: fmt.Sprintf("Someone asked to reset the password for your Ripple account.\n\n"+
+			"Use this link within %d minutes to choose a new password:\n%s\n\n"+
+			"If you did not ask for this, you can ignore this email.\n", int(passwordResetTokenTTL.Minutes()), resetLink),
+	})
+
+	log.Printf("Password reset requested for user ID %d", user.ID)
+	utils.WriteSuccessResponse(w, http.StatusOK, response)
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and signs out every session.
+func (ph *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	var errors utils.ValidationErrors
+	if err := utils.ValidateRequired(req.Token, "token"); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := validateNewPassword(req.NewPassword); err != nil {
+		errors = append(errors, *err)
+	}
+	if errors.HasErrors() {
+		utils.WriteValidationErrorResponse(w, errors)
+		return
+	}
+
+	userID, err := ph.resetRepo.ConsumeToken(auth.HashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		log.Printf("ResetPassword failed: %v", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidResetToken)
+		return
+	}
+
+	if err := ph.setPassword(userID, req.NewPassword); err != nil {
+		log.Printf("ResetPassword - failed to update password for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ph.sessionManager.DeleteUserSessions(userID); err != nil {
+		log.Printf("ResetPassword - failed to revoke sessions for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	// A reset usually means the account may be compromised, so scripts lose access too
+	if err := ph.sessionManager.DeleteUserAccessTokens(userID); err != nil {
+		log.Printf("ResetPassword - failed to revoke access tokens for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Password reset successfully, please log in with your new password",
+	})
+	log.Printf("Password reset completed for user ID %d", userID)
+}
+
+func (ph *PasswordHandler) setPassword(userID int, password string) error {
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return ph.userRepo.UpdatePassword(userID, passwordHash)
+}
+
+func validateNewPassword(password string) *utils.ValidationError {
+	if err := utils.ValidatePassword(password); err != nil {
+		err.Field = "new_password"
+		return err
+	}
+	return nil
+}
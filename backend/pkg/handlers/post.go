@@ -13,8 +13,8 @@ import (
 )
 
 type UpdatePostRequest struct {
-	PostID  int    `json:"post_id"`
-	Content string `json:"content"`
+	PostID  int    `json:"post_id" openapi:"required,minimum=1"`
+	Content string `json:"content" openapi:"required"`
 }
 
 type PostHandler struct {
@@ -29,11 +29,6 @@ func NewPostHandler(postRepo *models.PostRepository) *PostHandler {
 
 // CreatePost creates a new post
 func (ph *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -72,27 +67,14 @@ func (ph *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 
 // GetPost gets a single post by ID
 func (ph *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 4 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[3])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -115,11 +97,6 @@ func (ph *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
 
 // GetFeed gets posts for user's feed
 func (ph *PostHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -165,27 +142,14 @@ func (ph *PostHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
 
 // GetUserPosts gets posts by a specific user
 func (ph *PostHandler) GetUserPosts(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	viewerID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get user ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "User ID required")
-		return
-	}
-
-	userID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+	userID, ok := pathID(w, r, "id", "user")
+	if !ok {
 		return
 	}
 
@@ -222,11 +186,6 @@ func (ph *PostHandler) GetUserPosts(w http.ResponseWriter, r *http.Request) {
 
 // SearchPosts searches for posts by content
 func (ph *PostHandler) SearchPosts(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -274,27 +233,14 @@ func (ph *PostHandler) SearchPosts(w http.ResponseWriter, r *http.Request) {
 
 // DeletePost deletes a post
 func (ph *PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -315,11 +261,6 @@ func (ph *PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
 
 // CreateComment creates a new comment on a post
 func (ph *PostHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -366,27 +307,14 @@ func (ph *PostHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 // GetComments gets comments for a post
 func (ph *PostHandler) GetComments(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get post ID from URL path
-	pathParts := strings.Split(r.URL.Path, "/")
-	if len(pathParts) < 5 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Post ID required")
-		return
-	}
-
-	postID, err := strconv.Atoi(pathParts[4])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid post ID")
+	postID, ok := pathID(w, r, "id", "post")
+	if !ok {
 		return
 	}
 
@@ -427,11 +355,6 @@ func (ph *PostHandler) GetComments(w http.ResponseWriter, r *http.Request) {
 
 // UpdatePost updates a post
 func (ph *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
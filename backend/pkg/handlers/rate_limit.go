@@ -0,0 +1,156 @@
+// backend/pkg/handlers/rate_limit.go
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/constants"
+	"ripple/pkg/utils"
+)
+
+// RateLimiter is a token-bucket limiter. Every key gets a bucket holding up to
+// limit.Requests tokens that refills evenly over limit.Period; each request
+// takes one token.
+type RateLimiter struct {
+	name  string
+	limit config.RateLimit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimitResult describes the outcome of RateLimiter.Allow
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // until the next token, when not allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+func NewRateLimiter(name string, limit config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		name:    name,
+		limit:   limit,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (rl *RateLimiter) SetClock(now func() time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.now = now
+}
+
+// Enabled reports whether the limiter restricts anything
+func (rl *RateLimiter) Enabled() bool {
+	return rl != nil && rl.limit.Requests > 0 && rl.limit.Period > 0
+}
+
+// Allow takes a token from the key's bucket if one is available
+func (rl *RateLimiter) Allow(key string) RateLimitResult {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	capacity := float64(rl.limit.Requests)
+	perToken := rl.limit.Period / time.Duration(rl.limit.Requests)
+
+	b, exists := rl.buckets[key]
+	if !exists {
+		b = &tokenBucket{tokens: capacity, updated: now}
+		rl.buckets[key] = b
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+
+	result := RateLimitResult{Limit: rl.limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+	return result
+}
+
+// Len returns the number of tracked buckets
+func (rl *RateLimiter) Len() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.buckets)
+}
+
+// sweep drops buckets that have been idle long enough to refill completely. A
+// full bucket behaves exactly like a missing one, so nothing is lost. It runs
+// at most once per period to keep Allow cheap. The caller holds rl.mu.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.limit.Period {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, b := range rl.buckets {
+		if now.Sub(b.updated) >= rl.limit.Period {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// RateLimitMiddleware limits requests per user when the request is
+// authenticated and per client IP otherwise. Mount it after AuthMiddleware to
+// key by user. Disabled limiters pass every request through.
+func RateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limiter.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "ip:" + utils.GetClientIP(r)
+			if userID, err := auth.GetUserIDFromContext(r.Context()); err == nil {
+				key = fmt.Sprintf("user:%d", userID)
+			}
+
+			result := limiter.Allow(key)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				log.Printf("RateLimit: %s exceeded %s limit on %s %s", key, limiter.name, r.Method, r.URL.Path)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				utils.WriteErrorResponseWithCode(w, http.StatusTooManyRequests, constants.ErrRateLimited, constants.CodeRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
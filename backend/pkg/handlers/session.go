@@ -0,0 +1,156 @@
+// backend/pkg/handlers/session.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+// GetSessions lists the active sessions of the current user
+func (ah *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	currentSessionID, _ := auth.GetSessionIDFromContext(r.Context())
+
+	sessions, err := ah.sessionManager.GetUserSessions(userID)
+	if err != nil {
+		log.Printf("GetSessions failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	sessionResponses := make([]*models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, session.ToResponse(currentSessionID))
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]any{
+		"sessions": sessionResponses,
+		"count":    len(sessionResponses),
+	})
+}
+
+// RevokeSession ends one of the current user's sessions
+func (ah *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Sessions are addressed by their public ID, never by the secret session ID
+	publicID := r.PathValue("id")
+
+	if err := ah.sessionManager.RevokeUserSession(userID, publicID); err != nil {
+		if strings.Contains(err.Error(), constants.ErrSessionNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		log.Printf("RevokeSession failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	// Revoking the current session is equivalent to logging out
+	if currentSessionID, err := auth.GetSessionIDFromContext(r.Context()); err == nil {
+		current := &models.Session{ID: currentSessionID}
+		if current.PublicID() == publicID {
+			ah.clearSessionCookie(w)
+		}
+	}
+
+	log.Printf("Session revoked for user ID %d", userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions ends every session of the current user except the one making the request
+func (ah *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	currentSessionID, err := auth.GetSessionIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	revoked, err := ah.sessionManager.DeleteOtherSessions(userID, currentSessionID)
+	if err != nil {
+		log.Printf("RevokeOtherSessions failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	// Signing out other devices is security-sensitive, so the remaining session gets a fresh ID
+	if err := ah.rotateSession(w, r); err != nil {
+		log.Printf("RevokeOtherSessions - failed to rotate session for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Revoked %d other sessions for user ID %d", revoked, userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]any{
+		"message":       "Other sessions revoked successfully",
+		"revoked_count": revoked,
+	})
+}
+
+// IssueWebSocketTicket returns a short-lived, single-use ticket for opening the
+// WebSocket connection as ?ticket=..., so the session ID never appears in a URL
+func (ah *AuthHandler) IssueWebSocketTicket(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	sessionID, err := auth.GetSessionIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ticket, expiresAt, err := ah.sessionManager.CreateWebSocketTicket(userID, sessionID)
+	if err != nil {
+		log.Printf("IssueWebSocketTicket failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.WriteSuccessResponse(w, http.StatusCreated, map[string]any{
+		"ticket":     ticket,
+		"expires_at": expiresAt,
+	})
+}
+
+// rotateRequestSession replaces the ID of the session that made the request and
+// sends the new session cookie.
+func rotateRequestSession(w http.ResponseWriter, r *http.Request, sm *auth.SessionManager) error {
+	sessionID, err := auth.GetSessionIDFromContext(r.Context())
+	if err != nil {
+		return err
+	}
+
+	session, err := sm.RotateSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	auth.SetSessionCookie(w, session.ID, session.ExpiresAt)
+	return nil
+}
@@ -0,0 +1,405 @@
+// backend/pkg/handlers/two_factor.go
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+const (
+	totpIssuer = "Ripple"
+
+	twoFactorChallengeTTL         = 5 * time.Minute
+	twoFactorChallengeMaxAttempts = 5
+)
+
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password     string `json:"password" openapi:"required,format=password"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" openapi:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresAt         string `json:"expires_at"`
+	Message           string `json:"message"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	Message       string   `json:"message"`
+}
+
+// GetTwoFactorStatus reports whether the current user has two-factor authentication enabled
+func (ah *AuthHandler) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	totp, err := ah.twoFactorRepo.GetTOTP(userID)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	status := &models.TwoFactorStatusResponse{
+		Enabled:             totp.IsEnabled(),
+		PendingConfirmation: totp != nil && !totp.IsEnabled(),
+	}
+	if totp.IsEnabled() {
+		confirmedAt := utils.FormatTimeISO(*totp.ConfirmedAt)
+		status.ConfirmedAt = &confirmedAt
+
+		remaining, err := ah.twoFactorRepo.CountRecoveryCodes(userID)
+		if err != nil {
+			utils.WriteInternalErrorResponse(w, err)
+			return
+		}
+		status.RecoveryCodesRemaining = remaining
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, status)
+}
+
+// SetupTwoFactor creates a new unconfirmed TOTP secret for the current user
+func (ah *AuthHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	user, err := ah.userRepo.GetUserByID(userID)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ah.twoFactorRepo.SavePendingSecret(userID, secret); err != nil {
+		if strings.Contains(err.Error(), "already enabled") {
+			utils.WriteErrorResponse(w, http.StatusConflict, constants.ErrTwoFactorAlreadyEnabled)
+			return
+		}
+		log.Printf("SetupTwoFactor failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Two-factor enrollment started for user ID %d", userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, &TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(secret, totpIssuer, user.Email),
+	})
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves their
+// authenticator app works, and returns one-time recovery codes.
+func (ah *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	totp, err := ah.twoFactorRepo.GetTOTP(userID)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if totp == nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Two-factor setup has not been started")
+		return
+	}
+	if totp.IsEnabled() {
+		utils.WriteErrorResponse(w, http.StatusConflict, constants.ErrTwoFactorAlreadyEnabled)
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(totp.Secret, req.Code, time.Now())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidTwoFactorCode)
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ah.twoFactorRepo.ConfirmTOTP(userID, step, hashes); err != nil {
+		log.Printf("ConfirmTwoFactor failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ah.rotateSession(w, r); err != nil {
+		log.Printf("ConfirmTwoFactor - failed to rotate session for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Two-factor authentication enabled for user ID %d", userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, &RecoveryCodesResponse{
+		RecoveryCodes: codes,
+		Message:       "Two-factor authentication enabled. Store these recovery codes somewhere safe; each can be used once.",
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code
+func (ah *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	valid, err := ah.verifySecondFactor(userID, req.Code, "")
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if !valid {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidTwoFactorCode)
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ah.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, &RecoveryCodesResponse{
+		RecoveryCodes: codes,
+		Message:       "New recovery codes generated. Previous codes no longer work.",
+	})
+}
+
+// DisableTwoFactor turns two-factor authentication off after checking the password and a second factor
+func (ah *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	user, err := ah.userRepo.GetUserByID(userID)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := auth.CheckPassword(req.Password, user.PasswordHash); err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidCurrentPassword)
+		return
+	}
+
+	valid, err := ah.verifySecondFactor(userID, req.Code, req.RecoveryCode)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if !valid {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidTwoFactorCode)
+		return
+	}
+
+	if err := ah.twoFactorRepo.DisableTOTP(userID); err != nil {
+		log.Printf("DisableTwoFactor failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if err := ah.rotateSession(w, r); err != nil {
+		log.Printf("DisableTwoFactor - failed to rotate session for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Two-factor authentication disabled for user ID %d", userID)
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// VerifyTwoFactor completes a login that is waiting for a TOTP or recovery code
+func (ah *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := utils.ValidateRequired(req.ChallengeToken, "challenge_token"); err != nil {
+		utils.WriteValidationErrorResponse(w, utils.ValidationErrors{*err})
+		return
+	}
+
+	challengeHash := auth.HashToken(strings.TrimSpace(req.ChallengeToken))
+	userID, err := ah.twoFactorRepo.RecordChallengeAttempt(challengeHash, twoFactorChallengeMaxAttempts)
+	if err != nil {
+		log.Printf("VerifyTwoFactor failed: %v", err)
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidTwoFactorChallenge)
+		return
+	}
+
+	valid, err := ah.verifySecondFactor(userID, req.Code, req.RecoveryCode)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+	if !valid {
+		log.Printf("VerifyTwoFactor failed - invalid code for user ID %d", userID)
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, constants.ErrInvalidTwoFactorCode)
+		return
+	}
+
+	if err := ah.twoFactorRepo.DeleteChallenge(challengeHash); err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	user, err := ah.userRepo.GetUserByID(userID)
+	if err != nil {
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	// The account may have been suspended while the challenge was open
+	if user.IsSuspended() {
+		log.Printf("VerifyTwoFactor rejected - account suspended: user ID %d", userID)
+		utils.WriteErrorResponseWithCode(w, http.StatusForbidden, constants.ErrAccountSuspended, constants.CodeAccountSuspended)
+		return
+	}
+
+	ah.completeLogin(w, r, user, "Login successful")
+}
+
+// startTwoFactorChallenge answers a correct password login for a 2FA account
+// with a short-lived challenge token instead of a session.
+func (ah *AuthHandler) startTwoFactorChallenge(w http.ResponseWriter, user *models.User) {
+	token, expiresAt, err := createTwoFactorChallenge(ah.twoFactorRepo, user.ID)
+	if err != nil {
+		log.Printf("Login - failed to create two-factor challenge for user ID %d: %v", user.ID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	log.Printf("Login requires second factor for user ID %d", user.ID)
+	utils.WriteSuccessResponse(w, http.StatusOK, &TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         utils.FormatTimeISO(expiresAt),
+		Message:           "Enter the code from your authenticator app or a recovery code",
+	})
+}
+
+// createTwoFactorChallenge stores a pending second-factor login and returns its token
+func createTwoFactorChallenge(twoFactorRepo *models.TwoFactorRepository, userID int) (string, time.Time, error) {
+	token, tokenHash, err := auth.GenerateToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(twoFactorChallengeTTL)
+	if err := twoFactorRepo.CreateChallenge(tokenHash, userID, expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// verifySecondFactor checks a TOTP code, or failing that a recovery code, for a user with 2FA enabled
+func (ah *AuthHandler) verifySecondFactor(userID int, code, recoveryCode string) (bool, error) {
+	totp, err := ah.twoFactorRepo.GetTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	if !totp.IsEnabled() {
+		return false, nil
+	}
+
+	if strings.TrimSpace(code) != "" {
+		step, ok := auth.ValidateTOTP(totp.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		// Each code is only accepted once
+		return ah.twoFactorRepo.UseTOTPStep(userID, step)
+	}
+
+	if strings.TrimSpace(recoveryCode) != "" {
+		return ah.twoFactorRepo.UseRecoveryCode(userID, auth.HashRecoveryCode(recoveryCode))
+	}
+
+	return false, nil
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+	return codes, hashes, nil
+}
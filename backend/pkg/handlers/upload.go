@@ -4,7 +4,9 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"ripple/pkg/auth"
 	"ripple/pkg/config"
@@ -23,11 +25,6 @@ func NewUploadHandler(config *config.Config) *UploadHandler {
 
 // UploadAvatar uploads user avatar
 func (uh *UploadHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -69,11 +66,6 @@ func (uh *UploadHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 
 // UploadPostImage uploads image for posts
 func (uh *UploadHandler) UploadPostImage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -114,11 +106,6 @@ func (uh *UploadHandler) UploadPostImage(w http.ResponseWriter, r *http.Request)
 
 // UploadCommentImage uploads image for comments
 func (uh *UploadHandler) UploadCommentImage(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -159,11 +146,6 @@ func (uh *UploadHandler) UploadCommentImage(w http.ResponseWriter, r *http.Reque
 
 // UploadCover uploads user cover photo
 func (uh *UploadHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -204,11 +186,6 @@ func (uh *UploadHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
 
 // UploadGroupAvatar uploads group avatar
 func (uh *UploadHandler) UploadGroupAvatar(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -250,11 +227,6 @@ func (uh *UploadHandler) UploadGroupAvatar(w http.ResponseWriter, r *http.Reques
 
 // UploadGroupCover uploads group cover photo
 func (uh *UploadHandler) UploadGroupCover(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
 	userID, err := auth.GetUserIDFromContext(r.Context())
 	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
@@ -292,3 +264,48 @@ func (uh *UploadHandler) UploadGroupCover(w http.ResponseWriter, r *http.Request
 		"user_id":   userID,
 	})
 }
+
+// uploadCSP keeps an upload that is opened directly from ever running as a
+// page: the sandbox gives it an opaque origin without scripts, and only the
+// media itself may load
+const uploadCSP = "sandbox; default-src 'none'; img-src 'self'; media-src 'self'"
+
+// ServeUpload serves a stored upload with an explicit type taken from its
+// extension. Files of other types are only offered as downloads, and
+// directories and hidden files are not served at all. ETag and Last-Modified
+// let clients revalidate, and ranges let videos seek.
+func (uh *UploadHandler) ServeUpload(w http.ResponseWriter, r *http.Request) {
+	fullPath, err := utils.ResolveUploadPath(uh.config.UploadsPath, r.URL.Path)
+	if err != nil || strings.Contains(r.URL.Path, "/.") {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := filepath.Base(fullPath)
+	header := w.Header()
+	header.Set("Content-Security-Policy", uploadCSP)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("ETag", fileETag(info))
+	if contentType, ok := utils.MediaTypeByExtension(name); ok {
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", name))
+	} else {
+		header.Set("Content-Type", "application/octet-stream")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	}
+
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
@@ -0,0 +1,110 @@
+// backend/pkg/handlers/verification.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+	"ripple/pkg/utils"
+)
+
+const emailVerificationTokenTTL = 24 * time.Hour
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" openapi:"required"`
+}
+
+// VerifyEmail confirms the user's email address using the token from the verification email
+func (ah *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := utils.ValidateRequired(req.Token, "token"); err != nil {
+		utils.WriteValidationErrorResponse(w, utils.ValidationErrors{*err})
+		return
+	}
+
+	userID, err := ah.verificationRepo.ConsumeToken(auth.HashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		log.Printf("VerifyEmail failed: %v", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrInvalidVerificationToken)
+		return
+	}
+
+	if err := ah.userRepo.MarkEmailVerified(userID); err != nil {
+		log.Printf("VerifyEmail - failed to mark user ID %d as verified: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Email verified successfully",
+	})
+	log.Printf("Email verified for user ID %d", userID)
+}
+
+// ResendVerificationEmail sends a fresh verification link to the current user.
+// Earlier links stop working.
+func (ah *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	userID, err := auth.GetUserIDFromContext(r.Context())
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	user, err := ah.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Printf("ResendVerificationEmail - failed to get user %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	if user.IsEmailVerified() {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, constants.ErrEmailAlreadyVerified)
+		return
+	}
+
+	if err := ah.sendVerificationEmail(user); err != nil {
+		log.Printf("ResendVerificationEmail - failed for user ID %d: %v", userID, err)
+		utils.WriteInternalErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]string{
+		"message": "Verification email sent",
+	})
+}
+
+// sendVerificationEmail issues a new verification token and emails the link to the user
+func (ah *AuthHandler) sendVerificationEmail(user *models.User) error {
+	token, tokenHash, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	if err := ah.verificationRepo.CreateToken(user.ID, tokenHash, time.Now().Add(emailVerificationTokenTTL)); err != nil {
+		return err
+	}
+
+	verifyLink := fmt.Sprintf("%s/verify-email?token=%s", ah.frontendURL, url.QueryEscape(token))
+	sendMail(ah.mailer, &mail.Message{
+		To:      user.Email,
+		Subject: "Verify your Ripple email address",
+		Body: fmt.Sprintf("Welcome to Ripple!\n\n"+
+			"Confirm your email address within %d hours to start posting and messaging:\n%s\n\n"+
+			"If you did not create this account, you can ignore this email.\n", int(emailVerificationTokenTTL.Hours()), verifyLink),
+	})
+	return nil
+}
@@ -0,0 +1,155 @@
+// backend/pkg/logging/logging.go
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Redacted replaces the value of attributes that hold secrets
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute names whose values never reach the log output.
+// Keys are matched case-insensitively, also as a suffix such as "new_password".
+var sensitiveKeys = []string{
+	"password",
+	"session_id",
+	"cookie",
+	"set-cookie",
+	"authorization",
+	"token",
+	"ticket",
+	"secret",
+}
+
+// Setup installs the default slog logger. level is debug, info, warn or error;
+// format is text or json. Output of the standard log package goes through the
+// same handler, so existing log.Printf calls become info records.
+func Setup(level, format string) *slog.Logger {
+	return SetupWriter(os.Stderr, level, format)
+}
+
+// SetupWriter is Setup with a custom destination
+func SetupWriter(w io.Writer, level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level:       ParseLevel(level),
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// ParseLevel maps a level name to a slog level, defaulting to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+// IsSensitiveKey reports whether an attribute with this name holds a secret
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if key == sensitive || strings.HasSuffix(key, "_"+sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint returns a short, non-reversible identifier of a secret so log
+// lines about the same session or token can be correlated without leaking it
+func Fingerprint(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+type contextKey string
+
+const (
+	requestIDKey   contextKey = "requestID"
+	requestInfoKey contextKey = "requestInfo"
+)
+
+// RequestInfo collects details about a request while it is being handled, so
+// the access log can report values set by inner middleware such as the user ID
+type RequestInfo struct {
+	UserID int
+}
+
+// WithRequestID stores the request ID in the context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the ID of the current request, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithRequestInfo stores a RequestInfo in the context for inner handlers to fill in
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey, info)
+}
+
+// SetUserID records the authenticated user of the request for the access log
+func SetUserID(ctx context.Context, userID int) {
+	if info, ok := ctx.Value(requestInfoKey).(*RequestInfo); ok && info != nil {
+		info.UserID = userID
+	}
+}
+
+// FromContext returns the default logger annotated with the request ID
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return slog.Default().With("request_id", requestID)
+	}
+	return slog.Default()
+}
+
+// ValidRequestID reports whether an incoming X-Request-ID is safe to reuse:
+// short and limited to characters that cannot forge log lines
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 128 {
+		return false
+	}
+	for _, c := range requestID {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
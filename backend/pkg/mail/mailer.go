@@ -0,0 +1,95 @@
+// backend/pkg/mail/mailer.go
+package mail
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email. Production deployments can plug in an SMTP
+// or API-backed implementation; LogMailer and FileMailer cover development and tests.
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewMailer returns the mailer selected by driver ("log" or "file")
+func NewMailer(driver, from, dir string) (Mailer, error) {
+	switch driver {
+	case "", "log":
+		return NewLogMailer(from), nil
+	case "file":
+		return NewFileMailer(from, dir)
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", driver)
+	}
+}
+
+// LogMailer writes outgoing email to the application log. Bodies carry reset
+// and verification links, so they are only logged at debug level.
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (lm *LogMailer) Send(msg *Message) error {
+	log.Printf("Mail: From=%s To=%s Subject=%q", lm.from, msg.To, msg.Subject)
+	slog.Debug("Mail body", "to", msg.To, "body", msg.Body)
+	return nil
+}
+
+// FileMailer writes each outgoing email to its own .eml file in a directory
+type FileMailer struct {
+	from string
+	dir  string
+
+	mu      sync.Mutex
+	counter int
+}
+
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+func (fm *FileMailer) Send(msg *Message) error {
+	fm.mu.Lock()
+	fm.counter++
+	filename := fmt.Sprintf("%d_%04d.eml", time.Now().UnixNano(), fm.counter)
+	fm.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", fm.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(fm.dir, filename), []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+// Dir returns the directory the mailer writes to
+func (fm *FileMailer) Dir() string {
+	return fm.dir
+}
@@ -0,0 +1,32 @@
+// backend/pkg/metrics/application.go
+package metrics
+
+// DBBuckets are upper bounds in seconds suited to SQLite query durations
+var DBBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Application metrics, exposed on /metrics
+var (
+	HTTPRequests = Default.NewCounter("ripple_http_requests_total",
+		"HTTP requests by route pattern, method and status code.",
+		"route", "method", "status")
+
+	HTTPRequestDuration = Default.NewHistogram("ripple_http_request_duration_seconds",
+		"Time to handle HTTP requests by route pattern, method and status code.",
+		DefaultBuckets, "route", "method", "status")
+
+	DBQueryDuration = Default.NewHistogram("ripple_db_query_duration_seconds",
+		"Time to run database statements by operation and table.",
+		DBBuckets, "operation", "table")
+
+	WebSocketSendDrops = Default.NewCounter("ripple_websocket_send_drops_total",
+		"WebSocket messages dropped because a client's send queue was full.",
+		"source")
+
+	NotificationsCreated = Default.NewCounter("ripple_notifications_created_total",
+		"Notifications created by type.",
+		"type")
+
+	DeprecatedRequests = Default.NewCounter("ripple_deprecated_requests_total",
+		"Requests to deprecated routes and unversioned API aliases by route pattern as requested and method.",
+		"route", "method")
+)
@@ -0,0 +1,59 @@
+// backend/pkg/models/access_token.go
+package models
+
+import (
+	"time"
+)
+
+// PersonalAccessToken lets scripts and bots call the API on behalf of a user.
+// Only the hash of the token is stored.
+type PersonalAccessToken struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" openapi:"required,maxLength=100"`
+	Scopes        []string `json:"scopes" openapi:"required"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+type AccessTokenResponse struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// HasScope reports whether the token was granted scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *PersonalAccessToken) ToResponse() *AccessTokenResponse {
+	response := &AccessTokenResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		ExpiresAt: t.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: t.CreatedAt.Format(time.RFC3339),
+	}
+	if t.LastUsedAt != nil {
+		lastUsedAt := t.LastUsedAt.Format(time.RFC3339)
+		response.LastUsedAt = &lastUsedAt
+	}
+	return response
+}
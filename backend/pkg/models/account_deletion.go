@@ -0,0 +1,169 @@
+// backend/pkg/models/account_deletion.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AccountDeletionRepository removes accounts whose deletion grace period is over.
+// Requests are recorded on the user row, see UserRepository.RequestDeletion.
+type AccountDeletionRepository struct {
+	db *sql.DB
+}
+
+func NewAccountDeletionRepository(db *sql.DB) *AccountDeletionRepository {
+	return &AccountDeletionRepository{db: db}
+}
+
+// uploadReferenceQueries list every column that stores a path under /uploads/
+var uploadReferenceQueries = []string{
+	`SELECT 1 FROM users WHERE avatar_path = ?1 OR cover_path = ?1`,
+	`SELECT 1 FROM posts WHERE image_path = ?1`,
+	`SELECT 1 FROM comments WHERE image_path = ?1`,
+	`SELECT 1 FROM groups WHERE avatar_path = ?1 OR cover_path = ?1`,
+	`SELECT 1 FROM group_posts WHERE image_path = ?1`,
+	`SELECT 1 FROM group_post_comments WHERE image_path = ?1`,
+	`SELECT 1 FROM message_attachments WHERE file_path = ?1`,
+	`SELECT 1 FROM group_message_attachments WHERE file_path = ?1`,
+}
+
+// GetAccountsDueForPurge returns the users whose grace period has ended
+func (ar *AccountDeletionRepository) GetAccountsDueForPurge() ([]int, error) {
+	query := `SELECT id FROM users WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ? ORDER BY id`
+
+	rows, err := ar.db.Query(query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts due for purge: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during user rows iteration: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// PurgeAccount deletes the user and, through ON DELETE CASCADE, everything they
+// own, including groups they created and all content inside them. It returns
+// the upload paths that belonged to the deleted rows and are not used by
+// anything that remains, so the caller can remove the files.
+func (ar *AccountDeletionRepository) PurgeAccount(userID int) ([]string, error) {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	paths, err := collectUploadPaths(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only purge accounts that are still scheduled; a login may have cancelled the request
+	result, err := tx.Exec(`DELETE FROM users WHERE id = ? AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?`,
+		userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete user: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to delete user: %w", err)
+	} else if rows == 0 {
+		return nil, nil
+	}
+
+	// Another user may point at the same file, e.g. a shared group image
+	var orphaned []string
+	for _, path := range paths {
+		referenced, err := isUploadReferenced(tx, path)
+		if err != nil {
+			return nil, err
+		}
+		if !referenced {
+			orphaned = append(orphaned, path)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit account purge: %w", err)
+	}
+
+	return orphaned, nil
+}
+
+// collectUploadPaths gathers the paths of every upload that will be removed
+// together with the user
+func collectUploadPaths(tx *sql.Tx, userID int) ([]string, error) {
+	query := `
+		SELECT avatar_path FROM users WHERE id = ?1
+		UNION SELECT cover_path FROM users WHERE id = ?1
+		UNION SELECT image_path FROM posts WHERE user_id = ?1
+		UNION SELECT c.image_path FROM comments c
+			JOIN posts p ON c.post_id = p.id
+			WHERE c.user_id = ?1 OR p.user_id = ?1
+		UNION SELECT avatar_path FROM groups WHERE creator_id = ?1
+		UNION SELECT cover_path FROM groups WHERE creator_id = ?1
+		UNION SELECT gp.image_path FROM group_posts gp
+			JOIN groups g ON gp.group_id = g.id
+			WHERE gp.user_id = ?1 OR g.creator_id = ?1
+		UNION SELECT gc.image_path FROM group_post_comments gc
+			JOIN group_posts gp ON gc.group_post_id = gp.id
+			JOIN groups g ON gp.group_id = g.id
+			WHERE gc.user_id = ?1 OR gp.user_id = ?1 OR g.creator_id = ?1
+		UNION SELECT ma.file_path FROM message_attachments ma
+			JOIN messages m ON ma.message_id = m.id
+			WHERE m.sender_id = ?1 OR m.receiver_id = ?1
+		UNION SELECT ga.file_path FROM group_message_attachments ga
+			JOIN group_messages gm ON ga.group_message_id = gm.id
+			JOIN groups g ON gm.group_id = g.id
+			WHERE gm.sender_id = ?1 OR g.creator_id = ?1
+	`
+
+	rows, err := tx.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect upload paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path sql.NullString
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan upload path: %w", err)
+		}
+		if path.Valid && path.String != "" {
+			paths = append(paths, path.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during upload path rows iteration: %w", err)
+	}
+
+	return paths, nil
+}
+
+func isUploadReferenced(tx *sql.Tx, path string) (bool, error) {
+	for _, query := range uploadReferenceQueries {
+		var found int
+		err := tx.QueryRow(query+` LIMIT 1`, path).Scan(&found)
+		if err == nil {
+			return true, nil
+		}
+		if err != sql.ErrNoRows {
+			return false, fmt.Errorf("failed to check upload references: %w", err)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,357 @@
+// backend/pkg/models/admin.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"ripple/pkg/constants"
+)
+
+// AdminRepository holds the queries behind the moderation and admin API
+type AdminRepository struct {
+	db *sql.DB
+}
+
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// AdminUser is the view of an account shown to staff. Unlike UserResponse it
+// includes accounts that are suspended or scheduled for deletion.
+type AdminUser struct {
+	ID                  int        `json:"id"`
+	Email               string     `json:"email"`
+	FirstName           string     `json:"first_name"`
+	LastName            string     `json:"last_name"`
+	Nickname            *string    `json:"nickname"`
+	Role                string     `json:"role"`
+	EmailVerified       bool       `json:"email_verified"`
+	SuspendedAt         *time.Time `json:"suspended_at"`
+	SuspensionReason    *string    `json:"suspension_reason"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// AdminStats summarises the site for the admin dashboard
+type AdminStats struct {
+	Users          int `json:"users"`
+	Moderators     int `json:"moderators"`
+	Admins         int `json:"admins"`
+	SuspendedUsers int `json:"suspended_users"`
+	PendingDeletes int `json:"pending_deletions"`
+	Posts          int `json:"posts"`
+	Comments       int `json:"comments"`
+	Groups         int `json:"groups"`
+	GroupPosts     int `json:"group_posts"`
+	Events         int `json:"events"`
+	Messages       int `json:"messages"`
+	ActiveSessions int `json:"active_sessions"`
+}
+
+// Content types that moderators can remove
+const (
+	ContentTypePost             = "post"
+	ContentTypeComment          = "comment"
+	ContentTypeGroupPost        = "group_post"
+	ContentTypeGroupPostComment = "group_post_comment"
+)
+
+// moderatedContent maps each content type to its table and to the query that
+// collects the upload paths removed together with the row
+var moderatedContent = map[string]struct {
+	table       string
+	uploadQuery string
+}{
+	ContentTypePost: {
+		table: "posts",
+		uploadQuery: `SELECT image_path FROM posts WHERE id = ?1
+			UNION SELECT image_path FROM comments WHERE post_id = ?1`,
+	},
+	ContentTypeComment: {
+		table:       "comments",
+		uploadQuery: `SELECT image_path FROM comments WHERE id = ?1`,
+	},
+	ContentTypeGroupPost: {
+		table: "group_posts",
+		uploadQuery: `SELECT image_path FROM group_posts WHERE id = ?1
+			UNION SELECT image_path FROM group_post_comments WHERE group_post_id = ?1`,
+	},
+	ContentTypeGroupPostComment: {
+		table:       "group_post_comments",
+		uploadQuery: `SELECT image_path FROM group_post_comments WHERE id = ?1`,
+	},
+}
+
+// IsModeratedContentType reports whether contentType can be removed by moderators
+func IsModeratedContentType(contentType string) bool {
+	_, ok := moderatedContent[contentType]
+	return ok
+}
+
+// ListUsers returns accounts matching query (name or email), newest first
+func (ar *AdminRepository) ListUsers(query string, limit, offset int) ([]*AdminUser, error) {
+	listQuery := `
+		SELECT id, email, first_name, last_name, nickname, role, email_verified_at IS NOT NULL,
+		       suspended_at, suspension_reason, deletion_scheduled_at, created_at
+		FROM users
+		WHERE ?1 = '' OR first_name LIKE ?2 OR last_name LIKE ?2 OR email LIKE ?2 OR nickname LIKE ?2
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?3 OFFSET ?4
+	`
+
+	searchTerm := "%" + strings.ToLower(query) + "%"
+	rows, err := ar.db.Query(listQuery, query, searchTerm, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*AdminUser
+	for rows.Next() {
+		user := &AdminUser{}
+		var suspendedAt, deletionScheduledAt sql.NullTime
+		var suspensionReason sql.NullString
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.FirstName,
+			&user.LastName,
+			&user.Nickname,
+			&user.Role,
+			&user.EmailVerified,
+			&suspendedAt,
+			&suspensionReason,
+			&deletionScheduledAt,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if suspendedAt.Valid {
+			user.SuspendedAt = &suspendedAt.Time
+		}
+		if suspensionReason.Valid {
+			user.SuspensionReason = &suspensionReason.String
+		}
+		if deletionScheduledAt.Valid {
+			user.DeletionScheduledAt = &deletionScheduledAt.Time
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during user rows iteration: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserRole returns the role of the user
+func (ar *AdminRepository) GetUserRole(userID int) (string, error) {
+	var role string
+	err := ar.db.QueryRow(`SELECT role FROM users WHERE id = ?`, userID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf(constants.ErrUserNotFound)
+		}
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	return role, nil
+}
+
+// SuspendUser blocks the user from logging in
+func (ar *AdminRepository) SuspendUser(userID int, reason string) error {
+	query := `UPDATE users SET suspended_at = ?, suspension_reason = ?, updated_at = ? WHERE id = ?`
+	now := time.Now()
+	if _, err := ar.db.Exec(query, now, reason, now, userID); err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	return nil
+}
+
+// UnsuspendUser lifts a suspension. It reports whether the user was suspended.
+func (ar *AdminRepository) UnsuspendUser(userID int) (bool, error) {
+	query := `UPDATE users SET suspended_at = NULL, suspension_reason = NULL, updated_at = ? WHERE id = ? AND suspended_at IS NOT NULL`
+	result, err := ar.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SetRole changes the site-wide role of the user
+func (ar *AdminRepository) SetRole(userID int, role string) error {
+	query := `UPDATE users SET role = ?, updated_at = ? WHERE id = ?`
+	if _, err := ar.db.Exec(query, role, time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to set user role: %w", err)
+	}
+	return nil
+}
+
+// PromoteByEmail gives the listed accounts at least the given role. It is used to
+// bootstrap the first admins from configuration and returns the number of
+// accounts changed, along with the listed emails that were skipped because no
+// account with a verified email has them. Registration is open, so an
+// unverified account may belong to anyone.
+func (ar *AdminRepository) PromoteByEmail(emails []string, role string) (int64, []string, error) {
+	var promoted int64
+	var skipped []string
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		result, err := ar.db.Exec(`UPDATE users SET role = ?, updated_at = ?
+			WHERE email = ? AND role != ? AND email_verified_at IS NOT NULL`,
+			role, time.Now(), email, role)
+		if err != nil {
+			return promoted, skipped, fmt.Errorf("failed to promote %s: %w", email, err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return promoted, skipped, fmt.Errorf("failed to promote %s: %w", email, err)
+		}
+		promoted += rows
+		if rows > 0 {
+			continue
+		}
+
+		// Nothing changed: either the account already has the role or it is not eligible
+		var eligible bool
+		err = ar.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM users WHERE email = ? AND email_verified_at IS NOT NULL)`,
+			email).Scan(&eligible)
+		if err != nil {
+			return promoted, skipped, fmt.Errorf("failed to promote %s: %w", email, err)
+		}
+		if !eligible {
+			skipped = append(skipped, email)
+		}
+	}
+	return promoted, skipped, nil
+}
+
+// GetContentAuthor returns the author of a piece of content and their role
+func (ar *AdminRepository) GetContentAuthor(contentType string, contentID int) (int, string, error) {
+	content, ok := moderatedContent[contentType]
+	if !ok {
+		return 0, "", fmt.Errorf(constants.ErrInvalidContentType)
+	}
+
+	query := fmt.Sprintf(`SELECT c.user_id, u.role FROM %s c JOIN users u ON c.user_id = u.id WHERE c.id = ?`, content.table)
+	var authorID int
+	var role string
+	if err := ar.db.QueryRow(query, contentID).Scan(&authorID, &role); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", fmt.Errorf(constants.ErrContentNotFound)
+		}
+		return 0, "", fmt.Errorf("failed to get content author: %w", err)
+	}
+	return authorID, role, nil
+}
+
+// DeleteContent removes a post or comment, including replies that cascade with
+// it. It returns the upload paths that are no longer used by anything, so the
+// caller can remove the files.
+func (ar *AdminRepository) DeleteContent(contentType string, contentID int) ([]string, error) {
+	content, ok := moderatedContent[contentType]
+	if !ok {
+		return nil, fmt.Errorf(constants.ErrInvalidContentType)
+	}
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(content.uploadQuery, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect upload paths: %w", err)
+	}
+	var paths []string
+	for rows.Next() {
+		var path sql.NullString
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan upload path: %w", err)
+		}
+		if path.Valid && path.String != "" {
+			paths = append(paths, path.String)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during upload path rows iteration: %w", err)
+	}
+
+	result, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, content.table), contentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete %s: %w", contentType, err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to delete %s: %w", contentType, err)
+	} else if deleted == 0 {
+		return nil, fmt.Errorf(constants.ErrContentNotFound)
+	}
+
+	var orphaned []string
+	for _, path := range paths {
+		referenced, err := isUploadReferenced(tx, path)
+		if err != nil {
+			return nil, err
+		}
+		if !referenced {
+			orphaned = append(orphaned, path)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit content deletion: %w", err)
+	}
+
+	return orphaned, nil
+}
+
+// GetStats counts accounts and content
+func (ar *AdminRepository) GetStats() (*AdminStats, error) {
+	stats := &AdminStats{}
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM users WHERE role = ?1),
+			(SELECT COUNT(*) FROM users WHERE role = ?2),
+			(SELECT COUNT(*) FROM users WHERE suspended_at IS NOT NULL),
+			(SELECT COUNT(*) FROM users WHERE deletion_scheduled_at IS NOT NULL),
+			(SELECT COUNT(*) FROM posts),
+			(SELECT COUNT(*) FROM comments),
+			(SELECT COUNT(*) FROM groups),
+			(SELECT COUNT(*) FROM group_posts),
+			(SELECT COUNT(*) FROM events),
+			(SELECT COUNT(*) FROM messages),
+			(SELECT COUNT(*) FROM sessions WHERE expires_at > ?3)
+	`
+
+	err := ar.db.QueryRow(query, constants.RoleModerator, constants.RoleAdmin, time.Now()).Scan(
+		&stats.Users,
+		&stats.Moderators,
+		&stats.Admins,
+		&stats.SuspendedUsers,
+		&stats.PendingDeletes,
+		&stats.Posts,
+		&stats.Comments,
+		&stats.Groups,
+		&stats.GroupPosts,
+		&stats.Events,
+		&stats.Messages,
+		&stats.ActiveSessions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	return stats, nil
+}
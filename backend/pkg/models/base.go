@@ -13,25 +13,33 @@ type BaseModel struct {
 
 type User struct {
 	BaseModel
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	FirstName    string    `json:"first_name" db:"first_name"`
-	LastName     string    `json:"last_name" db:"last_name"`
-	DateOfBirth  time.Time `json:"date_of_birth" db:"date_of_birth"`
-	Nickname     *string   `json:"nickname" db:"nickname"`
-	AboutMe      *string   `json:"about_me" db:"about_me"`
-	AvatarPath   *string   `json:"avatar_path" db:"avatar_path"`
-	CoverPath    *string   `json:"cover_path" db:"cover_path"`
-	IsPublic     bool      `json:"is_public" db:"is_public"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	Email               string     `json:"email" db:"email"`
+	PasswordHash        string     `json:"-" db:"password_hash"`
+	FirstName           string     `json:"first_name" db:"first_name"`
+	LastName            string     `json:"last_name" db:"last_name"`
+	DateOfBirth         time.Time  `json:"date_of_birth" db:"date_of_birth"`
+	Nickname            *string    `json:"nickname" db:"nickname"`
+	AboutMe             *string    `json:"about_me" db:"about_me"`
+	AvatarPath          *string    `json:"avatar_path" db:"avatar_path"`
+	CoverPath           *string    `json:"cover_path" db:"cover_path"`
+	IsPublic            bool       `json:"is_public" db:"is_public"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at" db:"email_verified_at"`
+	DeletionScheduledAt *time.Time `json:"-" db:"deletion_scheduled_at"`
+	Role                string     `json:"role" db:"role"`
+	SuspendedAt         *time.Time `json:"suspended_at" db:"suspended_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type Session struct {
-	ID        string    `json:"id" db:"id"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID                string    `json:"id" db:"id"`
+	UserID            int       `json:"user_id" db:"user_id"`
+	UserAgent         string    `json:"user_agent" db:"user_agent"`
+	IPAddress         string    `json:"ip_address" db:"ip_address"`
+	LastUsedAt        time.Time `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt         time.Time `json:"expires_at" db:"expires_at"`
+	AbsoluteExpiresAt time.Time `json:"absolute_expires_at" db:"absolute_expires_at"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 }
 
 type Follow struct {
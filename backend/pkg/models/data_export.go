@@ -0,0 +1,379 @@
+// backend/pkg/models/data_export.go
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"ripple/pkg/constants"
+	"time"
+)
+
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+// DataExport is a request for an archive of everything stored about a user
+type DataExport struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"-"`
+	Status      string     `json:"status"`
+	FileName    *string    `json:"-"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// exportJSONColumns are built with json_group_array and are embedded as JSON rather than as strings
+var exportJSONColumns = map[string]bool{
+	"allowed_user_ids": true,
+	"attachments":      true,
+}
+
+// ExportSection is one JSON file of a data export
+type ExportSection struct {
+	Name string
+	Data interface{}
+}
+
+type DataExportRepository struct {
+	db *sql.DB
+}
+
+func NewDataExportRepository(db *sql.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// CreateExport queues a new export for the user
+func (dr *DataExportRepository) CreateExport(userID int) (*DataExport, error) {
+	export := &DataExport{UserID: userID, Status: DataExportPending, CreatedAt: time.Now()}
+
+	query := `INSERT INTO data_exports (user_id, status, created_at) VALUES (?, ?, ?) RETURNING id`
+	if err := dr.db.QueryRow(query, userID, export.Status, export.CreatedAt).Scan(&export.ID); err != nil {
+		return nil, fmt.Errorf("failed to create data export: %w", err)
+	}
+	return export, nil
+}
+
+// HasPendingExport reports whether the user already has an export being built
+func (dr *DataExportRepository) HasPendingExport(userID int) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM data_exports WHERE user_id = ? AND status = ?`
+	if err := dr.db.QueryRow(query, userID, DataExportPending).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check pending data exports: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetPendingExports returns the exports that still have to be built, oldest first
+func (dr *DataExportRepository) GetPendingExports() ([]*DataExport, error) {
+	return dr.queryExports(`
+		SELECT id, user_id, status, file_name, expires_at, created_at, completed_at
+		FROM data_exports
+		WHERE status = ?
+		ORDER BY created_at ASC
+	`, DataExportPending)
+}
+
+func (dr *DataExportRepository) GetUserExports(userID int) ([]*DataExport, error) {
+	return dr.queryExports(`
+		SELECT id, user_id, status, file_name, expires_at, created_at, completed_at
+		FROM data_exports
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+	`, userID)
+}
+
+// GetReadyExport returns one of the user's exports if it can still be downloaded
+func (dr *DataExportRepository) GetReadyExport(exportID, userID int) (*DataExport, error) {
+	exports, err := dr.queryExports(`
+		SELECT id, user_id, status, file_name, expires_at, created_at, completed_at
+		FROM data_exports
+		WHERE id = ? AND user_id = ? AND status = ? AND expires_at > ?
+	`, exportID, userID, DataExportReady, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, fmt.Errorf(constants.ErrDataExportNotFound)
+	}
+	return exports[0], nil
+}
+
+// MarkReady records the finished archive and until when it can be downloaded
+func (dr *DataExportRepository) MarkReady(exportID int, fileName string, expiresAt time.Time) error {
+	query := `
+		UPDATE data_exports SET status = ?, file_name = ?, expires_at = ?, completed_at = ?
+		WHERE id = ?
+	`
+	if _, err := dr.db.Exec(query, DataExportReady, fileName, expiresAt, time.Now(), exportID); err != nil {
+		return fmt.Errorf("failed to mark data export ready: %w", err)
+	}
+	return nil
+}
+
+func (dr *DataExportRepository) MarkFailed(exportID int) error {
+	query := `UPDATE data_exports SET status = ?, completed_at = ? WHERE id = ?`
+	if _, err := dr.db.Exec(query, DataExportFailed, time.Now(), exportID); err != nil {
+		return fmt.Errorf("failed to mark data export failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredExports removes exports whose download link has expired and
+// failed exports older than olderThan. It returns the archive file names so
+// the caller can remove the files.
+func (dr *DataExportRepository) DeleteExpiredExports(olderThan time.Duration) ([]string, error) {
+	query := `
+		DELETE FROM data_exports
+		WHERE (status = ? AND expires_at <= ?) OR (status = ? AND completed_at <= ?)
+		RETURNING file_name
+	`
+
+	now := time.Now()
+	rows, err := dr.db.Query(query, DataExportReady, now, DataExportFailed, now.Add(-olderThan))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var fileNames []string
+	for rows.Next() {
+		var fileName sql.NullString
+		if err := rows.Scan(&fileName); err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		if fileName.Valid {
+			fileNames = append(fileNames, fileName.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during data export rows iteration: %w", err)
+	}
+
+	return fileNames, nil
+}
+
+// GetExportFileNames returns the archive file names of every export still on record
+func (dr *DataExportRepository) GetExportFileNames() (map[string]bool, error) {
+	rows, err := dr.db.Query(`SELECT file_name FROM data_exports WHERE file_name IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export files: %w", err)
+	}
+	defer rows.Close()
+
+	fileNames := make(map[string]bool)
+	for rows.Next() {
+		var fileName string
+		if err := rows.Scan(&fileName); err != nil {
+			return nil, fmt.Errorf("failed to scan data export file: %w", err)
+		}
+		fileNames[fileName] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during data export rows iteration: %w", err)
+	}
+
+	return fileNames, nil
+}
+
+func (dr *DataExportRepository) queryExports(query string, args ...interface{}) ([]*DataExport, error) {
+	rows, err := dr.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var exports []*DataExport
+	for rows.Next() {
+		export := &DataExport{}
+		var expiresAt, completedAt sql.NullTime
+		if err := rows.Scan(&export.ID, &export.UserID, &export.Status, &export.FileName,
+			&expiresAt, &export.CreatedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		if expiresAt.Valid {
+			export.ExpiresAt = &expiresAt.Time
+		}
+		if completedAt.Valid {
+			export.CompletedAt = &completedAt.Time
+		}
+		exports = append(exports, export)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during data export rows iteration: %w", err)
+	}
+
+	return exports, nil
+}
+
+// CollectUserData gathers everything stored about the user, one section per JSON file
+func (dr *DataExportRepository) CollectUserData(userID int) ([]ExportSection, error) {
+	profile, err := dr.exportRows(`
+		SELECT id, email, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path,
+			is_public, email_verified_at, created_at, updated_at
+		FROM users WHERE id = ?
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile) == 0 {
+		return nil, fmt.Errorf("user %d not found", userID)
+	}
+	sections := []ExportSection{{Name: "profile", Data: profile[0]}}
+
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{"posts", `
+			SELECT p.id, p.content, p.image_path, p.privacy_level, p.created_at, p.updated_at,
+				(SELECT json_group_array(pp.user_id) FROM post_privacy pp WHERE pp.post_id = p.id) AS allowed_user_ids
+			FROM posts p WHERE p.user_id = ?1 ORDER BY p.created_at`},
+		{"comments", `
+			SELECT id, post_id, content, image_path, created_at, updated_at
+			FROM comments WHERE user_id = ?1 ORDER BY created_at`},
+		{"group_posts", `
+			SELECT gp.id, gp.group_id, g.title AS group_title, gp.content, gp.image_path, gp.created_at, gp.updated_at
+			FROM group_posts gp JOIN groups g ON gp.group_id = g.id
+			WHERE gp.user_id = ?1 ORDER BY gp.created_at`},
+		{"group_post_comments", `
+			SELECT id, group_post_id, content, image_path, created_at, updated_at
+			FROM group_post_comments WHERE user_id = ?1 ORDER BY created_at`},
+		{"likes", `
+			SELECT 'post' AS target_type, post_id AS target_id, created_at FROM likes WHERE user_id = ?1
+			UNION ALL
+			SELECT 'group_post', group_post_id, created_at FROM group_post_likes WHERE user_id = ?1
+			ORDER BY created_at`},
+		{"follows", `
+			SELECT CASE WHEN f.follower_id = ?1 THEN 'following' ELSE 'follower' END AS direction,
+				u.id AS user_id, u.first_name, u.last_name, f.status, f.created_at
+			FROM follows f
+			JOIN users u ON u.id = CASE WHEN f.follower_id = ?1 THEN f.following_id ELSE f.follower_id END
+			WHERE f.follower_id = ?1 OR f.following_id = ?1
+			ORDER BY f.created_at`},
+		{"groups_created", `
+			SELECT id, title, description, avatar_path, cover_path, created_at, updated_at
+			FROM groups WHERE creator_id = ?1 ORDER BY created_at`},
+		{"group_memberships", `
+			SELECT gm.group_id, g.title AS group_title, gm.status, gm.invited_by, gm.joined_at, gm.created_at
+			FROM group_members gm JOIN groups g ON gm.group_id = g.id
+			WHERE gm.user_id = ?1 ORDER BY gm.created_at`},
+		{"events_created", `
+			SELECT id, group_id, title, description, event_date, created_at, updated_at
+			FROM events WHERE creator_id = ?1 ORDER BY created_at`},
+		{"event_responses", `
+			SELECT er.event_id, e.title AS event_title, e.event_date, er.response, er.created_at, er.updated_at
+			FROM event_responses er JOIN events e ON er.event_id = e.id
+			WHERE er.user_id = ?1 ORDER BY er.created_at`},
+		{"messages", `
+			SELECT m.id, m.sender_id, m.receiver_id, m.content, m.created_at, m.read_at,
+				(SELECT json_group_array(ma.file_path) FROM message_attachments ma WHERE ma.message_id = m.id) AS attachments
+			FROM messages m WHERE m.sender_id = ?1 OR m.receiver_id = ?1 ORDER BY m.created_at`},
+		{"group_messages", `
+			SELECT gm.id, gm.group_id, g.title AS group_title, gm.content, gm.created_at,
+				(SELECT json_group_array(ga.file_path) FROM group_message_attachments ga WHERE ga.group_message_id = gm.id) AS attachments
+			FROM group_messages gm JOIN groups g ON gm.group_id = g.id
+			WHERE gm.sender_id = ?1 ORDER BY gm.created_at`},
+		{"notifications", `
+			SELECT id, type, title, message, related_id, related_type, is_read, created_at
+			FROM notifications WHERE user_id = ?1 ORDER BY created_at`},
+	}
+
+	for _, q := range queries {
+		rows, err := dr.exportRows(q.query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", q.name, err)
+		}
+		sections = append(sections, ExportSection{Name: q.name, Data: rows})
+	}
+
+	return sections, nil
+}
+
+// CollectUserUploadPaths returns the paths of every file the user uploaded
+func (dr *DataExportRepository) CollectUserUploadPaths(userID int) ([]string, error) {
+	query := `
+		SELECT avatar_path FROM users WHERE id = ?1
+		UNION SELECT cover_path FROM users WHERE id = ?1
+		UNION SELECT image_path FROM posts WHERE user_id = ?1
+		UNION SELECT image_path FROM comments WHERE user_id = ?1
+		UNION SELECT avatar_path FROM groups WHERE creator_id = ?1
+		UNION SELECT cover_path FROM groups WHERE creator_id = ?1
+		UNION SELECT image_path FROM group_posts WHERE user_id = ?1
+		UNION SELECT image_path FROM group_post_comments WHERE user_id = ?1
+		UNION SELECT ma.file_path FROM message_attachments ma
+			JOIN messages m ON ma.message_id = m.id WHERE m.sender_id = ?1
+		UNION SELECT ga.file_path FROM group_message_attachments ga
+			JOIN group_messages gm ON ga.group_message_id = gm.id WHERE gm.sender_id = ?1
+	`
+
+	rows, err := dr.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect upload paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path sql.NullString
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan upload path: %w", err)
+		}
+		if path.Valid && path.String != "" {
+			paths = append(paths, path.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during upload path rows iteration: %w", err)
+	}
+
+	return paths, nil
+}
+
+// exportRows runs query and returns each row as a column name to value map
+func (dr *DataExportRepository) exportRows(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := dr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			if text, ok := value.(string); ok && exportJSONColumns[column] {
+				value = json.RawMessage(text)
+			}
+			row[column] = value
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
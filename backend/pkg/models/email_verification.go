@@ -0,0 +1,77 @@
+// backend/pkg/models/email_verification.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type EmailVerificationRepository struct {
+	db *sql.DB
+}
+
+func NewEmailVerificationRepository(db *sql.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// CreateToken stores the hash of a new verification token, invalidating any earlier
+// unused tokens so that only the most recent email works.
+func (vr *EmailVerificationRepository) CreateToken(userID int, tokenHash string, expiresAt time.Time) error {
+	tx, err := vr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec(`UPDATE email_verification_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`, now, userID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate previous verification tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO email_verification_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query, userID, tokenHash, expiresAt, now); err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit verification token: %w", err)
+	}
+	return nil
+}
+
+// ConsumeToken marks a valid token as used and returns the user it belongs to.
+// A token can only be consumed once.
+func (vr *EmailVerificationRepository) ConsumeToken(tokenHash string) (int, error) {
+	now := time.Now()
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = ?
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		RETURNING user_id
+	`
+
+	var userID int
+	err := vr.db.QueryRow(query, now, tokenHash, now).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("verification token is invalid or expired")
+		}
+		return 0, fmt.Errorf("failed to consume verification token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// CleanupExpiredTokens removes tokens that can no longer be used
+func (vr *EmailVerificationRepository) CleanupExpiredTokens() error {
+	query := `DELETE FROM email_verification_tokens WHERE expires_at <= ? OR used_at IS NOT NULL`
+	if _, err := vr.db.Exec(query, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup verification tokens: %w", err)
+	}
+	return nil
+}
@@ -47,14 +47,14 @@ type EventResponse struct {
 }
 
 type CreateEventRequest struct {
-	Title           string `json:"title"`
-	Description     string `json:"description"`
-	EventDate       string `json:"event_date"`       // ISO format: "2025-06-15T19:00:00Z"
-	CreatorResponse string `json:"creator_response"` // "going" or "not_going"
+	Title           string `json:"title" openapi:"required,maxLength=200"`
+	Description     string `json:"description" openapi:"maxLength=1000"`
+	EventDate       string `json:"event_date" openapi:"required,format=date-time"`  // ISO format: "2025-06-15T19:00:00Z"
+	CreatorResponse string `json:"creator_response" openapi:"enum=going|not_going"` // "going" or "not_going"
 }
 
 type EventResponseRequest struct {
-	Response string `json:"response"` // "going" or "not_going"
+	Response string `json:"response" openapi:"required,enum=going|not_going"`
 }
 
 // CreateEvent creates a new event in a group
@@ -61,39 +61,39 @@ type GroupPostComment struct {
 }
 
 type CreateGroupRequest struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
+	Title       string  `json:"title" openapi:"required,maxLength=100"`
+	Description string  `json:"description" openapi:"maxLength=1000"`
 	AvatarPath  *string `json:"avatar_path"`
 	CoverPath   *string `json:"cover_path"`
 }
 
 type InviteToGroupRequest struct {
-	GroupID int   `json:"group_id"`
-	UserIDs []int `json:"user_ids"`
+	GroupID int   `json:"group_id" openapi:"required,minimum=1"`
+	UserIDs []int `json:"user_ids" openapi:"required"`
 }
 
 type JoinGroupRequest struct {
-	GroupID int `json:"group_id"`
+	GroupID int `json:"group_id" openapi:"required,minimum=1"`
 }
 
 type GroupActionRequest struct {
-	MembershipID int    `json:"membership_id"`
-	Action       string `json:"action"` // "accept" or "decline"
+	MembershipID int    `json:"membership_id" openapi:"required,minimum=1"`
+	Action       string `json:"action" openapi:"required,enum=accept|decline"`
 }
 
 type CreateGroupPostRequest struct {
-	Content   string  `json:"content"`
+	Content   string  `json:"content" openapi:"maxLength=2000"`
 	ImagePath *string `json:"image_path"`
 }
 
 type CreateGroupCommentRequest struct {
-	Content   string  `json:"content"`
+	Content   string  `json:"content" openapi:"maxLength=1000"`
 	ImagePath *string `json:"image_path"`
 }
 
 type UpdateGroupRequest struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
+	Title       string  `json:"title" openapi:"required,maxLength=100"`
+	Description string  `json:"description" openapi:"maxLength=1000"`
 	AvatarPath  *string `json:"avatar_path"`
 	CoverPath   *string `json:"cover_path"`
 }
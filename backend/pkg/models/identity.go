@@ -0,0 +1,147 @@
+// backend/pkg/models/identity.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// UserIdentity links an account at an external OpenID Connect provider to a local user
+type UserIdentity struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      int        `json:"user_id" db:"user_id"`
+	Provider    string     `json:"provider" db:"provider"`
+	Subject     string     `json:"-" db:"subject"`
+	Email       *string    `json:"email" db:"email"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
+}
+
+// OIDCLoginState is a pending authorization request, kept until the provider redirects back
+type OIDCLoginState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+}
+
+// GetUserIDByIdentity returns the user linked to the external identity, or 0 if none is linked
+func (ir *IdentityRepository) GetUserIDByIdentity(provider, subject string) (int, error) {
+	var userID int
+	query := `SELECT user_id FROM user_identities WHERE provider = ? AND subject = ?`
+	if err := ir.db.QueryRow(query, provider, subject).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get identity: %w", err)
+	}
+	return userID, nil
+}
+
+// LinkIdentity attaches an external identity to a user
+func (ir *IdentityRepository) LinkIdentity(userID int, provider, subject, email string) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at, last_login_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	now := time.Now()
+	if _, err := ir.db.Exec(query, userID, provider, subject, nullableString(email), now, now); err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+	return nil
+}
+
+// RecordLogin stores the time of the latest login and the email the provider reported
+func (ir *IdentityRepository) RecordLogin(provider, subject, email string) error {
+	query := `UPDATE user_identities SET last_login_at = ?, email = COALESCE(?, email) WHERE provider = ? AND subject = ?`
+	if _, err := ir.db.Exec(query, time.Now(), nullableString(email), provider, subject); err != nil {
+		return fmt.Errorf("failed to record identity login: %w", err)
+	}
+	return nil
+}
+
+func (ir *IdentityRepository) GetUserIdentities(userID int) ([]*UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE user_id = ?
+		ORDER BY created_at
+	`
+
+	rows, err := ir.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+	defer rows.Close()
+
+	var identities []*UserIdentity
+	for rows.Next() {
+		identity := &UserIdentity{}
+		if err := rows.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject,
+			&identity.Email, &identity.CreatedAt, &identity.LastLoginAt); err != nil {
+			return nil, fmt.Errorf("failed to scan identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during identity rows iteration: %w", err)
+	}
+
+	return identities, nil
+}
+
+// CreateLoginState stores the PKCE verifier and nonce of a new authorization request
+func (ir *IdentityRepository) CreateLoginState(stateHash string, state *OIDCLoginState, expiresAt time.Time) error {
+	query := `
+		INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if _, err := ir.db.Exec(query, stateHash, state.Provider, state.CodeVerifier, state.Nonce, expiresAt, time.Now()); err != nil {
+		return fmt.Errorf("failed to create login state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeLoginState removes a pending authorization request and returns it.
+// Each state can be used once, and only before it expires.
+func (ir *IdentityRepository) ConsumeLoginState(stateHash string) (*OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = ? AND expires_at > ?
+		RETURNING provider, code_verifier, nonce
+	`
+
+	state := &OIDCLoginState{}
+	err := ir.db.QueryRow(query, stateHash, time.Now()).Scan(&state.Provider, &state.CodeVerifier, &state.Nonce)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("login state is invalid or expired")
+		}
+		return nil, fmt.Errorf("failed to consume login state: %w", err)
+	}
+	return state, nil
+}
+
+// CleanupExpiredLoginStates removes authorization requests that were never completed
+func (ir *IdentityRepository) CleanupExpiredLoginStates() error {
+	if _, err := ir.db.Exec(`DELETE FROM oidc_login_states WHERE expires_at <= ?`, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup login states: %w", err)
+	}
+	return nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
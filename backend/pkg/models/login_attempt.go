@@ -0,0 +1,84 @@
+// backend/pkg/models/login_attempt.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LoginAttemptRepository keeps failed login counters in the database so that
+// lockouts survive restarts.
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// GetLockedUntil returns when the lockout for key ends, or nil if key is not locked
+func (lr *LoginAttemptRepository) GetLockedUntil(key string) (*time.Time, error) {
+	var lockedUntil sql.NullTime
+	err := lr.db.QueryRow(`SELECT locked_until FROM login_attempts WHERE attempt_key = ?`, key).Scan(&lockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+
+	if !lockedUntil.Valid || !lockedUntil.Time.After(time.Now()) {
+		return nil, nil
+	}
+	return &lockedUntil.Time, nil
+}
+
+// RecordFailure counts a failed login for key and returns the number of failures
+// so far. The count starts over when the previous failure is older than resetAfter.
+func (lr *LoginAttemptRepository) RecordFailure(key string, resetAfter time.Duration) (int, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT(attempt_key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures
+	`
+
+	var failures int
+	if err := lr.db.QueryRow(query, key, now, now.Add(-resetAfter)).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return failures, nil
+}
+
+// Lock blocks logins for key until the given time
+func (lr *LoginAttemptRepository) Lock(key string, until time.Time) error {
+	if _, err := lr.db.Exec(`UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?`, until, key); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	return nil
+}
+
+// Clear forgets all failures for key, e.g. after a successful login
+func (lr *LoginAttemptRepository) Clear(key string) error {
+	if _, err := lr.db.Exec(`DELETE FROM login_attempts WHERE attempt_key = ?`, key); err != nil {
+		return fmt.Errorf("failed to clear login failures: %w", err)
+	}
+	return nil
+}
+
+// CleanupStale removes counters whose last failure is older than olderThan and that are no longer locked
+func (lr *LoginAttemptRepository) CleanupStale(olderThan time.Duration) error {
+	now := time.Now()
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)
+	`
+	if _, err := lr.db.Exec(query, now.Add(-olderThan), now); err != nil {
+		return fmt.Errorf("failed to cleanup login attempts: %w", err)
+	}
+	return nil
+}
@@ -69,13 +69,13 @@ type WebSocketMessage struct {
 }
 
 type CreatePrivateMessageRequest struct {
-	ReceiverID int    `json:"receiver_id"`
-	Content    string `json:"content"`
+	ReceiverID int    `json:"receiver_id" openapi:"required,minimum=1"`
+	Content    string `json:"content" openapi:"required"`
 }
 
 type CreateGroupMessageRequest struct {
-	GroupID int    `json:"group_id"`
-	Content string `json:"content"`
+	GroupID int    `json:"group_id" openapi:"required,minimum=1"`
+	Content string `json:"content" openapi:"required"`
 }
 
 type UnreadCounts struct {
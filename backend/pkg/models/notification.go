@@ -4,6 +4,7 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"ripple/pkg/metrics"
 	"time"
 )
 
@@ -35,6 +36,7 @@ const (
 	NotificationEventCreated     = "event_created"
 	NotificationGroupPostCreated = "group_post_created"
 	NotificationEventReminder    = "event_reminder"
+	NotificationDataExportReady  = "data_export_ready"
 )
 
 type CreateNotificationRequest struct {
@@ -82,6 +84,7 @@ func (nr *NotificationRepository) CreateNotification(req *CreateNotificationRequ
 	if err != nil {
 		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
+	metrics.NotificationsCreated.Inc(notification.Type)
 
 	// Send real-time notification if WebSocket hub is available
 	if nr.wsHub != nil {
@@ -350,6 +353,21 @@ func (nr *NotificationRepository) CreateEventReminderNotification(userID, eventI
 	return err
 }
 
+// CreateDataExportNotification tells the user their data export can be downloaded
+func (nr *NotificationRepository) CreateDataExportNotification(userID, exportID int, downloadURL string, expiresAt time.Time) error {
+	req := &CreateNotificationRequest{
+		UserID:      userID,
+		Type:        NotificationDataExportReady,
+		Title:       "Your data export is ready",
+		Message:     fmt.Sprintf("Download your data before %s: %s", expiresAt.UTC().Format("January 2, 2006 15:04 MST"), downloadURL),
+		RelatedID:   &exportID,
+		RelatedType: stringPtr("data_export"),
+	}
+
+	_, err := nr.CreateNotification(req)
+	return err
+}
+
 // BulkCreateNotifications creates notifications for multiple users
 func (nr *NotificationRepository) BulkCreateNotifications(userIDs []int, notificationType NotificationType, title, message string, relatedID *int, relatedType *string) error {
 	if len(userIDs) == 0 {
@@ -396,6 +414,7 @@ func (nr *NotificationRepository) BulkCreateNotifications(userIDs []int, notific
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit bulk notifications: %w", err)
 	}
+	metrics.NotificationsCreated.Add(float64(len(userIDs)), string(notificationType))
 
 	// Send real-time notifications after successful commit
 	if nr.wsHub != nil {
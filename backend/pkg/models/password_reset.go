@@ -0,0 +1,77 @@
+// backend/pkg/models/password_reset.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// CreateToken stores the hash of a new reset token, invalidating any earlier
+// unused tokens so that only the most recent email works.
+func (pr *PasswordResetRepository) CreateToken(userID int, tokenHash string, expiresAt time.Time) error {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec(`UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`, now, userID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query, userID, tokenHash, expiresAt, now); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reset token: %w", err)
+	}
+	return nil
+}
+
+// ConsumeToken marks a valid token as used and returns the user it belongs to.
+// A token can only be consumed once.
+func (pr *PasswordResetRepository) ConsumeToken(tokenHash string) (int, error) {
+	now := time.Now()
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = ?
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+		RETURNING user_id
+	`
+
+	var userID int
+	err := pr.db.QueryRow(query, now, tokenHash, now).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("reset token is invalid or expired")
+		}
+		return 0, fmt.Errorf("failed to consume reset token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// CleanupExpiredTokens removes tokens that can no longer be used
+func (pr *PasswordResetRepository) CleanupExpiredTokens() error {
+	query := `DELETE FROM password_reset_tokens WHERE expires_at <= ? OR used_at IS NOT NULL`
+	if _, err := pr.db.Exec(query, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup reset tokens: %w", err)
+	}
+	return nil
+}
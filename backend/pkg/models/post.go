@@ -49,14 +49,14 @@ type Comment struct {
 }
 
 type CreatePostRequest struct {
-	Content      string  `json:"content"`
+	Content      string  `json:"content" openapi:"maxLength=2000"`
 	ImagePath    *string `json:"image_path"`
-	PrivacyLevel string  `json:"privacy_level"`
+	PrivacyLevel string  `json:"privacy_level" openapi:"required,enum=public|almost_private|private"`
 	AllowedUsers []int   `json:"allowed_users,omitempty"` // For private posts
 }
 
 type CreateCommentRequest struct {
-	PostID    int     `json:"postId"`
+	PostID    int     `json:"postId" openapi:"required,minimum=1"`
 	Content   string  `json:"content"`
 	ImagePath *string `json:"image_path"`
 }
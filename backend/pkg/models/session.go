@@ -0,0 +1,39 @@
+// backend/pkg/models/session.go
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Session struct is defined in base.go
+
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+	IsCurrent  bool   `json:"is_current"`
+}
+
+// PublicID returns a stable identifier for the session that can be shown to
+// the client without exposing the session token itself.
+func (s *Session) PublicID() string {
+	sum := sha256.Sum256([]byte(s.ID))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Session) ToResponse(currentSessionID string) *SessionResponse {
+	return &SessionResponse{
+		ID:         s.PublicID(),
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		LastUsedAt: s.LastUsedAt.Format(time.RFC3339),
+		ExpiresAt:  s.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+		IsCurrent:  s.ID == currentSessionID,
+	}
+}
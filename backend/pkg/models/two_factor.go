@@ -0,0 +1,269 @@
+// backend/pkg/models/two_factor.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+type UserTOTP struct {
+	UserID       int        `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	ConfirmedAt  *time.Time `json:"confirmed_at" db:"confirmed_at"`
+	LastUsedStep int64      `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsEnabled reports whether enrollment was confirmed with a valid code
+func (t *UserTOTP) IsEnabled() bool {
+	return t != nil && t.ConfirmedAt != nil
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool    `json:"enabled"`
+	PendingConfirmation    bool    `json:"pending_confirmation"`
+	ConfirmedAt            *string `json:"confirmed_at"`
+	RecoveryCodesRemaining int     `json:"recovery_codes_remaining"`
+}
+
+// GetTOTP returns the user's TOTP enrollment, or nil if the user never enrolled
+func (tr *TwoFactorRepository) GetTOTP(userID int) (*UserTOTP, error) {
+	totp := &UserTOTP{}
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at
+		FROM user_totp
+		WHERE user_id = ?
+	`
+
+	err := tr.db.QueryRow(query, userID).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get TOTP enrollment: %w", err)
+	}
+
+	return totp, nil
+}
+
+// IsEnabled reports whether the user has confirmed two-factor authentication
+func (tr *TwoFactorRepository) IsEnabled(userID int) (bool, error) {
+	totp, err := tr.GetTOTP(userID)
+	if err != nil {
+		return false, err
+	}
+	return totp.IsEnabled(), nil
+}
+
+// SavePendingSecret starts (or restarts) an unconfirmed enrollment.
+// It never overwrites a confirmed enrollment.
+func (tr *TwoFactorRepository) SavePendingSecret(userID int, secret string) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES (?, ?, NULL, 0, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			secret = excluded.secret,
+			last_used_step = 0,
+			created_at = excluded.created_at
+		WHERE user_totp.confirmed_at IS NULL
+	`
+
+	result, err := tr.db.Exec(query, userID, secret, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// ConfirmTOTP enables two-factor authentication and stores the recovery code hashes
+func (tr *TwoFactorRepository) ConfirmTOTP(userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user_totp SET confirmed_at = ?, last_used_step = ? WHERE user_id = ? AND confirmed_at IS NULL`
+	result, err := tx.Exec(query, time.Now(), step, userID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm TOTP: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("no pending two-factor enrollment")
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit TOTP confirmation: %w", err)
+	}
+	return nil
+}
+
+// UseTOTPStep records that a code for the given time step was accepted.
+// It returns false if that step (or a later one) was already used, preventing replay.
+func (tr *TwoFactorRepository) UseTOTPStep(userID int, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`
+	result, err := tr.db.Exec(query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record TOTP use: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// DisableTOTP removes the enrollment and all recovery codes
+func (tr *TwoFactorRepository) DisableTOTP(userID int) error {
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to disable TOTP: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit TOTP removal: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes invalidates all existing recovery codes and stores new ones
+func (tr *TwoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode consumes an unused recovery code. It returns false if the code is unknown or used.
+func (tr *TwoFactorRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	query := `UPDATE totp_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`
+	result, err := tr.db.Exec(query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func (tr *TwoFactorRepository) CountRecoveryCodes(userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = ? AND used_at IS NULL`
+	if err := tr.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// CreateChallenge stores a pending second-factor login
+func (tr *TwoFactorRepository) CreateChallenge(tokenHash string, userID int, expiresAt time.Time) error {
+	query := `
+		INSERT INTO two_factor_challenges (token_hash, user_id, attempts, expires_at, created_at)
+		VALUES (?, ?, 0, ?, ?)
+	`
+	if _, err := tr.db.Exec(query, tokenHash, userID, expiresAt, time.Now()); err != nil {
+		return fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+	return nil
+}
+
+// RecordChallengeAttempt counts a verification attempt against a pending challenge
+// and returns the user it belongs to. Expired or exhausted challenges are rejected.
+func (tr *TwoFactorRepository) RecordChallengeAttempt(tokenHash string, maxAttempts int) (int, error) {
+	query := `
+		UPDATE two_factor_challenges
+		SET attempts = attempts + 1
+		WHERE token_hash = ? AND expires_at > ? AND attempts < ?
+		RETURNING user_id
+	`
+
+	var userID int
+	err := tr.db.QueryRow(query, tokenHash, time.Now(), maxAttempts).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("two-factor challenge is invalid or expired")
+		}
+		return 0, fmt.Errorf("failed to record two-factor attempt: %w", err)
+	}
+	return userID, nil
+}
+
+func (tr *TwoFactorRepository) DeleteChallenge(tokenHash string) error {
+	if _, err := tr.db.Exec(`DELETE FROM two_factor_challenges WHERE token_hash = ?`, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete two-factor challenge: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredChallenges removes pending logins that can no longer be completed
+func (tr *TwoFactorRepository) CleanupExpiredChallenges() error {
+	if _, err := tr.db.Exec(`DELETE FROM two_factor_challenges WHERE expires_at <= ?`, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup two-factor challenges: %w", err)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	now := time.Now()
+	for _, codeHash := range codeHashes {
+		query := `INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)`
+		if _, err := tx.Exec(query, userID, codeHash, now); err != nil {
+			return fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+	return nil
+}
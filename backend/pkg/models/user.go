@@ -31,17 +31,19 @@ type CreateUserRequest struct {
 }
 
 type UserResponse struct {
-	ID          int     `json:"id"`
-	Email       string  `json:"email"`
-	FirstName   string  `json:"first_name"`
-	LastName    string  `json:"last_name"`
-	DateOfBirth string  `json:"date_of_birth"`
-	Nickname    *string `json:"nickname"`
-	AboutMe     *string `json:"about_me"`
-	AvatarPath  *string `json:"avatar_path"`
-	CoverPath   *string `json:"cover_path"`
-	IsPublic    bool    `json:"is_public"`
-	CreatedAt   string  `json:"created_at"`
+	ID            int     `json:"id"`
+	Email         string  `json:"email"`
+	FirstName     string  `json:"first_name"`
+	LastName      string  `json:"last_name"`
+	DateOfBirth   string  `json:"date_of_birth"`
+	Nickname      *string `json:"nickname"`
+	AboutMe       *string `json:"about_me"`
+	AvatarPath    *string `json:"avatar_path"`
+	CoverPath     *string `json:"cover_path"`
+	IsPublic      bool    `json:"is_public"`
+	CreatedAt     string  `json:"created_at"`
+	EmailVerified bool    `json:"email_verified,omitempty"`
+	Role          string  `json:"role,omitempty"`
 }
 
 type ProfileResponse struct {
@@ -60,6 +62,8 @@ type ProfileResponse struct {
 	FollowingCount int     `json:"following_count"`
 	PostCount      int     `json:"post_count"`
 	IsFollowing    bool    `json:"is_following,omitempty"`
+	EmailVerified  bool    `json:"email_verified,omitempty"`
+	Role           string  `json:"role,omitempty"`
 }
 
 func (ur *UserRepository) CreateUser(req *CreateUserRequest, passwordHash string) (*User, error) {
@@ -87,6 +91,7 @@ func (ur *UserRepository) CreateUser(req *CreateUserRequest, passwordHash string
 		AvatarPath:   req.AvatarPath,
 		CoverPath:    nil, // Default to nil for new users
 		IsPublic:     true,
+		Role:         constants.RoleUser,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -116,7 +121,7 @@ func (ur *UserRepository) GetUserByEmail(email string) (*User, error) {
 	user := &User{}
 
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, email_verified_at, deletion_scheduled_at, role, suspended_at, created_at, updated_at
 		FROM users
 		WHERE email = ?
 	`
@@ -133,6 +138,10 @@ func (ur *UserRepository) GetUserByEmail(email string) (*User, error) {
 		&user.AvatarPath,
 		&user.CoverPath,
 		&user.IsPublic,
+		&user.EmailVerifiedAt,
+		&user.DeletionScheduledAt,
+		&user.Role,
+		&user.SuspendedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -151,7 +160,7 @@ func (ur *UserRepository) GetUserByID(id int) (*User, error) {
 	user := &User{}
 
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, email_verified_at, deletion_scheduled_at, role, suspended_at, created_at, updated_at
 		FROM users
 		WHERE id = ?
 	`
@@ -168,6 +177,10 @@ func (ur *UserRepository) GetUserByID(id int) (*User, error) {
 		&user.AvatarPath,
 		&user.CoverPath,
 		&user.IsPublic,
+		&user.EmailVerifiedAt,
+		&user.DeletionScheduledAt,
+		&user.Role,
+		&user.SuspendedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -223,12 +236,65 @@ func (ur *UserRepository) UpdateProfile(userID int, updates map[string]interface
 	return nil
 }
 
+func (ur *UserRepository) UpdatePassword(userID int, passwordHash string) error {
+	query := `UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?`
+
+	_, err := ur.db.Exec(query, passwordHash, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) MarkEmailVerified(userID int) error {
+	query := `UPDATE users SET email_verified_at = ?, updated_at = ? WHERE id = ? AND email_verified_at IS NULL`
+
+	now := time.Now()
+	_, err := ur.db.Exec(query, now, now, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	return nil
+}
+
+// RequestDeletion schedules the account to be purged at purgeAt
+func (ur *UserRepository) RequestDeletion(userID int, purgeAt time.Time) error {
+	query := `UPDATE users SET deletion_scheduled_at = ?, updated_at = ? WHERE id = ?`
+	if _, err := ur.db.Exec(query, purgeAt, time.Now(), userID); err != nil {
+		return fmt.Errorf("failed to request account deletion: %w", err)
+	}
+	return nil
+}
+
+// CancelDeletion clears a pending deletion request that has not yet run out.
+// It reports whether a request was cancelled.
+func (ur *UserRepository) CancelDeletion(userID int) (bool, error) {
+	query := `
+		UPDATE users SET deletion_scheduled_at = NULL, updated_at = ?
+		WHERE id = ? AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at > ?
+	`
+	now := time.Now()
+	result, err := ur.db.Exec(query, now, userID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+	return rows > 0, nil
+}
+
 // SearchUsers searches for users by name or email
 func (ur *UserRepository) SearchUsers(query string, limit, offset int) ([]*User, error) {
 	searchQuery := `
-		SELECT id, email, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, created_at, updated_at
+		SELECT id, email, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, email_verified_at, deletion_scheduled_at, role, suspended_at, created_at, updated_at
 		FROM users
 		WHERE (first_name LIKE ? OR last_name LIKE ? OR email LIKE ? OR nickname LIKE ?)
+		  AND deletion_scheduled_at IS NULL
 		ORDER BY first_name, last_name
 		LIMIT ? OFFSET ?
 	`
@@ -254,6 +320,10 @@ func (ur *UserRepository) SearchUsers(query string, limit, offset int) ([]*User,
 			&user.AvatarPath,
 			&user.CoverPath,
 			&user.IsPublic,
+			&user.EmailVerifiedAt,
+			&user.DeletionScheduledAt,
+			&user.Role,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -271,9 +341,9 @@ func (ur *UserRepository) SearchUsers(query string, limit, offset int) ([]*User,
 func (ur *UserRepository) GetAll(currentUserID int) ([]*User, error) {
 	// Select all fields needed to create a full User object for a consistent response.
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, date_of_birth, nickname, about_me, avatar_path, cover_path, is_public, email_verified_at, deletion_scheduled_at, role, suspended_at, created_at, updated_at
 		FROM users
-		WHERE id != ?
+		WHERE id != ? AND deletion_scheduled_at IS NULL
 		ORDER BY first_name ASC, last_name ASC
 	`
 	rows, err := ur.db.Query(query, currentUserID)
@@ -297,6 +367,10 @@ func (ur *UserRepository) GetAll(currentUserID int) ([]*User, error) {
 			&user.AvatarPath,
 			&user.CoverPath,
 			&user.IsPublic,
+			&user.EmailVerifiedAt,
+			&user.DeletionScheduledAt,
+			&user.Role,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -313,19 +387,35 @@ func (ur *UserRepository) GetAll(currentUserID int) ([]*User, error) {
 	return users, nil
 }
 
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsSuspended reports whether staff suspended the account
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// IsPendingDeletion reports whether the user asked for their account to be deleted
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil
+}
+
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:          u.ID,
-		Email:       u.Email,
-		FirstName:   u.FirstName,
-		LastName:    u.LastName,
-		DateOfBirth: u.DateOfBirth.Format("2006-01-02"),
-		Nickname:    u.Nickname,
-		AboutMe:     u.AboutMe,
-		AvatarPath:  u.AvatarPath,
-		CoverPath:   u.CoverPath,
-		IsPublic:    u.IsPublic,
-		CreatedAt:   u.CreatedAt.Format(time.RFC3339),
+		ID:            u.ID,
+		Email:         u.Email,
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		DateOfBirth:   u.DateOfBirth.Format("2006-01-02"),
+		Nickname:      u.Nickname,
+		AboutMe:       u.AboutMe,
+		AvatarPath:    u.AvatarPath,
+		CoverPath:     u.CoverPath,
+		IsPublic:      u.IsPublic,
+		CreatedAt:     u.CreatedAt.Format(time.RFC3339),
+		EmailVerified: u.IsEmailVerified(),
+		Role:          u.Role,
 	}
 }
 
@@ -346,5 +436,7 @@ func (u *User) ToProfileResponse(followerCount, followingCount, postCount int, i
 		FollowingCount: followingCount,
 		PostCount:      postCount,
 		IsFollowing:    isFollowing,
+		EmailVerified:  u.IsEmailVerified(),
+		Role:           u.Role,
 	}
 }
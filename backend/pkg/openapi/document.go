@@ -0,0 +1,309 @@
+// backend/pkg/openapi/document.go
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"ripple/pkg/utils"
+)
+
+// Auth is the authentication a route accepts
+type Auth int
+
+const (
+	// AuthAny accepts a session cookie or a personal access token
+	AuthAny Auth = iota
+	// AuthSession only accepts a session cookie
+	AuthSession
+	// AuthNone is a public route
+	AuthNone
+)
+
+// Param is a query parameter; Type is a JSON schema type such as "integer"
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Object describes a JSON object by example: each value is a zero value of
+// the type of that property, e.g. Object{"posts": []*models.Post{}, "count": 0}
+type Object map[string]any
+
+// Route describes one API route. Request and Response hold zero values of the
+// types the handler decodes and answers with, so the schemas follow the code.
+type Route struct {
+	Summary    string
+	Auth       Auth
+	Query      []Param
+	Deprecated bool
+
+	// Request is the JSON body, Upload the multipart file field; nil and "" when there is none
+	Request any
+	Upload  string
+
+	// Status defaults to 200. Response is the data of the APIResponse envelope,
+	// or the whole body when ContentType is set.
+	Status      int
+	Response    any
+	ContentType string
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers,omitempty"`
+	Security   []map[string][]string            `json:"security,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+
+	types map[reflect.Type]string
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Security scheme names used by the document
+const (
+	sessionCookie = "sessionCookie"
+	bearerToken   = "bearerToken"
+)
+
+// New creates a document with the response envelope and the security schemes
+// of the API
+func New(title, version, serverURL string) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version},
+		Security: []map[string][]string{
+			{sessionCookie: {}},
+			{bearerToken: {}},
+		},
+		Paths: make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]*SecurityScheme{
+				sessionCookie: {Type: "apiKey", In: "cookie", Name: "session_id"},
+				bearerToken:   {Type: "http", Scheme: "bearer"},
+			},
+		},
+		types: make(map[reflect.Type]string),
+	}
+	if serverURL != "" {
+		doc.Servers = []Server{{URL: serverURL}}
+	}
+	doc.SchemaOf(utils.APIResponse{})
+	return doc
+}
+
+// AddRoute documents a route registered with a ServeMux pattern such as
+// "GET /api/groups/{id}". Patterns without a method are rejected, since they
+// would stand for every method.
+func (doc *Document) AddRoute(pattern string, route Route) error {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found || method != strings.ToUpper(method) {
+		return fmt.Errorf("openapi: pattern %q has no method", pattern)
+	}
+
+	op := &Operation{
+		OperationID: operationID(method, path),
+		Summary:     route.Summary,
+		Tags:        []string{tagOf(path)},
+		Deprecated:  route.Deprecated,
+		Responses:   make(map[string]*Response),
+	}
+	switch route.Auth {
+	case AuthSession:
+		op.Security = []map[string][]string{{sessionCookie: {}}}
+	case AuthNone:
+		op.Security = []map[string][]string{}
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			name = strings.TrimSuffix(name, "}")
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name: name, In: "path", Required: true, Schema: pathParamSchema(name, path),
+			})
+		}
+	}
+	for _, param := range route.Query {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: param.Name, In: "query", Description: param.Description, Schema: &Schema{Type: param.Type},
+		})
+	}
+
+	switch {
+	case route.Request != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+			"application/json": {Schema: doc.SchemaOf(route.Request)},
+		}}
+		op.Responses["400"] = doc.errorResponse("Invalid JSON or validation failed")
+	case route.Upload != "":
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+			"multipart/form-data": {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{route.Upload: {Type: "string", Format: "binary"}},
+				Required:   []string{route.Upload},
+			}},
+		}}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case route.ContentType != "":
+		media := &MediaType{}
+		if route.Response != nil {
+			media.Schema = doc.SchemaOf(route.Response)
+		}
+		success.Content = map[string]*MediaType{route.ContentType: media}
+	case status >= 200 && status < 300:
+		success.Content = map[string]*MediaType{"application/json": {Schema: doc.envelope(route.Response)}}
+	}
+	op.Responses[fmt.Sprint(status)] = success
+	op.Responses["default"] = doc.errorResponse("Error")
+
+	if doc.Paths[path] == nil {
+		doc.Paths[path] = make(map[string]*Operation)
+	}
+	doc.Paths[path][strings.ToLower(method)] = op
+	return nil
+}
+
+// Operation returns the documented operation for a method and path template
+func (doc *Document) Operation(method, path string) *Operation {
+	return doc.Paths[path][strings.ToLower(method)]
+}
+
+// envelope wraps the schema of data in the APIResponse envelope
+func (doc *Document) envelope(data any) *Schema {
+	ref := doc.SchemaOf(utils.APIResponse{})
+	if data == nil {
+		return ref
+	}
+	return &Schema{AllOf: []*Schema{ref, {
+		Type:       "object",
+		Properties: map[string]*Schema{"data": doc.SchemaOf(data)},
+	}}}
+}
+
+func (doc *Document) errorResponse(description string) *Response {
+	return &Response{Description: description, Content: map[string]*MediaType{
+		"application/json": {Schema: doc.SchemaOf(utils.APIResponse{})},
+	}}
+}
+
+// operationID turns "GET /api/v1/groups/{id}/members" into "getGroupsIdMembers"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(trimAPIPrefix(path), "/") {
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return !isAlphanumeric(r) }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// tagOf groups operations by the first path segment after the API prefix,
+// or after auth for the account routes
+func tagOf(path string) string {
+	segments := strings.Split(strings.Trim(trimAPIPrefix(path), "/"), "/")
+	if len(segments) > 1 && segments[0] == "auth" {
+		switch segments[1] {
+		case "sessions", "2fa", "tokens", "account", "password", "email", "oidc":
+			return segments[1]
+		}
+	}
+	return segments[0]
+}
+
+// trimAPIPrefix removes /api and a version segment such as /v1 from a path
+func trimAPIPrefix(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api")
+	if !ok {
+		return path
+	}
+	if version, after, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/"); len(version) > 1 && version[0] == 'v' &&
+		strings.Trim(version[1:], "0123456789") == "" {
+		return "/" + after
+	}
+	return rest
+}
+
+// pathParamSchema describes a path wildcard. Wildcards are numeric IDs
+// except for session IDs and named content types.
+func pathParamSchema(name, path string) *Schema {
+	if name == "id" && !strings.Contains(path, "/sessions/") {
+		return &Schema{Type: "integer", Minimum: ptr(1.0)}
+	}
+	return &Schema{Type: "string"}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,220 @@
+// backend/pkg/openapi/schema.go
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of the OpenAPI schema object the API needs
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	objectType    = reflect.TypeOf(Object{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// SchemaOf returns the schema of the JSON encoding of v. Named structs are
+// added to the components and referenced, so each is described once.
+//
+// Struct fields may refine their schema with an openapi tag, e.g.
+// `openapi:"required,enum=going|not_going,maxLength=100,format=email"`.
+func (doc *Document) SchemaOf(v any) *Schema {
+	if object, ok := v.(Object); ok {
+		return doc.objectSchema(object)
+	}
+	return doc.schemaOfType(reflect.TypeOf(v))
+}
+
+func (doc *Document) objectSchema(object Object) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(object))}
+	for name, value := range object {
+		schema.Properties[name] = doc.SchemaOf(value)
+	}
+	return schema
+}
+
+func (doc *Document) schemaOfType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+
+	schema := doc.schemaOfValueType(t)
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+func (doc *Document) schemaOfValueType(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawJSONType:
+		return &Schema{}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		// Custom encodings cannot be derived from the Go type
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: doc.schemaOfType(t.Elem())}
+	case reflect.Map:
+		if t == objectType {
+			return &Schema{Type: "object"}
+		}
+		return &Schema{Type: "object", AdditionalProperties: doc.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return doc.structSchema(t)
+		}
+		return doc.namedStructSchema(t)
+	}
+	// interface{} and anything else JSON can hold
+	return &Schema{}
+}
+
+// namedStructSchema adds t to the components and returns a reference to it
+func (doc *Document) namedStructSchema(t reflect.Type) *Schema {
+	if name, ok := doc.types[t]; ok {
+		return &Schema{Ref: refPrefix + name}
+	}
+
+	name := t.Name()
+	if _, taken := doc.Components.Schemas[name]; taken {
+		// Same name in another package, e.g. handlers.X and models.X
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Register before descending so recursive types end in a reference
+	doc.types[t] = name
+	doc.Components.Schemas[name] = &Schema{}
+	*doc.Components.Schemas[name] = *doc.structSchema(t)
+	return &Schema{Ref: refPrefix + name}
+}
+
+func (doc *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	doc.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields adds the fields of t the way encoding/json encodes them,
+// including those of embedded structs
+func (doc *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				doc.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := doc.schemaOfType(field.Type)
+		if strings.Contains(opts, "string") && fieldSchema.Type != "" {
+			fieldSchema = &Schema{Type: "string"}
+		}
+		if doc.applyTag(fieldSchema, field.Tag.Get("openapi")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// applyTag applies the options of an openapi struct tag and reports whether
+// the field is required
+func (doc *Document) applyTag(schema *Schema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "required":
+			required = true
+		case "enum":
+			schema.Enum = strings.Split(value, "|")
+		case "format":
+			schema.Format = value
+		case "maxLength":
+			if n, err := strconv.Atoi(value); err == nil {
+				schema.MaxLength = &n
+			}
+		case "minimum":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				schema.Minimum = &n
+			}
+		}
+	}
+	return required
+}
+
+// resolve follows a reference to a component schema
+func (doc *Document) resolve(schema *Schema) *Schema {
+	if name, ok := strings.CutPrefix(schema.Ref, refPrefix); ok {
+		if target, ok := doc.Components.Schemas[name]; ok {
+			return target
+		}
+	}
+	return schema
+}
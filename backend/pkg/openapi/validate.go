@@ -0,0 +1,144 @@
+// backend/pkg/openapi/validate.go
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"ripple/pkg/constants"
+	"ripple/pkg/utils"
+)
+
+// ValidateBody checks a JSON request body against the request schema of op.
+// It returns an error when the body is not JSON at all. Field names in the
+// validation errors are paths such as "user_ids[2]".
+func (doc *Document) ValidateBody(op *Operation, body []byte) (utils.ValidationErrors, error) {
+	media := op.RequestBody.Content["application/json"]
+	if media == nil || media.Schema == nil {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var errors utils.ValidationErrors
+	doc.validate(media.Schema, value, "", &errors)
+	return errors, nil
+}
+
+// validate mirrors how encoding/json would decode value into the documented
+// Go type: null is treated like a missing value, since decoding leaves the
+// field unchanged
+func (doc *Document) validate(schema *Schema, value any, field string, errors *utils.ValidationErrors) {
+	schema = doc.resolve(schema)
+	for _, part := range schema.AllOf {
+		doc.validate(part, value, field, errors)
+	}
+	if value == nil || schema.Type == "" {
+		return
+	}
+
+	fail := func(message string) {
+		name := field
+		if name == "" {
+			name = "body"
+		}
+		*errors = append(*errors, utils.ValidationError{Field: name, Message: message})
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range schema.Required {
+			if object[name] == nil {
+				*errors = append(*errors, utils.ValidationError{Field: joinField(field, name), Message: constants.ErrRequiredField})
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				doc.validate(property, object[name], joinField(field, name), errors)
+			} else if schema.AdditionalProperties != nil {
+				doc.validate(schema.AdditionalProperties, object[name], joinField(field, name), errors)
+			}
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				doc.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i), errors)
+			}
+		}
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+			fail("must be one of: " + strings.Join(schema.Enum, ", "))
+		}
+		if schema.MaxLength != nil && utf8.RuneCountInString(s) > *schema.MaxLength {
+			fail(fmt.Sprintf("must be at most %d characters long", *schema.MaxLength))
+		}
+
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		if schema.Type == "integer" {
+			if _, err := n.Int64(); err != nil {
+				fail("must be an integer")
+				return
+			}
+		}
+		if f, err := n.Float64(); err == nil && schema.Minimum != nil && f < *schema.Minimum {
+			fail(fmt.Sprintf("must be at least %v", *schema.Minimum))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// backend/pkg/router/cors.go
+package router
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"ripple/pkg/config"
+)
+
+// originList matches request origins against the configured frontends. An
+// entry is an exact origin such as "https://app.example.com" or a wildcard
+// subdomain pattern such as "https://*.example.com", which matches any
+// subdomain of example.com but not example.com itself.
+type originList struct {
+	exact     map[string]bool
+	wildcards []originWildcard
+}
+
+// originWildcard is a pattern split around its "*.", e.g. "https://" and
+// ".example.com"
+type originWildcard struct {
+	prefix string
+	suffix string
+}
+
+func newOriginList(origins []string) *originList {
+	list := &originList{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		prefix, suffix, found := strings.Cut(origin, "*.")
+		if !found {
+			list.exact[origin] = true
+			continue
+		}
+		if !strings.HasSuffix(prefix, "://") || strings.Contains(suffix, "*") {
+			log.Printf("CORS: ignoring invalid origin pattern %q", origin)
+			continue
+		}
+		list.wildcards = append(list.wildcards, originWildcard{prefix: prefix, suffix: "." + suffix})
+	}
+	return list
+}
+
+// Allows reports whether origin is one of the trusted origins
+func (list *originList) Allows(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	origin = strings.ToLower(origin)
+	if list.exact[origin] {
+		return true
+	}
+	for _, wildcard := range list.wildcards {
+		rest, ok := strings.CutPrefix(origin, wildcard.prefix)
+		if !ok {
+			continue
+		}
+		if subdomain, ok := strings.CutSuffix(rest, wildcard.suffix); ok && isSubdomain(subdomain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubdomain reports whether s is one or more DNS labels, so that a pattern
+// cannot be satisfied by a different port, a path or an empty label
+func isSubdomain(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// corsPolicy is the CORS configuration in the form the headers need
+type corsPolicy struct {
+	origins        *originList
+	allowedMethods string
+	allowedHeaders string
+	exposedHeaders string
+	maxAge         string
+}
+
+func newCORSPolicy(cfg *config.Config) corsPolicy {
+	return corsPolicy{
+		origins:        newOriginList(cfg.AllowedOrigins),
+		allowedMethods: strings.Join(cfg.CORSAllowedMethods, ", "),
+		allowedHeaders: strings.Join(cfg.CORSAllowedHeaders, ", "),
+		exposedHeaders: strings.Join(cfg.CORSExposedHeaders, ", "),
+		maxAge:         strconv.Itoa(int(cfg.CORSMaxAge / time.Second)),
+	}
+}
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"net/http"
+
+	"ripple/pkg/utils"
+)
+
+// jsonMuxErrors answers requests that match no route of mux with the same JSON
+// error body as the handlers, instead of ServeMux's plain text. ServeMux still
+// decides between 404 and 405 and computes the Allow header.
+func jsonMuxErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &muxErrorRecorder{header: make(http.Header)}
+		h.ServeHTTP(rec, r)
+
+		switch rec.status {
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		case http.StatusNotFound:
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Not found")
+		default:
+			// Redirects to the canonical path and the like
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// muxErrorRecorder keeps the status and headers of ServeMux's own error
+// replies and discards their body
+type muxErrorRecorder struct {
+	header http.Header
+	status int
+}
+
+func (rec *muxErrorRecorder) Header() http.Header { return rec.header }
+
+func (rec *muxErrorRecorder) Write(b []byte) (int, error) { return len(b), nil }
+
+func (rec *muxErrorRecorder) WriteHeader(status int) { rec.status = status }
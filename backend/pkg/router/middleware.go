@@ -11,27 +11,37 @@ func applyMiddleware(h http.Handler, middlewares ...func(http.Handler) http.Hand
     return h
 }
 
-func corsMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+// corsMiddleware lets the configured frontends call the API with credentials.
+// Responses always vary by Origin, since the allowed origin is echoed back.
+func corsMiddleware(policy corsPolicy) func(http.Handler) http.Handler {
     return func(next http.Handler) http.Handler {
         return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            w.Header().Add("Vary", "Origin")
+
             origin := r.Header.Get("Origin")
-            for _, allowed := range allowedOrigins {
-                if origin == allowed {
-                    w.Header().Set("Access-Control-Allow-Origin", origin)
-                    break
-                }
+            allowed := policy.origins.Allows(origin)
+            if allowed {
+                w.Header().Set("Access-Control-Allow-Origin", origin)
+                w.Header().Set("Access-Control-Allow-Credentials", "true")
             }
 
-            w.Header().Set("Access-Control-Allow-Credentials", "true")
-            w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-            w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-
             if r.Method == "OPTIONS" {
-                w.WriteHeader(http.StatusOK)
+                w.Header().Add("Vary", "Access-Control-Request-Method")
+                w.Header().Add("Vary", "Access-Control-Request-Headers")
+                if allowed {
+                    w.Header().Set("Access-Control-Allow-Methods", policy.allowedMethods)
+                    w.Header().Set("Access-Control-Allow-Headers", policy.allowedHeaders)
+                    w.Header().Set("Access-Control-Max-Age", policy.maxAge)
+                }
+                w.WriteHeader(http.StatusNoContent)
                 return
             }
 
+            if allowed && policy.exposedHeaders != "" {
+                w.Header().Set("Access-Control-Expose-Headers", policy.exposedHeaders)
+            }
+
             next.ServeHTTP(w, r)
         })
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,249 @@
+package router
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"ripple/pkg/handlers"
+	"ripple/pkg/models"
+	"ripple/pkg/openapi"
+	"ripple/pkg/scheduler"
+	"ripple/pkg/utils"
+)
+
+// routeMux is a ServeMux that remembers its patterns, so the OpenAPI document
+// can be built for exactly the routes that are served
+type routeMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func newRouteMux() *routeMux {
+	return &routeMux{ServeMux: http.NewServeMux()}
+}
+
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.ServeMux.Handle(pattern, handler)
+	m.patterns = append(m.patterns, pattern)
+}
+
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// buildOpenAPI documents every route of mux from apiDocs. Like ServeMux with
+// an invalid pattern, it panics when a route has no description, so new routes
+// cannot be added without one.
+func buildOpenAPI(mux *routeMux, serverURL string) *openapi.Document {
+	doc := openapi.New("Ripple API", "1.0.0", serverURL)
+	for _, pattern := range mux.patterns {
+		route, ok := apiDocs[pattern]
+		if !ok {
+			panic(fmt.Sprintf("router: route %q has no OpenAPI description in apiDocs", pattern))
+		}
+		_, route.Deprecated = deprecatedRoutes[pattern]
+		if err := doc.AddRoute(pattern, route); err != nil {
+			panic(err)
+		}
+	}
+	return doc
+}
+
+// maxValidatedBodySize limits the JSON bodies requestValidationMiddleware
+// reads into memory; larger ones are answered with 413
+const maxValidatedBodySize = 1 << 20
+
+// requestValidationMiddleware checks JSON request bodies against the request
+// schema of the matched route and answers 400 with the failing fields, in
+// the same format as the handlers' own validation
+func requestValidationMiddleware(doc *openapi.Document, mux *routeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+			method, path, _ := strings.Cut(pattern, " ")
+			op := doc.Operation(method, path)
+			if op == nil || op.RequestBody == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValidatedBodySize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					utils.WriteErrorResponse(w, http.StatusRequestEntityTooLarge, "Request body too large")
+					return
+				}
+				utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			validationErrors, err := doc.ValidateBody(op, body)
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+				return
+			}
+			if validationErrors.HasErrors() {
+				utils.WriteValidationErrorResponse(w, validationErrors)
+				return
+			}
+
+			// The handler decodes the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+var (
+	pagination  = []openapi.Param{{Name: "limit", Type: "integer"}, {Name: "offset", Type: "integer"}}
+	searchQuery = append([]openapi.Param{{Name: "q", Type: "string", Description: "Search text"}}, pagination...)
+
+	// message is the data of responses that only confirm an action
+	message = openapi.Object{"message": ""}
+
+	// uploaded is the data of the upload routes; file_path is the URL path of the file
+	uploaded = openapi.Object{"file_path": "", "message": "", "user_id": 0}
+)
+
+// apiDocs describes every API route, keyed by its ServeMux pattern. Request
+// and Response hold zero values of the types the handler decodes and
+// answers with.
+var apiDocs = map[string]openapi.Route{
+	"GET /api/v1/openapi.json": {Summary: "This OpenAPI document", Auth: openapi.AuthNone, Response: openapi.Object{}, ContentType: "application/json"},
+	"GET /ws":                  {Summary: "Open the WebSocket connection", Query: []openapi.Param{{Name: "ticket", Type: "string", Description: "One-time ticket from POST /api/v1/auth/ws-ticket"}}, Status: http.StatusSwitchingProtocols},
+
+	// Authentication
+	"POST /api/v1/auth/register":        {Summary: "Register an account", Auth: openapi.AuthNone, Request: handlers.RegisterRequest{}, Status: http.StatusCreated, Response: handlers.AuthResponse{}},
+	"POST /api/v1/auth/login":           {Summary: "Log in, or start a two-factor challenge", Auth: openapi.AuthNone, Request: handlers.LoginRequest{}, Response: handlers.AuthResponse{}},
+	"POST /api/v1/auth/logout":          {Summary: "Log out", Auth: openapi.AuthNone, Response: message},
+	"POST /api/v1/auth/password/forgot": {Summary: "Email a password reset link", Auth: openapi.AuthNone, Request: handlers.ForgotPasswordRequest{}, Response: message},
+	"POST /api/v1/auth/password/reset":  {Summary: "Reset the password with a reset token", Auth: openapi.AuthNone, Request: handlers.ResetPasswordRequest{}, Response: message},
+	"POST /api/v1/auth/2fa/verify":      {Summary: "Complete a two-factor login challenge", Auth: openapi.AuthNone, Request: handlers.VerifyTwoFactorRequest{}, Response: handlers.AuthResponse{}},
+	"POST /api/v1/auth/email/verify":    {Summary: "Verify the email address", Auth: openapi.AuthNone, Request: handlers.VerifyEmailRequest{}, Response: message},
+	"GET /api/v1/auth/oidc/login":       {Summary: "Start login with the identity provider", Auth: openapi.AuthNone, Status: http.StatusFound},
+	"GET /api/v1/auth/oidc/callback":    {Summary: "Finish login with the identity provider", Auth: openapi.AuthNone, Query: []openapi.Param{{Name: "code", Type: "string"}, {Name: "state", Type: "string"}}, Status: http.StatusFound},
+
+	// Profiles
+	"GET /api/v1/auth/profile":        {Summary: "Get the current user's profile", Response: models.ProfileResponse{}},
+	"PUT /api/v1/auth/profile/update": {Summary: "Update the current user's profile", Request: handlers.UpdateProfileRequest{}, Response: models.UserResponse{}},
+	"GET /api/v1/auth/search":         {Summary: "Search users", Query: searchQuery, Response: openapi.Object{"users": []*handlers.SearchUserResponse{}, "query": "", "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/users/{id}":          {Summary: "Get a user's profile", Response: models.ProfileResponse{}},
+
+	// Account security
+	"GET /api/v1/auth/sessions":                {Summary: "List the current user's sessions", Auth: openapi.AuthSession, Response: openapi.Object{"sessions": []*models.SessionResponse{}, "count": 0}},
+	"DELETE /api/v1/auth/sessions/revoke/{id}": {Summary: "Sign out one session", Auth: openapi.AuthSession, Response: message},
+	"POST /api/v1/auth/sessions/revoke-others": {Summary: "Sign out every other session", Auth: openapi.AuthSession, Response: openapi.Object{"message": "", "revoked_count": int64(0)}},
+	"POST /api/v1/auth/ws-ticket":              {Summary: "Issue a one-time WebSocket ticket", Auth: openapi.AuthSession, Status: http.StatusCreated, Response: openapi.Object{"ticket": "", "expires_at": ""}},
+	"POST /api/v1/auth/password/change":        {Summary: "Change the password", Auth: openapi.AuthSession, Request: handlers.ChangePasswordRequest{}, Response: message},
+	"GET /api/v1/auth/2fa/status":              {Summary: "Get the two-factor authentication status", Auth: openapi.AuthSession, Response: models.TwoFactorStatusResponse{}},
+	"POST /api/v1/auth/2fa/setup":              {Summary: "Create a TOTP secret to confirm", Auth: openapi.AuthSession, Response: handlers.TwoFactorSetupResponse{}},
+	"POST /api/v1/auth/2fa/confirm":            {Summary: "Enable two-factor authentication", Auth: openapi.AuthSession, Request: handlers.TwoFactorCodeRequest{}, Response: handlers.RecoveryCodesResponse{}},
+	"POST /api/v1/auth/2fa/disable":            {Summary: "Disable two-factor authentication", Auth: openapi.AuthSession, Request: handlers.DisableTwoFactorRequest{}, Response: message},
+	"POST /api/v1/auth/2fa/recovery-codes":     {Summary: "Replace the recovery codes", Auth: openapi.AuthSession, Request: handlers.TwoFactorCodeRequest{}, Response: handlers.RecoveryCodesResponse{}},
+	"POST /api/v1/auth/email/resend":           {Summary: "Resend the verification email", Auth: openapi.AuthSession, Response: message},
+	"GET /api/v1/auth/tokens":                  {Summary: "List personal access tokens", Auth: openapi.AuthSession, Response: openapi.Object{"tokens": []*models.AccessTokenResponse{}, "count": 0, "available_scopes": []string{}}},
+	"POST /api/v1/auth/tokens/create":          {Summary: "Create a personal access token", Auth: openapi.AuthSession, Request: models.CreateAccessTokenRequest{}, Status: http.StatusCreated, Response: openapi.Object{"token": "", "access_token": &models.AccessTokenResponse{}, "message": ""}},
+	"DELETE /api/v1/auth/tokens/revoke/{id}":   {Summary: "Revoke a personal access token", Auth: openapi.AuthSession, Response: message},
+	"POST /api/v1/auth/account/delete":         {Summary: "Schedule the account for deletion; accounts created by OIDC set a password with /auth/password/forgot first", Auth: openapi.AuthSession, Request: handlers.DeleteAccountRequest{}, Response: openapi.Object{"message": "", "scheduled_at": ""}},
+	"POST /api/v1/auth/account/export":         {Summary: "Request an export of the user's data", Auth: openapi.AuthSession, Status: http.StatusAccepted, Response: models.DataExport{}},
+	"GET /api/v1/auth/account/exports":         {Summary: "List data exports", Auth: openapi.AuthSession, Response: openapi.Object{"exports": []*models.DataExport{}}},
+	"GET /api/v1/auth/account/export/download": {Summary: "Download a data export archive", Auth: openapi.AuthSession, Query: []openapi.Param{{Name: "id", Type: "integer"}}, ContentType: "application/zip"},
+
+	// Follows
+	"POST /api/v1/follow":               {Summary: "Follow a user, or request to", Request: handlers.FollowUserRequest{}, Status: http.StatusCreated, Response: openapi.Object{"follow_request": &models.FollowRequest{}, "message": ""}},
+	"POST /api/v1/unfollow":             {Summary: "Unfollow a user", Request: handlers.FollowUserRequest{}, Response: message},
+	"POST /api/v1/follow/handle":        {Summary: "Accept or decline a follow request", Request: handlers.FollowActionRequest{}, Response: message},
+	"GET /api/v1/follow/requests":       {Summary: "List pending follow requests", Response: openapi.Object{"follow_requests": []*models.FollowRequest{}}},
+	"GET /api/v1/follow/followers/{id}": {Summary: "List a user's followers", Response: openapi.Object{"followers": []*models.UserResponse{}}},
+	"GET /api/v1/follow/following/{id}": {Summary: "List who a user follows", Response: openapi.Object{"following": []*models.UserResponse{}}},
+	"GET /api/v1/follow/stats/{id}":     {Summary: "Get a user's follow counts", Response: models.FollowStats{}},
+	"GET /api/v1/follow/status/{id}":    {Summary: "Get the follow relationship with a user", Response: openapi.Object{"following_status": "", "follower_status": "", "is_following": false, "is_follower": false}},
+
+	// Posts
+	"POST /api/v1/posts":                 {Summary: "Create a post", Request: models.CreatePostRequest{}, Status: http.StatusCreated, Response: openapi.Object{"post": &models.Post{}, "message": ""}},
+	"GET /api/v1/posts/{id}":             {Summary: "Get a post", Response: models.Post{}},
+	"GET /api/v1/posts/feed":             {Summary: "Get the current user's feed", Query: pagination, Response: openapi.Object{"posts": []*models.Post{}, "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/posts/search":           {Summary: "Search posts", Query: searchQuery, Response: openapi.Object{"posts": []*models.Post{}, "query": "", "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/posts/user/{id}":        {Summary: "List a user's posts", Query: pagination, Response: openapi.Object{"posts": []*models.Post{}, "limit": 0, "offset": 0, "count": 0}},
+	"PUT /api/v1/posts/update":           {Summary: "Edit a post", Request: handlers.UpdatePostRequest{}, Response: models.Post{}},
+	"DELETE /api/v1/posts/delete/{id}":   {Summary: "Delete a post", Response: message},
+	"POST /api/v1/posts/comments/create": {Summary: "Comment on a post", Request: models.CreateCommentRequest{}, Status: http.StatusCreated, Response: openapi.Object{"comment": &models.Comment{}, "message": ""}},
+	"GET /api/v1/posts/comments/{id}":    {Summary: "List a post's comments", Query: pagination, Response: openapi.Object{"comments": []*models.Comment{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/posts/like":            {Summary: "Like or unlike a post", Request: handlers.ToggleLikeRequest{}, Response: handlers.ToggleLikeResponse{}},
+
+	// Groups
+	"POST /api/v1/groups":                     {Summary: "Create a group", Request: models.CreateGroupRequest{}, Status: http.StatusCreated, Response: openapi.Object{"group": &models.Group{}, "message": ""}},
+	"GET /api/v1/groups/all":                  {Summary: "List all groups", Query: pagination, Response: openapi.Object{"groups": []*models.Group{}, "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/groups/user":                 {Summary: "List the current user's groups", Query: pagination, Response: openapi.Object{"groups": []*models.Group{}, "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/groups/recommendations":      {Summary: "Recommend groups to join", Query: pagination[:1], Response: openapi.Object{"recommendations": []*models.GroupRecommendation{}, "count": 0}},
+	"GET /api/v1/groups/search":               {Summary: "Search groups", Query: searchQuery, Response: openapi.Object{"groups": []*models.Group{}, "query": "", "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/groups/invitations":          {Summary: "List pending group invitations", Response: openapi.Object{"invitations": []*models.GroupMember{}}},
+	"POST /api/v1/groups/invite":              {Summary: "Invite users to a group", Request: models.InviteToGroupRequest{}, Response: message},
+	"POST /api/v1/groups/join":                {Summary: "Request to join a group", Request: models.JoinGroupRequest{}, Response: message},
+	"POST /api/v1/groups/handle":              {Summary: "Accept or decline an invitation or join request", Request: models.GroupActionRequest{}, Response: message},
+	"GET /api/v1/groups/{id}":                 {Summary: "Get a group", Response: models.Group{}},
+	"PUT /api/v1/groups/{id}":                 {Summary: "Update a group", Request: models.UpdateGroupRequest{}, Response: openapi.Object{"group": &models.Group{}, "message": ""}},
+	"GET /api/v1/groups/{id}/members":         {Summary: "List a group's members", Response: openapi.Object{"members": []*models.GroupMember{}}},
+	"GET /api/v1/groups/{id}/requests":        {Summary: "List a group's pending join requests", Response: openapi.Object{"join_requests": []*models.GroupMember{}}},
+	"POST /api/v1/groups/{id}/invite":         {Summary: "Invite users to this group", Request: handlers.InviteUsersRequest{}, Response: openapi.Object{"message": "", "invited_count": 0}},
+	"POST /api/v1/groups/{id}/leave":          {Summary: "Leave a group", Response: message},
+	"GET /api/v1/groups/{id}/posts":           {Summary: "List a group's posts", Query: pagination, Response: openapi.Object{"posts": []*models.GroupPost{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/groups/{id}/posts":          {Summary: "Post in a group", Request: models.CreateGroupPostRequest{}, Status: http.StatusCreated, Response: openapi.Object{"post": &models.GroupPost{}, "message": ""}},
+	"PUT /api/v1/groups/posts/update":         {Summary: "Edit a group post", Request: handlers.UpdateGroupPostRequest{}, Response: openapi.Object{"post": &models.GroupPost{}, "message": ""}},
+	"DELETE /api/v1/groups/posts/delete/{id}": {Summary: "Delete a group post", Response: message},
+	"POST /api/v1/groups/posts/like":          {Summary: "Like or unlike a group post", Request: handlers.GroupPostLikeRequest{}, Response: openapi.Object{"liked": false, "like_count": 0}},
+	"GET /api/v1/groups/posts/{id}/comments":  {Summary: "List a group post's comments", Query: pagination, Response: openapi.Object{"comments": []*models.GroupPostComment{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/groups/posts/{id}/comments": {Summary: "Comment on a group post", Request: models.CreateGroupCommentRequest{}, Status: http.StatusCreated, Response: openapi.Object{"comment": &models.GroupPostComment{}, "message": ""}},
+	"POST /api/groups/leave/{id}":             {Summary: "Leave a group", Response: message},
+	"POST /api/groups/posts/{id}":             {Summary: "Post in a group", Request: models.CreateGroupPostRequest{}, Status: http.StatusCreated, Response: openapi.Object{"post": &models.GroupPost{}, "message": ""}},
+	"GET /api/groups/posts/get/{id}":          {Summary: "List a group's posts", Query: pagination, Response: openapi.Object{"posts": []*models.GroupPost{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/groups/comments/{id}":          {Summary: "Comment on a group post", Request: models.CreateGroupCommentRequest{}, Status: http.StatusCreated, Response: openapi.Object{"comment": &models.GroupPostComment{}, "message": ""}},
+	"GET /api/groups/comments/get/{id}":       {Summary: "List a group post's comments", Query: pagination, Response: openapi.Object{"comments": []*models.GroupPostComment{}, "limit": 0, "offset": 0, "count": 0}},
+
+	// Events
+	"GET /api/v1/events":                {Summary: "List events in the current user's groups", Query: pagination, Response: []*models.Event{}},
+	"POST /api/v1/events/{id}":          {Summary: "Create an event in a group", Request: models.CreateEventRequest{}, Status: http.StatusCreated, Response: openapi.Object{"event": &models.Event{}, "message": ""}},
+	"GET /api/v1/events/get/{id}":       {Summary: "Get an event", Response: models.Event{}},
+	"GET /api/v1/events/group/{id}":     {Summary: "List a group's events", Query: pagination, Response: openapi.Object{"events": []*models.Event{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/events/respond/{id}":  {Summary: "Answer an event invitation", Request: models.EventResponseRequest{}, Response: message},
+	"GET /api/v1/events/responses/{id}": {Summary: "List the answers to an event", Response: openapi.Object{"going": []*models.EventResponse{}, "not_going": []*models.EventResponse{}}},
+
+	// Uploads
+	"POST /api/v1/upload/avatar":       {Summary: "Upload an avatar", Upload: "avatar", Response: uploaded},
+	"POST /api/v1/upload/cover":        {Summary: "Upload a profile cover", Upload: "cover", Response: uploaded},
+	"POST /api/v1/upload/post":         {Summary: "Upload a post image", Upload: "image", Response: uploaded},
+	"POST /api/v1/upload/comment":      {Summary: "Upload a comment image", Upload: "image", Response: uploaded},
+	"POST /api/v1/upload/group-avatar": {Summary: "Upload a group avatar", Upload: "group-avatar", Response: uploaded},
+	"POST /api/v1/upload/group-cover":  {Summary: "Upload a group cover", Upload: "group-cover", Response: uploaded},
+
+	// Notifications
+	"GET /api/v1/notifications":                {Summary: "List notifications", Query: pagination, Response: openapi.Object{"notifications": []*models.Notification{}, "unread_count": 0, "limit": 0, "offset": 0, "count": 0}},
+	"PUT /api/v1/notifications/read/{id}":      {Summary: "Mark a notification as read", Response: message},
+	"PUT /api/v1/notifications/read-all":       {Summary: "Mark every notification as read", Response: message},
+	"DELETE /api/v1/notifications/delete/{id}": {Summary: "Delete a notification", Response: message},
+
+	// Chat
+	"GET /api/v1/chat/conversations":         {Summary: "List conversations", Query: pagination, Response: openapi.Object{"conversations": []*models.Conversation{}, "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/chat/messages/private/{id}": {Summary: "List private messages with a user", Query: pagination, Response: openapi.Object{"messages": []*models.PrivateMessage{}, "limit": 0, "offset": 0, "count": 0}},
+	"GET /api/v1/chat/messages/group/{id}":   {Summary: "List a group's chat messages", Query: pagination, Response: openapi.Object{"messages": []*models.GroupMessage{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/chat/messages/private":     {Summary: "Send a private message", Request: models.CreatePrivateMessageRequest{}, Status: http.StatusCreated, Response: openapi.Object{"message": &models.PrivateMessage{}}},
+	"POST /api/v1/chat/messages/group":       {Summary: "Send a group chat message", Request: models.CreateGroupMessageRequest{}, Status: http.StatusCreated, Response: openapi.Object{"message": &models.GroupMessage{}}},
+	"GET /api/v1/chat/online":                {Summary: "List followed users who are online", Response: openapi.Object{"online_users": []*models.UserResponse{}, "count": 0}},
+	"POST /api/v1/chat/typing":               {Summary: "Send a typing indicator", Request: handlers.TypingIndicatorRequest{}, Response: message},
+	"GET /api/v1/chat/unread":                {Summary: "Count unread messages", Response: models.UnreadCounts{}},
+	"GET /api/v1/chat/followed-users":        {Summary: "List users to start a chat with", Response: openapi.Object{"users": []*models.UserResponse{}, "count": 0}},
+
+	// Moderation
+	"GET /api/v1/admin/users":                  {Summary: "List accounts", Auth: openapi.AuthSession, Query: searchQuery, Response: openapi.Object{"users": []*models.AdminUser{}, "limit": 0, "offset": 0, "count": 0}},
+	"POST /api/v1/admin/users/suspend/{id}":    {Summary: "Suspend an account", Auth: openapi.AuthSession, Request: handlers.SuspendUserRequest{}, Response: message},
+	"POST /api/v1/admin/users/unsuspend/{id}":  {Summary: "Lift a suspension", Auth: openapi.AuthSession, Response: message},
+	"POST /api/v1/admin/users/logout/{id}":     {Summary: "Sign a user out everywhere", Auth: openapi.AuthSession, Response: message},
+	"PUT /api/v1/admin/users/role/{id}":        {Summary: "Change a user's role", Auth: openapi.AuthSession, Request: handlers.SetRoleRequest{}, Response: openapi.Object{"message": "", "role": ""}},
+	"DELETE /api/v1/admin/content/{type}/{id}": {Summary: "Delete a post, comment, group post or group comment", Auth: openapi.AuthSession, Response: message},
+	"GET /api/v1/admin/stats":                  {Summary: "Get site statistics", Auth: openapi.AuthSession, Response: models.AdminStats{}},
+	"GET /api/v1/admin/jobs":                   {Summary: "Get the status of background jobs", Auth: openapi.AuthSession, Response: openapi.Object{"jobs": []scheduler.JobStatus{}}},
+}
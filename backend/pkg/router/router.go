@@ -2,10 +2,14 @@ package router
 
 import (
 	"net/http"
+	"strings"
 
 	"ripple/pkg/auth"
 	"ripple/pkg/config"
+	"ripple/pkg/constants"
 	"ripple/pkg/handlers"
+	"ripple/pkg/openapi"
+	"ripple/pkg/utils"
 	"ripple/pkg/websocket"
 )
 
@@ -13,6 +17,10 @@ import (
 func SetupRoutes(
 	cfg *config.Config,
 	authHandler *handlers.AuthHandler,
+	passwordHandler *handlers.PasswordHandler,
+	oidcHandler *handlers.OIDCHandler,
+	accountHandler *handlers.AccountHandler,
+	dataExportHandler *handlers.DataExportHandler,
 	followHandler *handlers.FollowHandler,
 	postHandler *handlers.PostHandler,
 	likeHandler *handlers.LikeHandler,
@@ -21,85 +29,223 @@ func SetupRoutes(
 	notificationHandler *handlers.NotificationHandler,
 	uploadHandler *handlers.UploadHandler,
 	chatHandler *handlers.ChatHandler,
+	adminHandler *handlers.AdminHandler,
+	metricsHandler *handlers.MetricsHandler,
+	healthHandler *handlers.HealthHandler,
 	sessionManager *auth.SessionManager,
 	wsHub *websocket.Hub,
 ) http.Handler {
 	mainMux := http.NewServeMux()
 
 	// Create separate mux for API routes that need JSON middleware
-	apiMux := http.NewServeMux()
+	apiMux := newRouteMux()
+
+	// Rate limits per route group, keyed by user when authenticated and by client IP otherwise
+	authRateLimit := handlers.RateLimitMiddleware(handlers.NewRateLimiter("auth", cfg.RateLimitAuth))
+	postsRateLimit := handlers.RateLimitMiddleware(handlers.NewRateLimiter("posts", cfg.RateLimitPosts))
+	messagesRateLimit := handlers.RateLimitMiddleware(handlers.NewRateLimiter("messages", cfg.RateLimitMessages))
 
 	// Auth routes (no auth required)
-	apiMux.HandleFunc("/api/auth/register", authHandler.Register)
-	apiMux.HandleFunc("/api/auth/login", authHandler.Login)
-	apiMux.HandleFunc("/api/auth/logout", authHandler.Logout)
+	apiMux.Handle("POST /api/v1/auth/register", authRateLimit(http.HandlerFunc(authHandler.Register)))
+	apiMux.Handle("POST /api/v1/auth/login", authRateLimit(http.HandlerFunc(authHandler.Login)))
+	apiMux.HandleFunc("POST /api/v1/auth/logout", authHandler.Logout)
+	apiMux.Handle("POST /api/v1/auth/password/forgot", authRateLimit(http.HandlerFunc(passwordHandler.ForgotPassword)))
+	apiMux.Handle("POST /api/v1/auth/password/reset", authRateLimit(http.HandlerFunc(passwordHandler.ResetPassword)))
+	apiMux.Handle("POST /api/v1/auth/2fa/verify", authRateLimit(http.HandlerFunc(authHandler.VerifyTwoFactor)))
+	apiMux.HandleFunc("POST /api/v1/auth/email/verify", authHandler.VerifyEmail)
+
+	// External identity provider login, only when one is configured
+	if oidcHandler != nil {
+		apiMux.HandleFunc("GET /api/v1/auth/oidc/login", oidcHandler.Login)
+		apiMux.HandleFunc("GET /api/v1/auth/oidc/callback", oidcHandler.Callback)
+	}
 
 	// Protected routes (auth required)
 	authMiddleware := sessionManager.AuthMiddleware
 
+	// Account management is only available to signed-in users, never to personal access tokens
+	sessionMiddleware := func(next http.Handler) http.Handler {
+		return authMiddleware(auth.RequireSession(next))
+	}
+
+	// scoped authenticates the request and limits personal access tokens to the given scopes
+	scoped := func(readScope, writeScope string) func(http.Handler) http.Handler {
+		requireScopes := auth.RequireScopes(readScope, writeScope)
+		return func(next http.Handler) http.Handler {
+			return authMiddleware(requireScopes(next))
+		}
+	}
+
+	// Creating content and sending messages additionally requires a verified email
+	verified := func(authenticate func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return authenticate(sessionManager.RequireVerifiedEmail(next))
+		}
+	}
+
+	// limited applies a rate limit after authentication so it is counted per user
+	limited := func(authenticate, rateLimit func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return authenticate(rateLimit(next))
+		}
+	}
+
+	// staff limits a route to signed-in users with at least the given site-wide role
+	staff := func(minRole string) func(http.Handler) http.Handler {
+		requireRole := sessionManager.RequireRole(minRole)
+		return func(next http.Handler) http.Handler {
+			return sessionMiddleware(requireRole(next))
+		}
+	}
+
 	// User routes
-	apiMux.Handle("/api/auth/profile", authMiddleware(http.HandlerFunc(authHandler.GetProfile)))
-	apiMux.Handle("/api/auth/profile/update", authMiddleware(http.HandlerFunc(authHandler.UpdateProfile)))
-	apiMux.Handle("/api/auth/search", authMiddleware(http.HandlerFunc(authHandler.SearchUsers)))
-	apiMux.Handle("/api/users/", authMiddleware(http.HandlerFunc(authHandler.GetUserProfile)))
+	profileMiddleware := scoped(auth.ScopeProfileRead, auth.ScopeProfileWrite)
+	apiMux.Handle("GET /api/v1/auth/profile", profileMiddleware(handlers.ConditionalGETMiddleware(http.HandlerFunc(authHandler.GetProfile))))
+	apiMux.Handle("PUT /api/v1/auth/profile/update", profileMiddleware(http.HandlerFunc(authHandler.UpdateProfile)))
+	apiMux.Handle("GET /api/v1/auth/search", profileMiddleware(http.HandlerFunc(authHandler.SearchUsers)))
+	apiMux.Handle("GET /api/v1/users/{id}", profileMiddleware(handlers.ConditionalGETMiddleware(http.HandlerFunc(authHandler.GetUserProfile))))
+
+	// Account security routes
+	apiMux.Handle("GET /api/v1/auth/sessions", sessionMiddleware(http.HandlerFunc(authHandler.GetSessions)))
+	apiMux.Handle("DELETE /api/v1/auth/sessions/revoke/{id}", sessionMiddleware(http.HandlerFunc(authHandler.RevokeSession)))
+	apiMux.Handle("POST /api/v1/auth/sessions/revoke-others", sessionMiddleware(http.HandlerFunc(authHandler.RevokeOtherSessions)))
+	apiMux.Handle("POST /api/v1/auth/ws-ticket", sessionMiddleware(http.HandlerFunc(authHandler.IssueWebSocketTicket)))
+	apiMux.Handle("POST /api/v1/auth/password/change", sessionMiddleware(http.HandlerFunc(passwordHandler.ChangePassword)))
+	apiMux.Handle("GET /api/v1/auth/2fa/status", sessionMiddleware(http.HandlerFunc(authHandler.GetTwoFactorStatus)))
+	apiMux.Handle("POST /api/v1/auth/2fa/setup", sessionMiddleware(http.HandlerFunc(authHandler.SetupTwoFactor)))
+	apiMux.Handle("POST /api/v1/auth/2fa/confirm", sessionMiddleware(http.HandlerFunc(authHandler.ConfirmTwoFactor)))
+	apiMux.Handle("POST /api/v1/auth/2fa/disable", sessionMiddleware(http.HandlerFunc(authHandler.DisableTwoFactor)))
+	apiMux.Handle("POST /api/v1/auth/2fa/recovery-codes", sessionMiddleware(http.HandlerFunc(authHandler.RegenerateRecoveryCodes)))
+	apiMux.Handle("POST /api/v1/auth/email/resend", sessionMiddleware(http.HandlerFunc(authHandler.ResendVerificationEmail)))
+	apiMux.Handle("GET /api/v1/auth/tokens", sessionMiddleware(http.HandlerFunc(authHandler.GetAccessTokens)))
+	apiMux.Handle("POST /api/v1/auth/tokens/create", sessionMiddleware(http.HandlerFunc(authHandler.CreateAccessToken)))
+	apiMux.Handle("DELETE /api/v1/auth/tokens/revoke/{id}", sessionMiddleware(http.HandlerFunc(authHandler.RevokeAccessToken)))
+	apiMux.Handle("POST /api/v1/auth/account/delete", sessionMiddleware(http.HandlerFunc(accountHandler.RequestDeletion)))
+	apiMux.Handle("POST /api/v1/auth/account/export", sessionMiddleware(http.HandlerFunc(dataExportHandler.RequestExport)))
+	apiMux.Handle("GET /api/v1/auth/account/exports", sessionMiddleware(http.HandlerFunc(dataExportHandler.GetExports)))
+	apiMux.Handle("GET /api/v1/auth/account/export/download", sessionMiddleware(http.HandlerFunc(dataExportHandler.DownloadExport)))
 
 	// Follow routes
-	setupFollowRoutes(apiMux, followHandler, authMiddleware)
+	setupFollowRoutes(apiMux, followHandler, scoped(auth.ScopeFollowsRead, auth.ScopeFollowsWrite))
 
 	// Post routes
-	setupPostRoutes(apiMux, postHandler, authMiddleware)
+	postsMiddleware := scoped(auth.ScopePostsRead, auth.ScopePostsWrite)
+	setupPostRoutes(apiMux, postHandler, postsMiddleware, limited(verified(postsMiddleware), postsRateLimit))
 
 	// Like routes
-	setupLikeRoutes(apiMux, likeHandler, authMiddleware)
+	setupLikeRoutes(apiMux, likeHandler, postsMiddleware)
 
 	// Group routes
-	setupGroupRoutes(apiMux, groupHandler, authMiddleware)
+	groupsMiddleware := scoped(auth.ScopeGroupsRead, auth.ScopeGroupsWrite)
+	setupGroupRoutes(apiMux, groupHandler, groupsMiddleware, limited(verified(groupsMiddleware), postsRateLimit))
 
 	// Event routes
-	setupEventRoutes(apiMux, eventHandler, authMiddleware)
+	setupEventRoutes(apiMux, eventHandler, scoped(auth.ScopeEventsRead, auth.ScopeEventsWrite))
 
 	// Upload routes
-	setupUploadRoutes(apiMux, uploadHandler, authMiddleware)
+	setupUploadRoutes(apiMux, uploadHandler, scoped("", auth.ScopeUploadsWrite))
 
 	// Notification routes
-	setupNotificationRoutes(apiMux, notificationHandler, authMiddleware)
+	setupNotificationRoutes(apiMux, notificationHandler, scoped(auth.ScopeNotificationsRead, auth.ScopeNotificationsWrite))
 
 	// Chat API routes (REST endpoints)
-	setupChatRoutes(apiMux, chatHandler, authMiddleware)
+	chatMiddleware := scoped(auth.ScopeChatRead, auth.ScopeChatWrite)
+	setupChatRoutes(apiMux, chatHandler, chatMiddleware, limited(verified(chatMiddleware), messagesRateLimit))
+
+	// Moderation and admin routes
+	setupAdminRoutes(apiMux, adminHandler, staff(constants.RoleModerator), staff(constants.RoleAdmin))
 
 	// WebSocket route (no JSON middleware needed)
-	apiMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+	apiMux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
 		websocket.HandleWebSocket(wsHub, sessionManager, w, r)
 	})
 
+	// OpenAPI description of every route, including this one, built from apiDocs
+	var apiDoc *openapi.Document
+	apiMux.HandleFunc("GET /api/v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteJSONResponse(w, http.StatusOK, apiDoc)
+	})
+	apiDoc = buildOpenAPI(apiMux, strings.TrimSuffix(cfg.APIURL, "/"))
+
+	var routes http.Handler = jsonMuxErrors(apiMux.ServeMux)
+	if cfg.ValidateRequests {
+		routes = requestValidationMiddleware(apiDoc, apiMux)(routes)
+	}
+	routes = deprecationMiddleware(apiMux, deprecatedRoutes, cfg.LegacyAPISunset)(routes)
+
+	// The frontends trusted for CORS are also trusted by the CSRF check
+	cors := newCORSPolicy(cfg)
+
 	// Apply middleware stack to API routes only:
 	// 1. PanicRecoveryMiddleware: Recovers from panics and logs them.
 	// 2. SecurityHeadersMiddleware: Adds security-related headers to responses.
-	// 3. corsMiddleware: Handles Cross-Origin Resource Sharing (CORS) based on allowed origins.
-	// 4. JSONMiddleware: Ensures all API responses are in JSON format.
-	apiHandler := applyMiddleware(apiMux,
+	// 3. corsMiddleware: Handles Cross-Origin Resource Sharing (CORS) for the allowed origins and subdomain patterns.
+	// 4. csrfMiddleware: Blocks state-changing requests sent by other sites with the session cookie.
+	// 5. RateLimitMiddleware: Limits the overall request rate of each client IP.
+	// 6. CompressionMiddleware: Compresses responses with brotli or gzip when the client accepts it.
+	// 7. JSONMiddleware: Ensures all API responses are in JSON format.
+	// 8. deprecationMiddleware: Serves unversioned /api/ aliases and flags deprecated routes.
+	// 9. requestValidationMiddleware: Optionally checks JSON bodies against the OpenAPI document.
+	// 10. jsonMuxErrors: Answers unknown routes and unsupported methods with JSON errors.
+	apiHandler := applyMiddleware(routes,
 		handlers.PanicRecoveryMiddleware,
 		handlers.SecurityHeadersMiddleware,
-		corsMiddleware(cfg.AllowedOrigins),
+		corsMiddleware(cors),
+		csrfMiddleware(cors.origins),
+		handlers.RateLimitMiddleware(handlers.NewRateLimiter("default", cfg.RateLimitDefault)),
+		handlers.CompressionMiddleware,
 		handlers.JSONMiddleware, // JSON middleware should not apply to static files
 	)
 
 	// Mount API routes with middleware
 	mainMux.Handle("/api/", apiHandler)
-	mainMux.Handle("/ws", apiHandler)
+	mainMux.Handle("GET /ws", apiHandler)
 
-	// Serve static files without JSON middleware (preserves proper MIME types)
-	fs := http.FileServer(http.Dir(cfg.UploadsPath))
-	staticHandler := http.StripPrefix("/uploads/", fs)
+	// Serve uploads without JSON middleware, with their own content type and sandbox policy
+	staticHandler := http.HandlerFunc(uploadHandler.ServeUpload)
 
 	// Apply only basic middleware to static files (no JSON middleware)
 	staticWithMiddleware := applyMiddleware(staticHandler,
 		handlers.PanicRecoveryMiddleware,
 		handlers.SecurityHeadersMiddleware,
-		corsMiddleware(cfg.AllowedOrigins),
+		corsMiddleware(cors),
 	)
 
-	mainMux.Handle("/uploads/", staticWithMiddleware)
-
-	return mainMux
+	mainMux.Handle("GET /uploads/", staticWithMiddleware)
+
+	// Operational endpoints outside /api get only the basic middleware
+	basicMiddleware := func(next http.HandlerFunc) http.Handler {
+		return applyMiddleware(next, handlers.PanicRecoveryMiddleware, handlers.SecurityHeadersMiddleware)
+	}
+
+	// Liveness and readiness probes for the orchestrator, without authentication
+	mainMux.Handle("GET /healthz", basicMiddleware(healthHandler.Healthz))
+	mainMux.Handle("GET /readyz", basicMiddleware(healthHandler.Readyz))
+
+	// Prometheus metrics, restricted to allowlisted scrapers by the handler itself
+	mainMux.Handle("GET /metrics", basicMiddleware(metricsHandler.ServeMetrics))
+
+	// Every request gets an ID, an access log record and request metrics named
+	// after the route that handled it, so paths with IDs in them group together.
+	// Labels leave out the method, which is recorded separately.
+	routeOf := func(r *http.Request) string {
+		pattern, _, _ := matchAPIRoute(apiMux, r)
+		if pattern == "" {
+			_, pattern = mainMux.Handler(r)
+			if pattern == "/api/" {
+				// Only the API mount point matched, not an actual route
+				return ""
+			}
+		}
+		if _, path, found := strings.Cut(pattern, " "); found {
+			return path
+		}
+		return pattern
+	}
+
+	return applyMiddleware(mainMux,
+		handlers.RequestIDMiddleware,
+		handlers.AccessLogMiddleware(routeOf),
+		handlers.MetricsMiddleware(routeOf),
+	)
 }
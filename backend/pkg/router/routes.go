@@ -2,113 +2,122 @@ package router
 
 import (
 	"net/http"
-	"strings"
 
 	"ripple/pkg/handlers"
 )
 
-func setupFollowRoutes(mux *http.ServeMux, h *handlers.FollowHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/follow", auth(http.HandlerFunc(h.FollowUser)))
-	mux.Handle("/api/unfollow", auth(http.HandlerFunc(h.UnfollowUser)))
-	mux.Handle("/api/follow/handle", auth(http.HandlerFunc(h.HandleFollowRequest)))
-	mux.Handle("/api/follow/requests", auth(http.HandlerFunc(h.GetFollowRequests)))
-	mux.Handle("/api/follow/followers/", auth(http.HandlerFunc(h.GetFollowers)))
-	mux.Handle("/api/follow/following/", auth(http.HandlerFunc(h.GetFollowing)))
-	mux.Handle("/api/follow/stats/", auth(http.HandlerFunc(h.GetFollowStats)))
-	mux.Handle("/api/follow/status/", auth(http.HandlerFunc(h.GetFollowStatus)))
+func setupFollowRoutes(mux *routeMux, h *handlers.FollowHandler, auth func(http.Handler) http.Handler) {
+	mux.Handle("POST /api/v1/follow", auth(http.HandlerFunc(h.FollowUser)))
+	mux.Handle("POST /api/v1/unfollow", auth(http.HandlerFunc(h.UnfollowUser)))
+	mux.Handle("POST /api/v1/follow/handle", auth(http.HandlerFunc(h.HandleFollowRequest)))
+	mux.Handle("GET /api/v1/follow/requests", auth(http.HandlerFunc(h.GetFollowRequests)))
+	mux.Handle("GET /api/v1/follow/followers/{id}", auth(http.HandlerFunc(h.GetFollowers)))
+	mux.Handle("GET /api/v1/follow/following/{id}", auth(http.HandlerFunc(h.GetFollowing)))
+	mux.Handle("GET /api/v1/follow/stats/{id}", auth(http.HandlerFunc(h.GetFollowStats)))
+	mux.Handle("GET /api/v1/follow/status/{id}", auth(http.HandlerFunc(h.GetFollowStatus)))
 }
 
-func setupPostRoutes(mux *http.ServeMux, h *handlers.PostHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/posts", auth(http.HandlerFunc(h.CreatePost)))
-	mux.Handle("/api/posts/", auth(http.HandlerFunc(h.GetPost)))
-	mux.Handle("/api/posts/feed", auth(http.HandlerFunc(h.GetFeed)))
-	mux.Handle("/api/posts/search", auth(http.HandlerFunc(h.SearchPosts)))
-	mux.Handle("/api/posts/user/", auth(http.HandlerFunc(h.GetUserPosts)))
-	mux.Handle("/api/posts/update", auth(http.HandlerFunc(h.UpdatePost)))
-	mux.Handle("/api/posts/delete/", auth(http.HandlerFunc(h.DeletePost)))
-	mux.Handle("/api/posts/comments/create", auth(http.HandlerFunc(h.CreateComment)))
-	mux.Handle("/api/posts/comments/", auth(http.HandlerFunc(h.GetComments)))
+func setupPostRoutes(mux *routeMux, h *handlers.PostHandler, auth, verified func(http.Handler) http.Handler) {
+	mux.Handle("POST /api/v1/posts", verified(http.HandlerFunc(h.CreatePost)))
+	mux.Handle("GET /api/v1/posts/{id}", auth(http.HandlerFunc(h.GetPost)))
+	mux.Handle("GET /api/v1/posts/feed", auth(http.HandlerFunc(h.GetFeed)))
+	mux.Handle("GET /api/v1/posts/search", auth(http.HandlerFunc(h.SearchPosts)))
+	mux.Handle("GET /api/v1/posts/user/{id}", auth(http.HandlerFunc(h.GetUserPosts)))
+	mux.Handle("PUT /api/v1/posts/update", auth(http.HandlerFunc(h.UpdatePost)))
+	mux.Handle("DELETE /api/v1/posts/delete/{id}", auth(http.HandlerFunc(h.DeletePost)))
+	mux.Handle("POST /api/v1/posts/comments/create", verified(http.HandlerFunc(h.CreateComment)))
+	mux.Handle("GET /api/v1/posts/comments/{id}", auth(http.HandlerFunc(h.GetComments)))
 }
 
-func setupLikeRoutes(mux *http.ServeMux, h *handlers.LikeHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/posts/like", auth(http.HandlerFunc(h.ToggleLike)))
+func setupLikeRoutes(mux *routeMux, h *handlers.LikeHandler, auth func(http.Handler) http.Handler) {
+	mux.Handle("POST /api/v1/posts/like", auth(http.HandlerFunc(h.ToggleLike)))
 	// mux.Handle("/api/posts/like/", auth(http.HandlerFunc(h.LikePost)))
 	// mux.Handle("/api/posts/unlike/", auth(http.HandlerFunc(h.UnlikePost)))
 	// mux.Handle("/api/posts/likes/", auth(http.HandlerFunc(h.GetPostLikes)))
 	// mux.Handle("/api/posts/like-status/", auth(http.HandlerFunc(h.CheckLikeStatus)))
 }
 
-func setupGroupRoutes(mux *http.ServeMux, h *handlers.GroupHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/groups", auth(http.HandlerFunc(h.CreateGroup)))
-	mux.Handle("/api/groups/all", auth(http.HandlerFunc(h.GetAllGroups)))
-	mux.Handle("/api/groups/user", auth(http.HandlerFunc(h.GetUserGroups)))
-	mux.Handle("/api/groups/recommendations", auth(http.HandlerFunc(h.GetRecommendedGroups)))
-	mux.Handle("/api/groups/search", auth(http.HandlerFunc(h.SearchGroups)))
-	mux.Handle("/api/groups/invite", auth(http.HandlerFunc(h.InviteToGroup)))
-	mux.Handle("/api/groups/join", auth(http.HandlerFunc(h.JoinGroup)))
-	mux.Handle("/api/groups/handle", auth(http.HandlerFunc(h.HandleMembershipRequest)))
-	mux.Handle("/api/groups/leave/", auth(http.HandlerFunc(h.LeaveGroup)))
-	mux.Handle("/api/groups/posts/update", auth(http.HandlerFunc(h.UpdateGroupPost)))
-	mux.Handle("/api/groups/posts/delete/", auth(http.HandlerFunc(h.DeleteGroupPost)))
+func setupGroupRoutes(mux *routeMux, h *handlers.GroupHandler, auth, verified func(http.Handler) http.Handler) {
+	mux.Handle("POST /api/v1/groups", auth(http.HandlerFunc(h.CreateGroup)))
+	mux.Handle("GET /api/v1/groups/all", auth(http.HandlerFunc(h.GetAllGroups)))
+	mux.Handle("GET /api/v1/groups/user", auth(http.HandlerFunc(h.GetUserGroups)))
+	mux.Handle("GET /api/v1/groups/recommendations", auth(http.HandlerFunc(h.GetRecommendedGroups)))
+	mux.Handle("GET /api/v1/groups/search", auth(http.HandlerFunc(h.SearchGroups)))
+	mux.Handle("GET /api/v1/groups/invitations", auth(http.HandlerFunc(h.GetPendingInvitations)))
+	mux.Handle("POST /api/v1/groups/invite", auth(http.HandlerFunc(h.InviteToGroup)))
+	mux.Handle("POST /api/v1/groups/join", auth(http.HandlerFunc(h.JoinGroup)))
+	mux.Handle("POST /api/v1/groups/handle", auth(http.HandlerFunc(h.HandleMembershipRequest)))
 
-	mux.Handle("/api/groups/invitations", auth(http.HandlerFunc(h.GetPendingInvitations)))
-	// Handle group requests endpoint with proper routing
-	mux.HandleFunc("/api/groups/", func(w http.ResponseWriter, r *http.Request) {
-		authHandler := auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if strings.HasSuffix(r.URL.Path, "/requests") && r.Method == "GET" {
-				h.GetPendingJoinRequests(w, r)
-			} else if strings.HasSuffix(r.URL.Path, "/invite") && r.Method == "POST" {
-				h.InviteUsers(w, r)
-			} else if strings.HasSuffix(r.URL.Path, "/members") && r.Method == "GET" {
-				h.GetGroupMembers(w, r)
-			} else if r.Method == "PUT" {
-				h.UpdateGroup(w, r)
-			} else {
-				h.GetGroup(w, r)
-			}
-		}))
-		authHandler.ServeHTTP(w, r)
-	})
-	mux.Handle("/api/groups/posts/", auth(http.HandlerFunc(h.CreateGroupPost)))
-	mux.Handle("/api/groups/posts/get/", auth(http.HandlerFunc(h.GetGroupPosts)))
-	mux.Handle("/api/groups/comments/", auth(http.HandlerFunc(h.CreateGroupComment)))
-	mux.Handle("/api/groups/comments/get/", auth(http.HandlerFunc(h.GetGroupComments)))
-	mux.Handle("/api/groups/posts/like", auth(http.HandlerFunc(h.ToggleGroupPostLike)))
+	// A single group and its members
+	mux.Handle("GET /api/v1/groups/{id}", auth(handlers.ConditionalGETMiddleware(http.HandlerFunc(h.GetGroup))))
+	mux.Handle("PUT /api/v1/groups/{id}", auth(http.HandlerFunc(h.UpdateGroup)))
+	mux.Handle("GET /api/v1/groups/{id}/members", auth(http.HandlerFunc(h.GetGroupMembers)))
+	mux.Handle("GET /api/v1/groups/{id}/requests", auth(http.HandlerFunc(h.GetPendingJoinRequests)))
+	mux.Handle("POST /api/v1/groups/{id}/invite", auth(http.HandlerFunc(h.InviteUsers)))
+	mux.Handle("POST /api/v1/groups/{id}/leave", auth(http.HandlerFunc(h.LeaveGroup)))
+
+	// Group posts and their comments
+	mux.Handle("GET /api/v1/groups/{id}/posts", auth(http.HandlerFunc(h.GetGroupPosts)))
+	mux.Handle("POST /api/v1/groups/{id}/posts", verified(http.HandlerFunc(h.CreateGroupPost)))
+	mux.Handle("PUT /api/v1/groups/posts/update", auth(http.HandlerFunc(h.UpdateGroupPost)))
+	mux.Handle("DELETE /api/v1/groups/posts/delete/{id}", auth(http.HandlerFunc(h.DeleteGroupPost)))
+	mux.Handle("POST /api/v1/groups/posts/like", auth(http.HandlerFunc(h.ToggleGroupPostLike)))
+	mux.Handle("GET /api/v1/groups/posts/{id}/comments", auth(http.HandlerFunc(h.GetGroupComments)))
+	mux.Handle("POST /api/v1/groups/posts/{id}/comments", verified(http.HandlerFunc(h.CreateGroupComment)))
+
+	// The earlier shapes of the routes above, which clash with them under
+	// /api/v1, are kept as deprecated legacy routes; see deprecatedRoutes
+	mux.Handle("POST /api/groups/leave/{id}", auth(http.HandlerFunc(h.LeaveGroup)))
+	mux.Handle("POST /api/groups/posts/{id}", verified(http.HandlerFunc(h.CreateGroupPost)))
+	mux.Handle("GET /api/groups/posts/get/{id}", auth(http.HandlerFunc(h.GetGroupPosts)))
+	mux.Handle("POST /api/groups/comments/{id}", verified(http.HandlerFunc(h.CreateGroupComment)))
+	mux.Handle("GET /api/groups/comments/get/{id}", auth(http.HandlerFunc(h.GetGroupComments)))
+}
+
+func setupEventRoutes(mux *routeMux, h *handlers.EventHandler, auth func(http.Handler) http.Handler) {
+	mux.Handle("GET /api/v1/events", auth(http.HandlerFunc(h.GetUserEvents)))
+	mux.Handle("POST /api/v1/events/{id}", auth(http.HandlerFunc(h.CreateEvent)))
+	mux.Handle("GET /api/v1/events/get/{id}", auth(handlers.ConditionalGETMiddleware(http.HandlerFunc(h.GetEvent))))
+	mux.Handle("GET /api/v1/events/group/{id}", auth(http.HandlerFunc(h.GetGroupEvents)))
+	mux.Handle("POST /api/v1/events/respond/{id}", auth(http.HandlerFunc(h.RespondToEvent)))
+	mux.Handle("GET /api/v1/events/responses/{id}", auth(http.HandlerFunc(h.GetEventResponses)))
 }
 
-func setupEventRoutes(mux *http.ServeMux, h *handlers.EventHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/events", auth(http.HandlerFunc(h.GetUserEvents)))
-	mux.Handle("/api/events/", auth(http.HandlerFunc(h.CreateEvent)))
-	mux.Handle("/api/events/get/", auth(http.HandlerFunc(h.GetEvent)))
-	mux.Handle("/api/events/group/", auth(http.HandlerFunc(h.GetGroupEvents)))
-	mux.Handle("/api/events/respond/", auth(http.HandlerFunc(h.RespondToEvent)))
-	mux.Handle("/api/events/responses/", auth(http.HandlerFunc(h.GetEventResponses)))
+func setupUploadRoutes(mux *routeMux, h *handlers.UploadHandler, auth func(http.Handler) http.Handler) {
+	mux.Handle("POST /api/v1/upload/avatar", auth(http.HandlerFunc(h.UploadAvatar)))
+	mux.Handle("POST /api/v1/upload/cover", auth(http.HandlerFunc(h.UploadCover)))
+	mux.Handle("POST /api/v1/upload/post", auth(http.HandlerFunc(h.UploadPostImage)))
+	mux.Handle("POST /api/v1/upload/comment", auth(http.HandlerFunc(h.UploadCommentImage)))
+	mux.Handle("POST /api/v1/upload/group-avatar", auth(http.HandlerFunc(h.UploadGroupAvatar)))
+	mux.Handle("POST /api/v1/upload/group-cover", auth(http.HandlerFunc(h.UploadGroupCover)))
 }
 
-func setupUploadRoutes(mux *http.ServeMux, h *handlers.UploadHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/upload/avatar", auth(http.HandlerFunc(h.UploadAvatar)))
-	mux.Handle("/api/upload/cover", auth(http.HandlerFunc(h.UploadCover)))
-	mux.Handle("/api/upload/post", auth(http.HandlerFunc(h.UploadPostImage)))
-	mux.Handle("/api/upload/comment", auth(http.HandlerFunc(h.UploadCommentImage)))
-	mux.Handle("/api/upload/group-avatar", auth(http.HandlerFunc(h.UploadGroupAvatar)))
-	mux.Handle("/api/upload/group-cover", auth(http.HandlerFunc(h.UploadGroupCover)))
+func setupNotificationRoutes(mux *routeMux, h *handlers.NotificationHandler, auth func(http.Handler) http.Handler) {
+	mux.Handle("GET /api/v1/notifications", auth(http.HandlerFunc(h.GetNotifications)))
+	mux.Handle("PUT /api/v1/notifications/read/{id}", auth(http.HandlerFunc(h.MarkAsRead)))
+	mux.Handle("PUT /api/v1/notifications/read-all", auth(http.HandlerFunc(h.MarkAllAsRead)))
+	mux.Handle("DELETE /api/v1/notifications/delete/{id}", auth(http.HandlerFunc(h.DeleteNotification)))
 }
 
-func setupNotificationRoutes(mux *http.ServeMux, h *handlers.NotificationHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/notifications", auth(http.HandlerFunc(h.GetNotifications)))
-	mux.Handle("/api/notifications/read/", auth(http.HandlerFunc(h.MarkAsRead)))
-	mux.Handle("/api/notifications/read-all", auth(http.HandlerFunc(h.MarkAllAsRead)))
-	mux.Handle("/api/notifications/delete/", auth(http.HandlerFunc(h.DeleteNotification)))
+func setupChatRoutes(mux *routeMux, h *handlers.ChatHandler, auth, verified func(http.Handler) http.Handler) {
+	mux.Handle("GET /api/v1/chat/conversations", auth(http.HandlerFunc(h.GetConversations)))
+	mux.Handle("GET /api/v1/chat/messages/private/{id}", auth(http.HandlerFunc(h.GetPrivateMessages)))
+	mux.Handle("GET /api/v1/chat/messages/group/{id}", auth(http.HandlerFunc(h.GetGroupMessages)))
+	mux.Handle("POST /api/v1/chat/messages/private", verified(http.HandlerFunc(h.CreatePrivateMessage)))
+	mux.Handle("POST /api/v1/chat/messages/group", verified(http.HandlerFunc(h.CreateGroupMessage)))
+	mux.Handle("GET /api/v1/chat/online", auth(http.HandlerFunc(h.GetOnlineUsers)))
+	mux.Handle("POST /api/v1/chat/typing", auth(http.HandlerFunc(h.TypingIndicator)))
+	mux.Handle("GET /api/v1/chat/unread", auth(http.HandlerFunc(h.GetUnreadCounts)))
+	mux.Handle("GET /api/v1/chat/followed-users", auth(http.HandlerFunc(h.GetFollowedUsers)))
 }
 
-func setupChatRoutes(mux *http.ServeMux, h *handlers.ChatHandler, auth func(http.Handler) http.Handler) {
-	mux.Handle("/api/chat/conversations", auth(http.HandlerFunc(h.GetConversations)))
-	mux.Handle("/api/chat/messages/private/", auth(http.HandlerFunc(h.GetPrivateMessages)))
-	mux.Handle("/api/chat/messages/group/", auth(http.HandlerFunc(h.GetGroupMessages)))
-	mux.Handle("/api/chat/messages/private", auth(http.HandlerFunc(h.CreatePrivateMessage)))
-	mux.Handle("/api/chat/messages/group", auth(http.HandlerFunc(h.CreateGroupMessage)))
-	mux.Handle("/api/chat/online", auth(http.HandlerFunc(h.GetOnlineUsers)))
-	mux.Handle("/api/chat/typing", auth(http.HandlerFunc(h.TypingIndicator)))
-	mux.Handle("/api/chat/unread", auth(http.HandlerFunc(h.GetUnreadCounts)))
-	mux.Handle("/api/chat/followed-users", auth(http.HandlerFunc(h.GetFollowedUsers)))
+func setupAdminRoutes(mux *routeMux, h *handlers.AdminHandler, moderator, admin func(http.Handler) http.Handler) {
+	mux.Handle("GET /api/v1/admin/users", moderator(http.HandlerFunc(h.ListUsers)))
+	mux.Handle("POST /api/v1/admin/users/suspend/{id}", moderator(http.HandlerFunc(h.SuspendUser)))
+	mux.Handle("POST /api/v1/admin/users/unsuspend/{id}", moderator(http.HandlerFunc(h.UnsuspendUser)))
+	mux.Handle("POST /api/v1/admin/users/logout/{id}", admin(http.HandlerFunc(h.ForceLogout)))
+	mux.Handle("PUT /api/v1/admin/users/role/{id}", admin(http.HandlerFunc(h.SetRole)))
+	mux.Handle("DELETE /api/v1/admin/content/{type}/{id}", moderator(http.HandlerFunc(h.DeleteContent)))
+	mux.Handle("GET /api/v1/admin/stats", admin(http.HandlerFunc(h.GetStats)))
+	mux.Handle("GET /api/v1/admin/jobs", admin(http.HandlerFunc(h.GetJobs)))
 }
@@ -0,0 +1,159 @@
+package router
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ripple/pkg/metrics"
+)
+
+// apiPrefix is the canonical prefix of every API route. Paths directly under
+// /api/ are served as deprecated aliases of the same route under this prefix.
+const apiPrefix = "/api/v1"
+
+// legacyAPIDeprecated is when the unversioned /api/ paths were deprecated
+var legacyAPIDeprecated = time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+
+// deprecation announces that a route will be removed
+type deprecation struct {
+	since  time.Time // sent as the Deprecation header
+	sunset time.Time // sent as the Sunset header; zero until a removal date is set
+
+	// successor is the path of the replacement, sent as a Link header
+	successor string
+}
+
+// deprecatedRoutes marks routes for removal, keyed by their pattern. They stay
+// documented, flagged as deprecated, until they are removed, e.g.
+//
+//	"POST /api/v1/posts/comments/create": {since: ..., sunset: ..., successor: "/api/v2/posts/{id}/comments"},
+//
+// Wildcards in the successor are filled in from the request. Legacy routes,
+// registered under /api/ in their old shape, get the legacy API sunset unless
+// they have their own.
+var deprecatedRoutes = map[string]deprecation{
+	// Group routes from before they were nested under the group or post
+	"POST /api/groups/leave/{id}":       {since: legacyAPIDeprecated, successor: apiPrefix + "/groups/{id}/leave"},
+	"POST /api/groups/posts/{id}":       {since: legacyAPIDeprecated, successor: apiPrefix + "/groups/{id}/posts"},
+	"GET /api/groups/posts/get/{id}":    {since: legacyAPIDeprecated, successor: apiPrefix + "/groups/{id}/posts"},
+	"POST /api/groups/comments/{id}":    {since: legacyAPIDeprecated, successor: apiPrefix + "/groups/posts/{id}/comments"},
+	"GET /api/groups/comments/get/{id}": {since: legacyAPIDeprecated, successor: apiPrefix + "/groups/posts/{id}/comments"},
+}
+
+// deprecatedUsageLogInterval is how often the usage count of a deprecated
+// route is logged, besides its first use
+const deprecatedUsageLogInterval = 100
+
+// canonicalAPIRequest maps a request for an unversioned /api/ path to the
+// canonical path under apiPrefix and reports whether it did
+func canonicalAPIRequest(r *http.Request) (*http.Request, bool) {
+	rest, ok := strings.CutPrefix(r.URL.Path, "/api/")
+	if !ok || rest == "v1" || strings.HasPrefix(rest, "v1/") {
+		return r, false
+	}
+
+	canonical := new(http.Request)
+	*canonical = *r
+	canonical.URL = new(url.URL)
+	*canonical.URL = *r.URL
+	canonical.URL.Path = apiPrefix + "/" + rest
+	if r.URL.RawPath != "" {
+		canonical.URL.RawPath = apiPrefix + strings.TrimPrefix(r.URL.RawPath, "/api")
+	}
+	return canonical, true
+}
+
+// matchAPIRoute returns the pattern of the route of mux that serves r and the
+// request it is served with. Unversioned paths are served by the route with
+// the same path under apiPrefix. Legacy routes registered under /api/ only
+// serve the paths no such route matches, so their wildcards cannot shadow a
+// literal segment of a current route, e.g. POST /api/groups/posts/like.
+func matchAPIRoute(mux *routeMux, r *http.Request) (pattern string, canonical *http.Request, legacy bool) {
+	canonical, legacy = canonicalAPIRequest(r)
+	if _, pattern = mux.Handler(canonical); pattern != "" || !legacy {
+		return pattern, canonical, legacy
+	}
+	if _, pattern := mux.Handler(r); pattern != "" {
+		return pattern, r, true
+	}
+	return "", canonical, true
+}
+
+// fillWildcards replaces the {name} wildcards of target with the segments of
+// path that match the same wildcards of patternPath
+func fillWildcards(target, patternPath, path string) string {
+	if !strings.Contains(target, "{") {
+		return target
+	}
+	values := strings.Split(path, "/")
+	for i, segment := range strings.Split(patternPath, "/") {
+		if strings.HasPrefix(segment, "{") && i < len(values) {
+			target = strings.ReplaceAll(target, segment, values[i])
+		}
+	}
+	return target
+}
+
+// deprecationMiddleware serves the unversioned aliases of the API routes and
+// adds Deprecation, Sunset and Link headers to them and to deprecated routes.
+// Usage is counted in the ripple_deprecated_requests_total metric and logged.
+func deprecationMiddleware(mux *routeMux, routes map[string]deprecation, legacySunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pattern, canonical, legacy := matchAPIRoute(mux, r)
+			if pattern == "" {
+				// Unknown route; the mux answers 404 or 405
+				next.ServeHTTP(w, canonical)
+				return
+			}
+
+			dep, deprecated := routes[pattern]
+			_, patternPath, _ := strings.Cut(pattern, " ")
+			route := patternPath
+			if legacy {
+				route = strings.Replace(route, apiPrefix+"/", "/api/", 1)
+				if !deprecated {
+					dep = deprecation{since: legacyAPIDeprecated}
+				}
+				if dep.sunset.IsZero() {
+					dep.sunset = legacySunset
+				}
+				if dep.successor == "" {
+					dep.successor = canonical.URL.Path
+				}
+			} else if !deprecated {
+				next.ServeHTTP(w, canonical)
+				return
+			}
+
+			dep.successor = fillWildcards(dep.successor, patternPath, canonical.URL.Path)
+			setDeprecationHeaders(w.Header(), dep)
+			countDeprecatedUse(route, r.Method)
+			next.ServeHTTP(w, canonical)
+		})
+	}
+}
+
+func setDeprecationHeaders(header http.Header, dep deprecation) {
+	// RFC 9745 structured date and RFC 8594 HTTP date
+	header.Set("Deprecation", fmt.Sprintf("@%d", dep.since.Unix()))
+	if !dep.sunset.IsZero() {
+		header.Set("Sunset", dep.sunset.UTC().Format(http.TimeFormat))
+	}
+	if dep.successor != "" {
+		header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", dep.successor))
+	}
+}
+
+// countDeprecatedUse counts a request to a deprecated route, keyed by the
+// route as requested, and logs the first use and every hundredth after it
+func countDeprecatedUse(route, method string) {
+	metrics.DeprecatedRequests.Inc(route, method)
+	if count := int64(metrics.DeprecatedRequests.Value(route, method)); count == 1 || count%deprecatedUsageLogInterval == 0 {
+		log.Printf("Deprecated route %s %s used %d times", method, route, count)
+	}
+}
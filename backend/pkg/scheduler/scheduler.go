@@ -0,0 +1,269 @@
+// backend/pkg/scheduler/scheduler.go
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultJitter spreads job runs by up to 10% of their interval so jobs with the
+// same interval do not all hit the database at the same moment
+const DefaultJitter = 0.1
+
+// JobFunc is the work done by a periodic job
+type JobFunc func() error
+
+// JobStatus describes the last run of a job
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"`
+	Running        bool       `json:"running"`
+	Runs           int        `json:"runs"`
+	Failures       int        `json:"failures"`
+	Skipped        int        `json:"skipped"`
+	LastStartedAt  *time.Time `json:"last_started_at"`
+	LastFinishedAt *time.Time `json:"last_finished_at"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at"`
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+
+	// Guarded by Scheduler.mu
+	status JobStatus
+}
+
+// Scheduler runs registered jobs periodically. Each job runs right after Start
+// (plus jitter) and then once per interval. A run is skipped while the previous
+// run of the same job is still going.
+type Scheduler struct {
+	jitter float64
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{
+		jitter: DefaultJitter,
+		jobs:   make(map[string]*job),
+		stop:   make(chan struct{}),
+	}
+}
+
+// SetJitter changes the fraction of the interval used to spread runs, e.g. 0.1
+// for up to 10%. Zero disables jitter.
+func (s *Scheduler) SetJitter(jitter float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jitter = jitter
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run JobFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("job %s: interval must be positive", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("job %s: scheduler already started", name)
+	}
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s: already registered", name)
+	}
+
+	s.jobs[name] = &job{
+		name:     name,
+		interval: interval,
+		run:      run,
+		status:   JobStatus{Name: name, Interval: interval.String()},
+	}
+	return nil
+}
+
+// Start launches every registered job
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+	log.Printf("Scheduler: started %d jobs", len(s.jobs))
+}
+
+// Stop stops scheduling new runs and waits for running jobs to finish or for
+// ctx to expire
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("Scheduler: stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler: jobs still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// Status returns the status of every job, sorted by name
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status)
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// RunNow starts a run of the named job outside its schedule. It returns false
+// if the job is unknown or already running.
+func (s *Scheduler) RunNow(name string) bool {
+	s.mu.Lock()
+	j, exists := s.jobs[name]
+	s.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+	return s.trigger(j)
+}
+
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+
+	// The first run only waits for the jitter, so a restart catches up right away
+	delay := s.jitterFor(j.interval)
+	for {
+		next := time.Now().Add(delay)
+		s.mu.Lock()
+		j.status.NextRunAt = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+
+		s.trigger(j)
+		delay = s.nextDelay(j.interval)
+	}
+}
+
+// trigger runs the job in its own goroutine unless it is already running
+func (s *Scheduler) trigger(j *job) bool {
+	s.mu.Lock()
+	if j.status.Running {
+		j.status.Skipped++
+		s.mu.Unlock()
+		log.Printf("Scheduler: job %s is still running, skipping this run", j.name)
+		return false
+	}
+	select {
+	case <-s.stop:
+		s.mu.Unlock()
+		return false
+	default:
+	}
+	startedAt := time.Now()
+	j.status.Running = true
+	j.status.LastStartedAt = &startedAt
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		err := s.execute(j)
+		finishedAt := time.Now()
+
+		s.mu.Lock()
+		j.status.Running = false
+		j.status.Runs++
+		j.status.LastFinishedAt = &finishedAt
+		j.status.LastDurationMs = finishedAt.Sub(startedAt).Milliseconds()
+		j.status.LastError = ""
+		if err != nil {
+			j.status.Failures++
+			j.status.LastError = err.Error()
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			log.Printf("Scheduler: job %s failed after %s: %v", j.name, finishedAt.Sub(startedAt), err)
+		} else {
+			log.Printf("Scheduler: job %s finished in %s", j.name, finishedAt.Sub(startedAt))
+		}
+	}()
+	return true
+}
+
+// execute runs the job and turns a panic into an error so one broken job
+// cannot take down the server
+func (s *Scheduler) execute(j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run()
+}
+
+// jitterFor returns a random delay between zero and the jitter fraction of interval
+func (s *Scheduler) jitterFor(interval time.Duration) time.Duration {
+	maxJitter := s.maxJitter(interval)
+	if maxJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(maxJitter)))
+}
+
+// nextDelay returns interval shifted randomly by up to half the jitter either way
+func (s *Scheduler) nextDelay(interval time.Duration) time.Duration {
+	return interval - s.maxJitter(interval)/2 + s.jitterFor(interval)
+}
+
+func (s *Scheduler) maxJitter(interval time.Duration) time.Duration {
+	s.mu.Lock()
+	jitter := s.jitter
+	s.mu.Unlock()
+	return time.Duration(float64(interval) * jitter)
+}
@@ -3,6 +3,10 @@ package utils
 
 import (
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"mime/multipart"
 	"os"
@@ -12,16 +16,10 @@ import (
 	"time"
 )
 
-var allowedMediaTypes = map[string]bool{
-	"image/jpeg":      true,
-	"image/jpg":       true,
-	"image/png":       true,
-	"image/gif":       true,
-	"video/mp4":       true,
-	"video/webm":      true,
-	"video/quicktime": true,
-}
-
+// SaveUploadedFile stores an uploaded image or video under a new name and
+// returns that name. The type is taken from the file's content, which must
+// match the type the client declared; files that also carry markup or an
+// appended archive are refused, so an upload can never be served as a page.
 func SaveUploadedFile(file multipart.File, header *multipart.FileHeader, uploadDir string, maxSize int64) (string, error) {
 	// Validate file size
 	if header.Size > maxSize {
@@ -29,14 +27,20 @@ func SaveUploadedFile(file multipart.File, header *multipart.FileHeader, uploadD
 	}
 
 	// Validate file type
-	contentType := header.Header.Get("Content-Type")
-	if !allowedMediaTypes[contentType] {
+	declaredType := normalizeMediaType(header.Header.Get("Content-Type"))
+	if _, ok := mediaExtensions[declaredType]; !ok {
 		return "", fmt.Errorf(constants.ErrInvalidFileType)
 	}
+	contentType, err := checkFileContent(file, header.Size)
+	if err != nil {
+		return "", err
+	}
+	if contentType != declaredType {
+		return "", fmt.Errorf(constants.ErrFileTypeMismatch)
+	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("%d_%s%s", time.Now().Unix(), generateRandomString(8), ext)
+	// Generate unique filename, with the extension of the detected type
+	filename := fmt.Sprintf("%d_%s%s", time.Now().Unix(), generateRandomString(8), mediaExtensions[contentType])
 
 	// Create full path
 	fullPath := filepath.Join(uploadDir, filename)
@@ -54,13 +58,69 @@ func SaveUploadedFile(file multipart.File, header *multipart.FileHeader, uploadD
 	defer dst.Close()
 
 	// Copy file content
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		os.Remove(fullPath)
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
 	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(fullPath)
 		return "", fmt.Errorf("failed to save file: %w", err)
 	}
 
 	return filename, nil
 }
 
+// checkFileContent detects the media type of an uploaded file from its magic
+// bytes and checks that the whole file is what that type says it is
+func checkFileContent(file multipart.File, size int64) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf(constants.ErrInvalidFileType)
+	}
+	contentType := DetectMediaType(head[:n])
+	if contentType == "" {
+		return "", fmt.Errorf(constants.ErrInvalidFileType)
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		// The header of an image must also parse, not just its first bytes
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+		if _, _, err := image.DecodeConfig(file); err != nil {
+			return "", fmt.Errorf(constants.ErrFileTypeMismatch)
+		}
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	markup, err := ContainsMarkup(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	archive, err := HasArchiveTrailer(file, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if markup || archive {
+		return "", fmt.Errorf(constants.ErrUnsafeFileContent)
+	}
+	return contentType, nil
+}
+
+// normalizeMediaType drops parameters from a Content-Type and maps the
+// nonstandard image/jpg to image/jpeg
+func normalizeMediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "image/jpg" {
+		return "image/jpeg"
+	}
+	return mediaType
+}
+
 func generateRandomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	result := make([]byte, length)
@@ -75,5 +135,39 @@ func GetFileExtension(filename string) string {
 }
 
 func IsValidMediaType(contentType string) bool {
-	return allowedMediaTypes[contentType]
+	_, ok := mediaExtensions[normalizeMediaType(contentType)]
+	return ok
+}
+
+// ResolveUploadPath maps a public upload path such as "/uploads/avatars/x.jpg"
+// to the file inside uploadsPath. Paths that would escape uploadsPath are refused.
+func ResolveUploadPath(uploadsPath, publicPath string) (string, error) {
+	relative, ok := strings.CutPrefix(publicPath, "/uploads/")
+	if !ok {
+		return "", fmt.Errorf("not an upload path: %s", publicPath)
+	}
+
+	root, err := filepath.Abs(uploadsPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve uploads directory: %w", err)
+	}
+	fullPath := filepath.Join(root, filepath.FromSlash(relative))
+	if !strings.HasPrefix(fullPath, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("upload path escapes uploads directory: %s", publicPath)
+	}
+	return fullPath, nil
+}
+
+// RemoveUploadedFile deletes the file behind a public upload path. A file that
+// is already gone is not an error.
+func RemoveUploadedFile(uploadsPath, publicPath string) error {
+	fullPath, err := ResolveUploadPath(uploadsPath, publicPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	return nil
 }
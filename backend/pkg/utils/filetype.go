@@ -0,0 +1,151 @@
+// backend/pkg/utils/filetype.go
+package utils
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// mediaExtensions maps the accepted upload types to the extension files are
+// stored with, so a stored file's extension always matches its content
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"video/quicktime": ".mov",
+}
+
+// servedMediaTypes maps the extensions of stored uploads to the type they are
+// served with, including those written before extensions were normalized
+var servedMediaTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+}
+
+// mp4Brands are ISO base media file brands of MP4 video; "qt  " is QuickTime
+var mp4Brands = []string{"isom", "iso2", "iso4", "iso5", "iso6", "mp41", "mp42", "mp71", "avc1", "M4V ", "dash"}
+
+// DetectMediaType identifies an accepted media type from the magic bytes at
+// the start of a file. It returns "" for anything else, including SVG, HTML
+// and other text formats, which have no magic bytes.
+func DetectMediaType(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xFF\xD8\xFF")):
+		return "image/jpeg"
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1A\n")):
+		return "image/png"
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return "image/gif"
+	case bytes.HasPrefix(head, []byte("\x1A\x45\xDF\xA3")):
+		// EBML container; only the WebM document type, not other Matroska files
+		if bytes.Contains(head[:min(len(head), 64)], []byte("webm")) {
+			return "video/webm"
+		}
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		brand := string(head[8:12])
+		if brand == "qt  " {
+			return "video/quicktime"
+		}
+		for _, mp4Brand := range mp4Brands {
+			if brand == mp4Brand {
+				return "video/mp4"
+			}
+		}
+	}
+	return ""
+}
+
+// MediaTypeByExtension returns the type an upload is served with, judging by
+// its stored name, and false for files that should only be downloaded
+func MediaTypeByExtension(name string) (string, bool) {
+	mediaType, ok := servedMediaTypes[strings.ToLower(filepath.Ext(name))]
+	return mediaType, ok
+}
+
+// markupMarkers betray HTML, SVG, XML or script content. Browsers sniff the
+// start of a file, so there every marker counts; in the rest of the file only
+// the longer ones are looked for, which binary media practically never contain
+// by chance.
+var (
+	headMarkupMarkers = [][]byte{
+		[]byte("<html"), []byte("<body"), []byte("<head"), []byte("<svg"), []byte("<?xml"), []byte("<?php"),
+	}
+	markupMarkers = [][]byte{
+		[]byte("<script"), []byte("<iframe"), []byte("<object"), []byte("<!doctype"), []byte("<embed "),
+		[]byte("javascript:"),
+	}
+)
+
+// markupScanHeadSize is how much of the start of a file is checked for every
+// marker, a little more than browsers sniff
+const markupScanHeadSize = 1024
+
+// ContainsMarkup reports whether r holds markup that a browser could render
+// as a document, which is how polyglot files smuggle scripts inside media
+func ContainsMarkup(r io.Reader) (bool, error) {
+	longest := 0
+	for _, marker := range markupMarkers {
+		longest = max(longest, len(marker))
+	}
+
+	buffer := make([]byte, 32*1024)
+	carry := 0
+	offset := 0
+	for {
+		n, err := io.ReadFull(r, buffer[carry:])
+		window := bytes.ToLower(buffer[:carry+n])
+
+		if offset < markupScanHeadSize {
+			head := window[:min(len(window), markupScanHeadSize-offset+carry)]
+			for _, marker := range headMarkupMarkers {
+				if bytes.Contains(head, marker) {
+					return true, nil
+				}
+			}
+		}
+		for _, marker := range markupMarkers {
+			if bytes.Contains(window, marker) {
+				return true, nil
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		// Keep the tail so markers spanning two reads are found
+		offset += n
+		carry = min(longest-1, len(window))
+		copy(buffer, buffer[len(window)-carry:len(window)])
+	}
+}
+
+// zipEndSignature starts the end record of a ZIP archive, found at the end of
+// the file. Appending an archive to an image makes a valid file of both kinds.
+var zipEndSignature = []byte("PK\x05\x06")
+
+// maxZipEndRecord is the largest size of the end record, including its comment
+const maxZipEndRecord = 22 + 65535
+
+// HasArchiveTrailer reports whether the file of the given size ends with a
+// ZIP archive
+func HasArchiveTrailer(r io.ReaderAt, size int64) (bool, error) {
+	length := min(size, maxZipEndRecord)
+	tail := make([]byte, length)
+	if _, err := r.ReadAt(tail, size-length); err != nil && err != io.EOF {
+		return false, err
+	}
+	return bytes.Contains(tail, zipEndSignature), nil
+}
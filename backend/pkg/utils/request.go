@@ -0,0 +1,17 @@
+// backend/pkg/utils/request.go
+package utils
+
+import (
+	"net"
+	"net/http"
+)
+
+// GetClientIP returns the IP address of the peer that sent the request.
+// Forwarding headers are ignored because they can be set by any client.
+func GetClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -58,6 +58,29 @@ func WriteErrorResponse(w http.ResponseWriter, statusCode int, message string) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// WriteErrorResponseWithCode writes an error JSON response like WriteErrorResponse,
+// adding a machine-readable error code so clients can react to specific failures.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the response to
+//   - statusCode: HTTP status code for the error response
+//   - message: error message to be included in the response
+//   - code: machine-readable error code, e.g. "EMAIL_NOT_VERIFIED"
+func WriteErrorResponseWithCode(w http.ResponseWriter, statusCode int, message, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	
+	response := APIResponse{
+		Success: false,
+		Error: &APIError{
+			Message: message,
+			Code:    code,
+		},
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
 // WriteValidationErrorResponse writes a validation error JSON response to the http.ResponseWriter
 // with a 400 Bad Request status code and details about the validation errors.
 //
@@ -9,30 +9,22 @@ import (
 	"ripple/pkg/auth"
 )
 
-// AuthenticateWebSocket validates the WebSocket connection and returns user ID
+// AuthenticateWebSocket validates the WebSocket connection and returns user ID.
+// Clients authenticate with a one-time ticket from /api/v1/auth/ws-ticket in the
+// ticket query parameter or with the session cookie. Session IDs are never
+// accepted in the URL, where they would end up in proxy logs.
 func AuthenticateWebSocket(r *http.Request, sessionManager *auth.SessionManager) (int, error) {
-	// Try to get session from cookie first
-	cookie, err := r.Cookie("session_id")
-	var sessionID string
-	
+	if ticket := r.URL.Query().Get("ticket"); ticket != "" {
+		return sessionManager.ConsumeWebSocketTicket(ticket)
+	}
+
+	cookie, err := r.Cookie(auth.SessionCookieName)
 	if err != nil {
-		// If no cookie, try to get session_id from query parameters
-		queryParams, parseErr := url.ParseQuery(r.URL.RawQuery)
-		if parseErr != nil {
-			return 0, parseErr
-		}
-		
-		sessionIDs, exists := queryParams["session_id"]
-		if !exists || len(sessionIDs) == 0 {
-			return 0, err
-		}
-		sessionID = sessionIDs[0]
-	} else {
-		sessionID = cookie.Value
+		return 0, err
 	}
 
 	// Validate session
-	session, err := sessionManager.GetSession(sessionID)
+	session, err := sessionManager.GetSession(cookie.Value)
 	if err != nil {
 		return 0, err
 	}
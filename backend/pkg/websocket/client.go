@@ -159,6 +159,11 @@ func (c *Client) handleIncomingMessage(msg *WSMessage) {
 
 // handlePrivateMessage processes private messages with privacy checks
 func (c *Client) handlePrivateMessage(msg *WSMessage) {
+	if !c.hasVerifiedEmail() {
+		c.sendError("Please verify your email address before sending messages")
+		return
+	}
+
 	if msg.To <= 0 {
 		c.sendError("Invalid recipient")
 		return
@@ -210,6 +215,11 @@ func (c *Client) handlePrivateMessage(msg *WSMessage) {
 
 // handleGroupMessage processes group messages with membership checks
 func (c *Client) handleGroupMessage(msg *WSMessage) {
+	if !c.hasVerifiedEmail() {
+		c.sendError("Please verify your email address before sending messages")
+		return
+	}
+
 	if msg.GroupID <= 0 {
 		c.sendError("Invalid group ID")
 		return
@@ -465,6 +475,21 @@ func (c *Client) markPrivateMessagesAsRead(senderID int) error {
 	return err
 }
 
+// hasVerifiedEmail checks whether the user may send messages. A positive result is
+// cached so verified users do not hit the database on every message.
+func (c *Client) hasVerifiedEmail() bool {
+	if c.emailVerified {
+		return true
+	}
+
+	err := c.hub.db.QueryRow("SELECT email_verified_at IS NOT NULL FROM users WHERE id = ?", c.userID).Scan(&c.emailVerified)
+	if err != nil {
+		log.Printf("Error checking email verification for user %d: %v", c.userID, err)
+		return false
+	}
+	return c.emailVerified
+}
+
 // sendError sends an error message to the client
 func (c *Client) sendError(errorMsg string) {
 	errorMessage := WSMessage{
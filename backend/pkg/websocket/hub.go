@@ -2,11 +2,14 @@
 package websocket
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"ripple/pkg/auth"
+	"ripple/pkg/metrics"
 	"sync"
 	"time"
 
@@ -33,6 +36,9 @@ type Hub struct {
 	// Unregister requests from clients
 	unregister chan *Client
 
+	// Liveness probes of the Run loop
+	ping chan chan struct{}
+
 	// Stop signal
 	stop chan struct{}
 
@@ -62,6 +68,9 @@ type Client struct {
 
 	// User groups (cached for quick access)
 	userGroups map[int]bool
+
+	// Whether the user has verified their email (cached once true)
+	emailVerified bool
 }
 
 // Message types for WebSocket communication
@@ -107,6 +116,7 @@ func NewHub(db *sql.DB) *Hub {
 		broadcast:    make(chan []byte),
 		register:     make(chan *Client),
 		unregister:   make(chan *Client),
+		ping:         make(chan chan struct{}),
 		stop:         make(chan struct{}),
 		db:           db,
 	}
@@ -114,18 +124,9 @@ func NewHub(db *sql.DB) *Hub {
 
 // HandleWebSocket upgrades HTTP connection to WebSocket and handles the connection
 func HandleWebSocket(hub *Hub, sm *auth.SessionManager, w http.ResponseWriter, r *http.Request) {
-	// Get session cookie
-	cookie, err := r.Cookie("session_id")
-	if err != nil {
-		log.Printf("WebSocket: No session cookie found: %v", err)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	// Get session and validate
-	session, err := sm.GetSession(cookie.Value)
+	userID, err := AuthenticateWebSocket(r, sm)
 	if err != nil {
-		log.Printf("WebSocket: Invalid session: %v", err)
+		log.Printf("WebSocket: Authentication failed: %v", err)
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
@@ -149,7 +150,7 @@ func HandleWebSocket(hub *Hub, sm *auth.SessionManager, w http.ResponseWriter, r
 		hub:        hub,
 		conn:       conn,
 		send:       make(chan []byte, 256),
-		userID:     session.UserID,
+		userID:     userID,
 		lastSeen:   time.Now(),
 		userGroups: make(map[int]bool),
 	}
@@ -165,7 +166,7 @@ func HandleWebSocket(hub *Hub, sm *auth.SessionManager, w http.ResponseWriter, r
 			Timestamp: time.Now(),
 			Data: map[string]interface{}{
 				"status":  "connected",
-				"user_id": session.UserID,
+				"user_id": userID,
 			},
 		}
 		client.hub.sendToClient(client, statusMessage)
@@ -181,6 +182,19 @@ func (h *Hub) Stop() {
 	close(h.stop)
 }
 
+// Ping reports whether the Run loop is still picking up work, waiting at most
+// until ctx is done
+func (h *Hub) Ping(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case h.ping <- reply:
+	case <-ctx.Done():
+		return fmt.Errorf("websocket hub is not responding: %w", ctx.Err())
+	}
+	<-reply
+	return nil
+}
+
 // Run starts the hub and handles client connections
 func (h *Hub) Run() {
 	// Start cleanup routine
@@ -254,6 +268,9 @@ func (h *Hub) Run() {
 
 			h.handleBroadcastMessage(&wsMsg)
 
+		case reply := <-h.ping:
+			close(reply)
+
 		case <-h.stop:
 			h.mu.Lock()
 			for client := range h.clients {
@@ -281,6 +298,7 @@ func (h *Hub) sendToClient(client *Client, message WSMessage) {
 		}
 	default:
 		// Client's send channel is full, close the connection
+		metrics.WebSocketSendDrops.Inc("direct")
 		h.unregisterClient(client)
 	}
 }
@@ -700,16 +718,19 @@ func (h *Hub) handleFullChannel(client *Client, messageBytes []byte) {
 	select {
 	case <-client.send:
 		// Drained one message, try to send the notification again
+		metrics.WebSocketSendDrops.Inc("notification")
 		select {
 		case client.send <- messageBytes:
 			log.Printf("WebSocket: Notification sent to user %d after channel cleanup", client.userID)
 		default:
 			log.Printf("WebSocket: Failed to send notification to user %d - channel still full", client.userID)
+			metrics.WebSocketSendDrops.Inc("notification")
 			// Consider disconnecting the client if channel is consistently full
 			h.unregisterClient(client)
 		}
 	case <-time.After(1 * time.Second):
 		log.Printf("WebSocket: Timeout waiting for channel cleanup for user %d", client.userID)
+		metrics.WebSocketSendDrops.Inc("notification")
 		// Channel is consistently full, disconnect the client
 		h.unregisterClient(client)
 	}
@@ -746,6 +767,47 @@ func (h *Hub) cleanupRoutine() {
 	}
 }
 
+// DisconnectUser closes the user's connection, e.g. after their sessions were revoked
+func (h *Hub) DisconnectUser(userID int) {
+	h.mu.RLock()
+	client, exists := h.userClients[userID]
+	h.mu.RUnlock()
+
+	if exists {
+		log.Printf("WebSocket: Disconnecting user %d", userID)
+		h.unregisterClient(client)
+	}
+}
+
+// ClientCount returns the number of connected clients
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// GroupSubscriptionCount returns how many group channels connected clients
+// are subscribed to, summed over all groups
+func (h *Hub) GroupSubscriptionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	count := 0
+	for _, clients := range h.groupClients {
+		count += len(clients)
+	}
+	return count
+}
+
+// RegisterMetrics exposes the hub's connection gauges. Call it once per registry.
+func (h *Hub) RegisterMetrics(registry *metrics.Registry) {
+	registry.NewGaugeFunc("ripple_websocket_clients", "Connected WebSocket clients.", func() float64 {
+		return float64(h.ClientCount())
+	})
+	registry.NewGaugeFunc("ripple_websocket_group_subscriptions", "Group subscriptions of connected WebSocket clients.", func() float64 {
+		return float64(h.GroupSubscriptionCount())
+	})
+}
+
 // SendToUser sends a message to a specific user
 func (h *Hub) SendToUser(userID int, message WSMessage) {
 	h.mu.RLock()
@@ -787,6 +849,7 @@ func (h *Hub) BroadcastToGroup(groupID int, message WSMessage, senderID int) {
 			default:
 				// Client's send channel is full, skip this client
 				log.Printf("WebSocket: Client %d send channel full, skipping", client.userID)
+				metrics.WebSocketSendDrops.Inc("group_broadcast")
 			}
 		}
 	}
@@ -6,22 +6,38 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"ripple/pkg/auth"
 	"ripple/pkg/config"
+	"ripple/pkg/constants"
 	"ripple/pkg/db"
 	"ripple/pkg/handlers"
+	"ripple/pkg/logging"
+	"ripple/pkg/mail"
+	"ripple/pkg/metrics"
 	"ripple/pkg/models"
+	"ripple/pkg/oidc"
 	"ripple/pkg/router"
+	"ripple/pkg/scheduler"
 	"ripple/pkg/websocket"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const (
+	// How often background jobs such as the account purge run
+	backgroundJobInterval = time.Hour
+	// Old notifications are removed once a day
+	notificationCleanupInterval = 24 * time.Hour
+)
+
 func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
+	logging.Setup(cfg.LogLevel, cfg.LogFormat)
 
 	// Initialize database
 	database, err := db.NewDatabase(cfg.DatabasePath)
@@ -34,6 +50,10 @@ func main() {
 	if err := database.RunMigrations(cfg.MigrationsPath); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
+	migrationVersion, err := db.LatestMigrationVersion(cfg.MigrationsPath)
+	if err != nil {
+		log.Fatalf("Failed to determine migration version: %v", err)
+	}
 
 	// Initialize repositories
 	userRepo := models.NewUserRepository(database.DB)
@@ -45,9 +65,43 @@ func main() {
 	eventRepo := models.NewEventRepository(database.DB)
 	notificationRepo := models.NewNotificationRepository(database.DB)
 	messageRepo := models.NewMessageRepository(database.DB)
+	passwordResetRepo := models.NewPasswordResetRepository(database.DB)
+	twoFactorRepo := models.NewTwoFactorRepository(database.DB)
+	emailVerificationRepo := models.NewEmailVerificationRepository(database.DB)
+	loginAttemptRepo := models.NewLoginAttemptRepository(database.DB)
+	identityRepo := models.NewIdentityRepository(database.DB)
+	accountDeletionRepo := models.NewAccountDeletionRepository(database.DB)
+	dataExportRepo := models.NewDataExportRepository(database.DB)
+	adminRepo := models.NewAdminRepository(database.DB)
+
+	// Promote the configured admins; their accounts must already exist and be verified
+	if len(cfg.AdminEmails) > 0 {
+		promoted, skipped, err := adminRepo.PromoteByEmail(cfg.AdminEmails, constants.RoleAdmin)
+		if err != nil {
+			log.Fatalf("Failed to promote admins: %v", err)
+		}
+		if len(skipped) > 0 {
+			log.Printf("Admin bootstrap: skipped %s, no account with a verified email", strings.Join(skipped, ", "))
+		}
+		log.Printf("Admin bootstrap: %d of %d configured accounts promoted", promoted, len(cfg.AdminEmails))
+	}
 
 	// Initialize session manager
 	sessionManager := auth.NewSessionManager(database.DB)
+	sessionManager.SetTimeouts(cfg.SessionIdleTimeout, cfg.SessionAbsoluteTimeout)
+	argon2Params, err := auth.NewArgon2Params(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+	if err != nil {
+		log.Fatalf("Invalid password hashing configuration: %v", err)
+	}
+	if err := auth.SetArgon2Params(argon2Params); err != nil {
+		log.Fatalf("Invalid password hashing configuration: %v", err)
+	}
+
+	// Initialize mailer
+	mailer, err := mail.NewMailer(cfg.MailDriver, cfg.MailFrom, cfg.MailDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
 
 	// Initialize WebSocket hub
 	wsHub := websocket.NewHub(database.DB)
@@ -56,8 +110,26 @@ func main() {
 	// Set WebSocket hub for real-time notifications
 	notificationRepo.SetWebSocketHub(wsHub)
 
+	// Initialize the background job scheduler; jobs are registered below
+	jobScheduler := scheduler.New()
+
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, sessionManager)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, twoFactorRepo, emailVerificationRepo, loginAttemptRepo, sessionManager, mailer, cfg.FrontendURL)
+	passwordHandler := handlers.NewPasswordHandler(userRepo, passwordResetRepo, sessionManager, mailer, cfg.FrontendURL)
+	var oidcHandler *handlers.OIDCHandler
+	if cfg.OIDCIssuerURL != "" {
+		provider := oidc.NewProvider(oidc.Config{
+			Name:         cfg.OIDCProviderName,
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+		})
+		oidcHandler = handlers.NewOIDCHandler(provider, userRepo, identityRepo, twoFactorRepo, sessionManager, cfg.FrontendURL)
+		log.Printf("OIDC login enabled with provider %s (%s)", cfg.OIDCProviderName, cfg.OIDCIssuerURL)
+	}
+	accountHandler := handlers.NewAccountHandler(userRepo, accountDeletionRepo, sessionManager, mailer, cfg.UploadsPath, cfg.AccountDeletionGracePeriod)
+	dataExportHandler := handlers.NewDataExportHandler(dataExportRepo, notificationRepo, cfg.ExportsPath, cfg.UploadsPath, cfg.APIURL, cfg.DataExportLinkTTL)
 	followHandler := handlers.NewFollowHandler(followRepo, userRepo, notificationRepo)
 	postHandler := handlers.NewPostHandler(postRepo)
 	likeHandler := handlers.NewLikeHandler(likeRepo, postRepo)
@@ -66,11 +138,19 @@ func main() {
 	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
 	uploadHandler := handlers.NewUploadHandler(cfg)
 	chatHandler := handlers.NewChatHandler(messageRepo, followRepo, groupRepo, userRepo, wsHub)
+	adminHandler := handlers.NewAdminHandler(adminRepo, sessionManager, wsHub, jobScheduler, cfg.UploadsPath)
+	wsHub.RegisterMetrics(metrics.Default)
+	metricsHandler := handlers.NewMetricsHandler(metrics.Default, cfg.MetricsAllowlist, cfg.MetricsToken)
+	healthHandler := handlers.NewHealthHandler(database, migrationVersion, cfg.UploadsPath, wsHub)
 
 	// Setup routes
 	handler := router.SetupRoutes(
 		cfg,
 		authHandler,
+		passwordHandler,
+		oidcHandler,
+		accountHandler,
+		dataExportHandler,
 		followHandler,
 		postHandler,
 		likeHandler,
@@ -79,10 +159,41 @@ func main() {
 		notificationHandler,
 		uploadHandler,
 		chatHandler,
+		adminHandler,
+		metricsHandler,
+		healthHandler,
 		sessionManager,
 		wsHub,
 	)
 
+	// Background jobs
+	jobs := []struct {
+		name     string
+		interval time.Duration
+		run      scheduler.JobFunc
+	}{
+		{"session cleanup", backgroundJobInterval, sessionManager.CleanupExpiredSessions},
+		{"access token cleanup", backgroundJobInterval, sessionManager.CleanupExpiredAccessTokens},
+		{"websocket ticket cleanup", backgroundJobInterval, sessionManager.CleanupExpiredWebSocketTickets},
+		{"password reset token cleanup", backgroundJobInterval, passwordResetRepo.CleanupExpiredTokens},
+		{"email verification token cleanup", backgroundJobInterval, emailVerificationRepo.CleanupExpiredTokens},
+		{"two-factor challenge cleanup", backgroundJobInterval, twoFactorRepo.CleanupExpiredChallenges},
+		{"login attempt cleanup", backgroundJobInterval, authHandler.CleanupLoginAttempts},
+		{"oidc login state cleanup", backgroundJobInterval, identityRepo.CleanupExpiredLoginStates},
+		{"notification cleanup", notificationCleanupInterval, func() error {
+			return notificationRepo.CleanupOldNotifications(int(cfg.NotificationRetentionDays))
+		}},
+		{"account purge", backgroundJobInterval, accountHandler.PurgeDueAccounts},
+		{"data export", backgroundJobInterval, dataExportHandler.ProcessPendingExports},
+		{"data export cleanup", backgroundJobInterval, dataExportHandler.CleanupExpiredExports},
+	}
+	for _, job := range jobs {
+		if err := jobScheduler.Register(job.name, job.interval, job.run); err != nil {
+			log.Fatalf("Failed to register background job: %v", err)
+		}
+	}
+	jobScheduler.Start()
+
 	// Create server
 	server := &http.Server{
 		Addr:         ":" + cfg.ServerPort,
@@ -102,20 +213,27 @@ func main() {
 		}
 	}()
 
-	// Wait for interrupt signal
+	// Wait for interrupt or termination signal
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 
-	// Graceful shutdown
+	// Graceful shutdown: fail readiness first and keep serving while load
+	// balancers notice and drain traffic away
 	log.Println("Shutting down server...")
+	healthHandler.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDrainDelay)
 
-	// Stop WebSocket hub
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Stop background jobs and the WebSocket hub
+	if err := jobScheduler.Stop(ctx); err != nil {
+		log.Printf("Background job shutdown error: %v", err)
+	}
 	wsHub.Stop()
 
 	// Shutdown HTTP server
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("Server shutdown error: %v", err)
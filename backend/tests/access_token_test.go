@@ -0,0 +1,245 @@
+// backend/tests/access_token_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/constants"
+	"ripple/pkg/db"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/metrics"
+	"ripple/pkg/models"
+	"ripple/pkg/router"
+	"ripple/pkg/scheduler"
+	"ripple/pkg/websocket"
+)
+
+// newTestRouter wires every handler the same way server.go does
+func newTestRouter(t *testing.T, database *db.Database, sessionManager *auth.SessionManager) http.Handler {
+	return newTestRouterWithConfig(t, database, sessionManager, nil)
+}
+
+// newTestRouterWithConfig is newTestRouter with a chance to change the configuration first
+func newTestRouterWithConfig(t *testing.T, database *db.Database, sessionManager *auth.SessionManager, configure func(*config.Config)) http.Handler {
+	cfg := &config.Config{
+		UploadsPath:    t.TempDir(),
+		ExportsPath:    t.TempDir(),
+		AllowedOrigins: []string{"http://localhost:3000"},
+		MaxFileSize:    10 << 20,
+		FrontendURL:    "http://localhost:3000",
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	mailer := mail.NewLogMailer("test@ripple.local")
+
+	userRepo := models.NewUserRepository(database.DB)
+	followRepo := models.NewFollowRepository(database.DB)
+	postRepo := models.NewPostRepository(database.DB)
+	groupRepo := models.NewGroupRepository(database.DB)
+	notificationRepo := models.NewNotificationRepository(database.DB)
+
+	wsHub := websocket.NewHub(database.DB)
+	go wsHub.Run()
+	notificationRepo.SetWebSocketHub(wsHub)
+
+	migrationVersion, err := db.LatestMigrationVersion("../pkg/db/migrations/sqlite")
+	if err != nil {
+		t.Fatalf("Failed to read migration version: %v", err)
+	}
+
+	return router.SetupRoutes(
+		cfg,
+		handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB),
+			models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB),
+			sessionManager, mailer, cfg.FrontendURL),
+		handlers.NewPasswordHandler(userRepo, models.NewPasswordResetRepository(database.DB), sessionManager, mailer, cfg.FrontendURL),
+		nil,
+		handlers.NewAccountHandler(userRepo, models.NewAccountDeletionRepository(database.DB), sessionManager, mailer,
+			cfg.UploadsPath, 30*24*time.Hour),
+		handlers.NewDataExportHandler(models.NewDataExportRepository(database.DB), notificationRepo, cfg.ExportsPath,
+			cfg.UploadsPath, "http://localhost:8000", 24*time.Hour),
+		handlers.NewFollowHandler(followRepo, userRepo, notificationRepo),
+		handlers.NewPostHandler(postRepo),
+		handlers.NewLikeHandler(models.NewLikeRepository(database.DB), postRepo),
+		handlers.NewGroupHandler(groupRepo, models.NewGroupPostRepository(database.DB), notificationRepo, userRepo),
+		handlers.NewEventHandler(models.NewEventRepository(database.DB), groupRepo, notificationRepo),
+		handlers.NewNotificationHandler(notificationRepo),
+		handlers.NewUploadHandler(cfg),
+		handlers.NewChatHandler(models.NewMessageRepository(database.DB), followRepo, groupRepo, userRepo, wsHub),
+		handlers.NewAdminHandler(models.NewAdminRepository(database.DB), sessionManager, wsHub, scheduler.New(), cfg.UploadsPath),
+		handlers.NewMetricsHandler(metrics.Default, []string{"127.0.0.1"}, "test-metrics-token"),
+		handlers.NewHealthHandler(database, migrationVersion, cfg.UploadsPath, wsHub),
+		sessionManager,
+		wsHub,
+	)
+}
+
+func TestPersonalAccessTokens(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+
+	user, session := createTestUser(t, userRepo, sessionManager, "tokens@test.com", true)
+
+	request := func(method, path string, payload any, cookie, bearer string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	var token string
+	var tokenID int
+
+	t.Run("Create token", func(t *testing.T) {
+		rr, _ := request("POST", "/api/auth/tokens/create", map[string]any{
+			"name":   "bad",
+			"scopes": []string{"posts:write", "everything"},
+		}, session.ID, "")
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected unknown scope to be rejected, got %d", rr.Code)
+		}
+
+		rr, response := request("POST", "/api/auth/tokens/create", map[string]any{
+			"name":            "Feed bot",
+			"scopes":          []string{"posts:read", "posts:write"},
+			"expires_in_days": 7,
+		}, session.ID, "")
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		data := response["data"].(map[string]interface{})
+		token = data["token"].(string)
+		tokenID = int(data["access_token"].(map[string]interface{})["id"].(float64))
+		if !strings.HasPrefix(token, auth.AccessTokenPrefix) {
+			t.Errorf("Expected token prefix %s, got %s", auth.AccessTokenPrefix, token)
+		}
+
+		var storedHash string
+		database.DB.QueryRow(`SELECT token_hash FROM personal_access_tokens WHERE id = ?`, tokenID).Scan(&storedHash)
+		if storedHash == token || storedHash != auth.HashToken(token) {
+			t.Errorf("Expected only the token hash to be stored")
+		}
+	})
+
+	t.Run("Token works within its scopes", func(t *testing.T) {
+		if rr, _ := request("GET", "/api/posts/feed", nil, "", token); rr.Code != http.StatusOK {
+			t.Errorf("Expected feed to be readable, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		rr, _ := request("POST", "/api/posts", map[string]any{
+			"content":       "Posted by a bot",
+			"privacy_level": "public",
+		}, "", token)
+		if rr.Code != http.StatusCreated {
+			t.Errorf("Expected post to be created, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("Token is rejected outside its scopes", func(t *testing.T) {
+		rr, response := request("GET", "/api/chat/conversations", nil, "", token)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", rr.Code)
+		}
+		if code := response["error"].(map[string]interface{})["code"]; code != constants.CodeInsufficientScope {
+			t.Errorf("Expected error code %s, got %v", constants.CodeInsufficientScope, code)
+		}
+
+		// Tokens can never manage the account, whatever their scopes
+		if rr, _ := request("GET", "/api/auth/tokens", nil, "", token); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected token management to require a session, got %d", rr.Code)
+		}
+		if rr, _ := request("GET", "/api/auth/sessions", nil, "", token); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected session list to require a session, got %d", rr.Code)
+		}
+	})
+
+	t.Run("List shows last use", func(t *testing.T) {
+		rr, response := request("GET", "/api/auth/tokens", nil, session.ID, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+
+		tokens := response["data"].(map[string]interface{})["tokens"].([]interface{})
+		if len(tokens) != 1 {
+			t.Fatalf("Expected 1 token, got %d", len(tokens))
+		}
+		listed := tokens[0].(map[string]interface{})
+		if listed["last_used_at"] == nil {
+			t.Errorf("Expected last_used_at to be set")
+		}
+		if _, ok := listed["token"]; ok {
+			t.Errorf("Token secret must not be listed")
+		}
+	})
+
+	t.Run("Expired and invalid tokens", func(t *testing.T) {
+		expired, _, err := sessionManager.CreateAccessToken(user.ID, "old", []string{auth.ScopePostsRead}, time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+		if rr, _ := request("GET", "/api/posts/feed", nil, "", expired); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected expired token to be rejected, got %d", rr.Code)
+		}
+		if rr, _ := request("GET", "/api/posts/feed", nil, "", "rpl_invalid"); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected unknown token to be rejected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Failed last use write does not reject token", func(t *testing.T) {
+		unused, _, err := sessionManager.CreateAccessToken(user.ID, "busy", []string{auth.ScopePostsRead}, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+
+		// A busy database must not break scripts using the token
+		database.DB.Exec(`CREATE TRIGGER fail_token_touch BEFORE UPDATE OF last_used_at ON personal_access_tokens
+			BEGIN SELECT RAISE(ABORT, 'database is locked'); END`)
+		defer database.DB.Exec(`DROP TRIGGER fail_token_touch`)
+		if rr, _ := request("GET", "/api/posts/feed", nil, "", unused); rr.Code != http.StatusOK {
+			t.Errorf("Expected the token to stay valid when its last use cannot be written, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Revoke token", func(t *testing.T) {
+		path := fmt.Sprintf("/api/auth/tokens/revoke/%d", tokenID)
+		if rr, _ := request("DELETE", path, nil, session.ID, ""); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr, _ := request("DELETE", path, nil, session.ID, ""); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected second revoke to return 404, got %d", rr.Code)
+		}
+		if rr, _ := request("GET", "/api/posts/feed", nil, "", token); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected revoked token to be rejected, got %d", rr.Code)
+		}
+	})
+}
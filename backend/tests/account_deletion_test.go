@@ -0,0 +1,185 @@
+// backend/tests/account_deletion_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+func TestAccountDeletion(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+
+	user, session := createTestUser(t, userRepo, sessionManager, "leaving@test.com", true)
+	other, otherSession := createTestUser(t, userRepo, sessionManager, "staying@test.com", true)
+
+	request := func(method, path string, payload any, cookie string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	deletionScheduled := func() bool {
+		var scheduled bool
+		database.DB.QueryRow(`SELECT deletion_scheduled_at IS NOT NULL FROM users WHERE id = ?`, user.ID).Scan(&scheduled)
+		return scheduled
+	}
+
+	profilePath := fmt.Sprintf("/api/users/%d", user.ID)
+	credentials := map[string]string{"email": user.Email, "password": "password123"}
+
+	t.Run("Wrong password is rejected", func(t *testing.T) {
+		rr := request("POST", "/api/auth/account/delete", map[string]string{"password": "wrong"}, session.ID)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rr.Code)
+		}
+		if deletionScheduled() {
+			t.Errorf("Deletion must not be scheduled")
+		}
+	})
+
+	t.Run("Request disables login sessions and hides profile", func(t *testing.T) {
+		token, _, _ := sessionManager.CreateAccessToken(user.ID, "bot", []string{auth.ScopePostsRead}, time.Now().Add(time.Hour))
+
+		rr := request("POST", "/api/auth/account/delete", map[string]string{"password": "password123"}, session.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if !deletionScheduled() {
+			t.Fatalf("Expected deletion to be scheduled")
+		}
+
+		if _, err := sessionManager.GetSession(session.ID); err == nil {
+			t.Errorf("Expected sessions to be revoked")
+		}
+		if _, err := sessionManager.GetAccessToken(token); err == nil {
+			t.Errorf("Expected access tokens to be revoked")
+		}
+		if rr := request("GET", profilePath, nil, otherSession.ID); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected hidden profile to return 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Login cancels the request", func(t *testing.T) {
+		rr := request("POST", "/api/auth/login", credentials, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if deletionScheduled() {
+			t.Errorf("Expected deletion to be cancelled")
+		}
+		if rr := request("GET", profilePath, nil, otherSession.ID); rr.Code != http.StatusOK {
+			t.Errorf("Expected profile to be visible again, got %d", rr.Code)
+		}
+	})
+
+	t.Run("OIDC accounts set a password with the reset flow first", func(t *testing.T) {
+		// Like accounts created by an OIDC login: the password is random and never shown
+		unknownPassword, _, _ := auth.GenerateToken()
+		passwordHash, _ := auth.HashPassword(unknownPassword)
+		oidcUser, err := userRepo.CreateUser(&models.CreateUserRequest{
+			Email: "oidc-leaving@test.com", FirstName: "Test", LastName: "User", DateOfBirth: "1990-01-01",
+		}, passwordHash)
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		models.NewIdentityRepository(database.DB).LinkIdentity(oidcUser.ID, "mock", "subject-leaving", oidcUser.Email)
+
+		resetToken, resetTokenHash, _ := auth.GenerateToken()
+		models.NewPasswordResetRepository(database.DB).CreateToken(oidcUser.ID, resetTokenHash, time.Now().Add(time.Hour))
+		rr := request("POST", "/api/v1/auth/password/reset", map[string]string{"token": resetToken, "new_password": "chosen-password"}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected the reset to succeed, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		oidcSession, _ := sessionManager.CreateSession(oidcUser.ID, "test-agent", "127.0.0.1")
+		rr = request("POST", "/api/v1/auth/account/delete", map[string]string{"password": "chosen-password"}, oidcSession.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected deletion with the new password, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if deleted, _ := userRepo.GetUserByID(oidcUser.ID); !deleted.IsPendingDeletion() {
+			t.Errorf("Expected deletion to be scheduled")
+		}
+	})
+
+	t.Run("Purge removes rows and files after the grace period", func(t *testing.T) {
+		uploadsPath := t.TempDir()
+		for _, dir := range []string{"avatars", "posts"} {
+			os.MkdirAll(filepath.Join(uploadsPath, dir), 0755)
+		}
+		writeUpload := func(name string) {
+			if err := os.WriteFile(filepath.Join(uploadsPath, name), []byte("image"), 0644); err != nil {
+				t.Fatalf("Failed to write upload: %v", err)
+			}
+		}
+		writeUpload("avatars/leaving.jpg")
+		writeUpload("posts/leaving.jpg")
+		writeUpload("avatars/staying.jpg")
+
+		userRepo.UpdateProfile(user.ID, map[string]interface{}{
+			"avatar_path": "/uploads/avatars/leaving.jpg",
+			// Points at a file that still belongs to another user
+			"cover_path": "/uploads/avatars/staying.jpg",
+		})
+		userRepo.UpdateProfile(other.ID, map[string]interface{}{"avatar_path": "/uploads/avatars/staying.jpg"})
+		database.DB.Exec(`INSERT INTO posts (user_id, content, image_path) VALUES (?, 'bye', '/uploads/posts/leaving.jpg')`, user.ID)
+
+		// Schedule the purge in the past, as if the grace period had run out
+		if err := userRepo.RequestDeletion(user.ID, time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("Failed to schedule deletion: %v", err)
+		}
+
+		if rr := request("POST", "/api/auth/login", credentials, ""); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected login after the grace period to fail, got %d", rr.Code)
+		}
+
+		accountHandler := handlers.NewAccountHandler(userRepo, models.NewAccountDeletionRepository(database.DB), sessionManager,
+			mail.NewLogMailer("test@ripple.local"), uploadsPath, time.Hour)
+		if err := accountHandler.PurgeDueAccounts(); err != nil {
+			t.Fatalf("Purge failed: %v", err)
+		}
+
+		if _, err := userRepo.GetUserByID(user.ID); err == nil {
+			t.Errorf("Expected user to be deleted")
+		}
+		var posts int
+		database.DB.QueryRow(`SELECT COUNT(*) FROM posts WHERE user_id = ?`, user.ID).Scan(&posts)
+		if posts != 0 {
+			t.Errorf("Expected posts to be deleted, found %d", posts)
+		}
+
+		for _, name := range []string{"avatars/leaving.jpg", "posts/leaving.jpg"} {
+			if _, err := os.Stat(filepath.Join(uploadsPath, name)); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be removed", name)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(uploadsPath, "avatars/staying.jpg")); err != nil {
+			t.Errorf("File still used by another user must be kept: %v", err)
+		}
+	})
+}
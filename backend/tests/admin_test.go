@@ -0,0 +1,200 @@
+// backend/tests/admin_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+)
+
+func TestAdminAPI(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	adminRepo := models.NewAdminRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+
+	admin, adminSession := createTestUser(t, userRepo, sessionManager, "admin@test.com", true)
+	moderator, moderatorSession := createTestUser(t, userRepo, sessionManager, "moderator@test.com", true)
+	member, memberSession := createTestUser(t, userRepo, sessionManager, "member@test.com", true)
+
+	// Registration is open, so an unverified account with a listed email is not promoted
+	hashedPassword, _ := auth.HashPassword("password123")
+	squatter, _ := userRepo.CreateUser(&models.CreateUserRequest{
+		Email: "squatter@test.com", FirstName: "Test", LastName: "User", DateOfBirth: "1990-01-01",
+	}, hashedPassword)
+
+	promoted, skipped, err := adminRepo.PromoteByEmail([]string{" Admin@test.com ", "squatter@test.com", "missing@test.com"}, constants.RoleAdmin)
+	if err != nil || promoted != 1 {
+		t.Fatalf("Expected admin bootstrap to promote one account, got %d: %v", promoted, err)
+	}
+	if len(skipped) != 2 || skipped[0] != "squatter@test.com" || skipped[1] != "missing@test.com" {
+		t.Errorf("Expected the unverified and missing accounts to be skipped, got %v", skipped)
+	}
+	if user, _ := userRepo.GetUserByID(squatter.ID); user.Role != constants.RoleUser {
+		t.Errorf("Expected the unverified account to keep its role, got %s", user.Role)
+	}
+	if promoted, skipped, _ := adminRepo.PromoteByEmail([]string{"admin@test.com"}, constants.RoleAdmin); promoted != 0 || len(skipped) != 0 {
+		t.Errorf("Expected an existing admin to be neither promoted nor skipped, got %d %v", promoted, skipped)
+	}
+	adminRepo.SetRole(moderator.ID, constants.RoleModerator)
+
+	request := func(method, path string, payload any, cookie string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	t.Run("Regular users cannot use the admin API", func(t *testing.T) {
+		rr, response := request("GET", "/api/admin/users", nil, memberSession.ID)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", rr.Code)
+		}
+		if code := response["error"].(map[string]interface{})["code"]; code != constants.CodeInsufficientRole {
+			t.Errorf("Expected code %s, got %v", constants.CodeInsufficientRole, code)
+		}
+	})
+
+	t.Run("Moderators cannot use admin-only routes", func(t *testing.T) {
+		if rr, _ := request("GET", "/api/admin/users", nil, moderatorSession.ID); rr.Code != http.StatusOK {
+			t.Errorf("Expected moderator to list users, got %d", rr.Code)
+		}
+		if rr, _ := request("GET", "/api/admin/stats", nil, moderatorSession.ID); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for stats, got %d", rr.Code)
+		}
+		path := fmt.Sprintf("/api/admin/users/role/%d", member.ID)
+		if rr, _ := request("PUT", path, map[string]string{"role": "admin"}, moderatorSession.ID); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for role change, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Staff cannot act on equal or higher roles", func(t *testing.T) {
+		path := fmt.Sprintf("/api/admin/users/suspend/%d", admin.ID)
+		if rr, _ := request("POST", path, map[string]string{"reason": "coup"}, moderatorSession.ID); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 suspending an admin, got %d", rr.Code)
+		}
+		path = fmt.Sprintf("/api/admin/users/suspend/%d", moderator.ID)
+		if rr, _ := request("POST", path, map[string]string{"reason": "self"}, moderatorSession.ID); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 suspending oneself, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Suspension signs the user out and blocks login", func(t *testing.T) {
+		path := fmt.Sprintf("/api/admin/users/suspend/%d", member.ID)
+		if rr, _ := request("POST", path, map[string]string{}, moderatorSession.ID); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected a reason to be required, got %d", rr.Code)
+		}
+		if rr, _ := request("POST", path, map[string]string{"reason": "spam"}, moderatorSession.ID); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		if rr, _ := request("GET", "/api/auth/profile", nil, memberSession.ID); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected suspended user's session to be revoked, got %d", rr.Code)
+		}
+
+		rr, response := request("POST", "/api/auth/login", map[string]string{"email": member.Email, "password": "password123"}, "")
+		if rr.Code != http.StatusForbidden || response["error"].(map[string]interface{})["code"] != constants.CodeAccountSuspended {
+			t.Errorf("Expected suspended login to be rejected, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		rr, response = request("GET", "/api/admin/users?q=member", nil, moderatorSession.ID)
+		users := response["data"].(map[string]interface{})["users"].([]interface{})
+		if rr.Code != http.StatusOK || len(users) != 1 || users[0].(map[string]interface{})["suspension_reason"] != "spam" {
+			t.Errorf("Expected suspended user in listing, got %s", rr.Body.String())
+		}
+
+		path = fmt.Sprintf("/api/admin/users/unsuspend/%d", member.ID)
+		if rr, _ := request("POST", path, nil, moderatorSession.ID); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if rr, _ := request("POST", "/api/auth/login", map[string]string{"email": member.Email, "password": "password123"}, ""); rr.Code != http.StatusOK {
+			t.Errorf("Expected login after unsuspension, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Moderators can delete content of regular users", func(t *testing.T) {
+		var memberPostID, adminPostID int64
+		result, _ := database.DB.Exec(`INSERT INTO posts (user_id, content) VALUES (?, 'spam')`, member.ID)
+		memberPostID, _ = result.LastInsertId()
+		result, _ = database.DB.Exec(`INSERT INTO posts (user_id, content) VALUES (?, 'announcement')`, admin.ID)
+		adminPostID, _ = result.LastInsertId()
+
+		if rr, _ := request("DELETE", "/api/admin/content/video/1", nil, moderatorSession.ID); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected unknown content type to be rejected, got %d", rr.Code)
+		}
+		if rr, _ := request("DELETE", fmt.Sprintf("/api/admin/content/post/%d", adminPostID), nil, moderatorSession.ID); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 deleting an admin's post, got %d", rr.Code)
+		}
+		if rr, _ := request("DELETE", fmt.Sprintf("/api/admin/content/post/%d", memberPostID), nil, moderatorSession.ID); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var count int
+		database.DB.QueryRow(`SELECT COUNT(*) FROM posts WHERE id = ?`, memberPostID).Scan(&count)
+		if count != 0 {
+			t.Errorf("Expected post to be deleted")
+		}
+		if rr, _ := request("DELETE", fmt.Sprintf("/api/admin/content/post/%d", memberPostID), nil, moderatorSession.ID); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for deleted post, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Admins manage roles and force logout", func(t *testing.T) {
+		_, newSession := createTestSession(t, sessionManager, member.ID)
+
+		path := fmt.Sprintf("/api/admin/users/role/%d", member.ID)
+		if rr, _ := request("PUT", path, map[string]string{"role": "owner"}, adminSession.ID); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected invalid role to be rejected, got %d", rr.Code)
+		}
+		if rr, _ := request("PUT", path, map[string]string{"role": constants.RoleModerator}, adminSession.ID); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr, _ := request("GET", "/api/admin/users", nil, newSession); rr.Code != http.StatusOK {
+			t.Errorf("Expected promoted user to reach the admin API, got %d", rr.Code)
+		}
+
+		path = fmt.Sprintf("/api/admin/users/logout/%d", member.ID)
+		if rr, _ := request("POST", path, nil, adminSession.ID); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if rr, _ := request("GET", "/api/auth/profile", nil, newSession); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected sessions to be revoked, got %d", rr.Code)
+		}
+
+		rr, response := request("GET", "/api/admin/stats", nil, adminSession.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		stats := response["data"].(map[string]interface{})
+		if stats["users"] != float64(4) || stats["admins"] != float64(1) || stats["moderators"] != float64(2) {
+			t.Errorf("Unexpected stats: %v", stats)
+		}
+	})
+}
+
+func createTestSession(t *testing.T, sessionManager *auth.SessionManager, userID int) (*models.Session, string) {
+	session, err := sessionManager.CreateSession(userID, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	return session, session.ID
+}
@@ -13,6 +13,7 @@ import (
 	"ripple/pkg/auth"
 	"ripple/pkg/db"
 	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
 	"ripple/pkg/models"
 )
 
@@ -61,7 +62,7 @@ func TestUserRegistration(t *testing.T) {
 	followRepo := models.NewFollowRepository(database.DB)
 	postRepo := models.NewPostRepository(database.DB)
 	sessionManager := auth.NewSessionManager(database.DB)
-	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, sessionManager)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
 
 	tests := []struct {
 		name           string
@@ -175,7 +176,7 @@ func TestUserLogin(t *testing.T) {
 	followRepo := models.NewFollowRepository(database.DB)
 	postRepo := models.NewPostRepository(database.DB)
 	sessionManager := auth.NewSessionManager(database.DB)
-	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, sessionManager)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
 
 	// First register a user
 	_, err := createAuthTestUser(userRepo)
@@ -262,7 +263,7 @@ func TestCompleteAuthFlow(t *testing.T) {
 	followRepo := models.NewFollowRepository(database.DB)
 	postRepo := models.NewPostRepository(database.DB)
 	sessionManager := auth.NewSessionManager(database.DB)
-	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, sessionManager)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
 
 	// Test registration
 	regPayload := map[string]interface{}{
@@ -360,7 +361,7 @@ func TestSessionManagement(t *testing.T) {
 	}
 
 	// Test session creation
-	session, err := sessionManager.CreateSession(user.ID)
+	session, err := sessionManager.CreateSession(user.ID, "test-agent", "127.0.0.1")
 	if err != nil {
 		t.Fatalf("Failed to create session: %v", err)
 	}
@@ -404,6 +405,272 @@ func TestSessionManagement(t *testing.T) {
 	}
 }
 
+func TestSessionRevocation(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	followRepo := models.NewFollowRepository(database.DB)
+	postRepo := models.NewPostRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
+
+	user, err := createAuthTestUser(userRepo)
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	laptop, _ := sessionManager.CreateSession(user.ID, "Laptop Browser", "10.0.0.1")
+	phone, _ := sessionManager.CreateSession(user.ID, "Phone App", "10.0.0.2")
+	tablet, _ := sessionManager.CreateSession(user.ID, "Tablet Browser", "10.0.0.3")
+
+	t.Run("List sessions", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/auth/sessions", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: laptop.ID})
+
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.GetSessions)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		sessions := response["data"].(map[string]interface{})["sessions"].([]interface{})
+		if len(sessions) != 3 {
+			t.Fatalf("Expected 3 sessions, got %d", len(sessions))
+		}
+
+		currentCount := 0
+		for _, s := range sessions {
+			session := s.(map[string]interface{})
+			if session["id"] == laptop.ID || session["id"] == phone.ID {
+				t.Errorf("Session token must not be exposed in listing")
+			}
+			if session["is_current"].(bool) {
+				currentCount++
+				if session["user_agent"] != "Laptop Browser" || session["ip_address"] != "10.0.0.1" {
+					t.Errorf("Unexpected metadata for current session: %v", session)
+				}
+			}
+		}
+		if currentCount != 1 {
+			t.Errorf("Expected exactly one current session, got %d", currentCount)
+		}
+	})
+
+	t.Run("Revoke one session", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/api/auth/sessions/revoke/"+phone.PublicID(), nil)
+		req.SetPathValue("id", phone.PublicID())
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: laptop.ID})
+
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.RevokeSession)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if _, err := sessionManager.GetSession(phone.ID); err == nil {
+			t.Errorf("Revoked session should no longer be valid")
+		}
+		if _, err := sessionManager.GetSession(laptop.ID); err != nil {
+			t.Errorf("Current session should still be valid: %v", err)
+		}
+	})
+
+	t.Run("Revoke unknown session", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/api/auth/sessions/revoke/doesnotexist", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: laptop.ID})
+
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.RevokeSession)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Revoke all other sessions", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/api/auth/sessions/revoke-others", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: laptop.ID})
+
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.RevokeOtherSessions)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if _, err := sessionManager.GetSession(tablet.ID); err == nil {
+			t.Errorf("Other sessions should be revoked")
+		}
+		// The surviving session is rotated and handed back as a new cookie
+		cookies := rr.Result().Cookies()
+		if len(cookies) == 0 || cookies[0].Value == laptop.ID {
+			t.Fatalf("Expected a rotated session cookie")
+		}
+		if _, err := sessionManager.GetSession(cookies[0].Value); err != nil {
+			t.Errorf("Current session should survive under its new ID: %v", err)
+		}
+	})
+}
+
+func TestSessionExpiryAndRotation(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	followRepo := models.NewFollowRepository(database.DB)
+	postRepo := models.NewPostRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	sessionManager.SetTimeouts(time.Hour, 3*time.Hour)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
+
+	user, err := createAuthTestUser(userRepo)
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+
+	t.Run("Idle and absolute expiry are set separately", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "", "")
+
+		if d := time.Until(session.ExpiresAt); d > time.Hour || d < 59*time.Minute {
+			t.Errorf("Expected idle expiry about 1h away, got %v", d)
+		}
+		if d := time.Until(session.AbsoluteExpiresAt); d > 3*time.Hour || d < 179*time.Minute {
+			t.Errorf("Expected absolute expiry about 3h away, got %v", d)
+		}
+	})
+
+	t.Run("Middleware extends sessions in use", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "", "")
+		database.DB.Exec(`UPDATE sessions SET expires_at = ? WHERE id = ?`, time.Now().Add(10*time.Minute), session.ID)
+
+		req, _ := http.NewRequest("GET", "/api/auth/profile", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.GetProfile)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if rr.Header().Get("Set-Cookie") == "" {
+			t.Errorf("Expected refreshed session cookie")
+		}
+
+		refreshed, err := sessionManager.GetSession(session.ID)
+		if err != nil {
+			t.Fatalf("Session should still be valid: %v", err)
+		}
+		if time.Until(refreshed.ExpiresAt) < 59*time.Minute {
+			t.Errorf("Expected expiry to slide forward, got %v", refreshed.ExpiresAt)
+		}
+	})
+
+	t.Run("Extension never passes the absolute expiry", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "", "")
+		absolute := time.Now().Add(20 * time.Minute)
+		database.DB.Exec(`UPDATE sessions SET expires_at = ?, absolute_expires_at = ? WHERE id = ?`,
+			time.Now().Add(5*time.Minute), absolute, session.ID)
+
+		current, _ := sessionManager.GetSession(session.ID)
+		if _, err := sessionManager.ExtendSession(current); err != nil {
+			t.Fatalf("Failed to extend session: %v", err)
+		}
+		if current.ExpiresAt.After(absolute) {
+			t.Errorf("Expiry %v extended past absolute expiry %v", current.ExpiresAt, absolute)
+		}
+	})
+
+	t.Run("Last use is throttled and its write may fail", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "", "")
+		lastUsed := func() time.Time {
+			var at time.Time
+			database.DB.QueryRow(`SELECT last_used_at FROM sessions WHERE id = ?`, session.ID).Scan(&at)
+			return at
+		}
+
+		recent := time.Now().Add(-10 * time.Second).Truncate(time.Second)
+		database.DB.Exec(`UPDATE sessions SET last_used_at = ? WHERE id = ?`, recent, session.ID)
+		if _, err := sessionManager.GetSession(session.ID); err != nil {
+			t.Fatalf("Failed to get session: %v", err)
+		}
+		if !lastUsed().Equal(recent) {
+			t.Errorf("Expected a recent last use not to be rewritten, got %v", lastUsed())
+		}
+
+		stale := time.Now().Add(-10 * time.Minute)
+		database.DB.Exec(`UPDATE sessions SET last_used_at = ? WHERE id = ?`, stale, session.ID)
+		if _, err := sessionManager.GetSession(session.ID); err != nil {
+			t.Fatalf("Failed to get session: %v", err)
+		}
+		if time.Since(lastUsed()) > time.Minute {
+			t.Errorf("Expected a stale last use to be updated, got %v", lastUsed())
+		}
+
+		// A failed write, e.g. a busy database, must not log the user out
+		database.DB.Exec(`UPDATE sessions SET last_used_at = ? WHERE id = ?`, stale, session.ID)
+		database.DB.Exec(`CREATE TRIGGER fail_session_touch BEFORE UPDATE OF last_used_at ON sessions
+			BEGIN SELECT RAISE(ABORT, 'database is locked'); END`)
+		defer database.DB.Exec(`DROP TRIGGER fail_session_touch`)
+		if _, err := sessionManager.GetSession(session.ID); err != nil {
+			t.Errorf("Expected the session to stay valid when its last use cannot be written: %v", err)
+		}
+	})
+
+	t.Run("Absolutely expired sessions are rejected", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "", "")
+		database.DB.Exec(`UPDATE sessions SET absolute_expires_at = ? WHERE id = ?`, time.Now().Add(-time.Minute), session.ID)
+
+		if _, err := sessionManager.GetSession(session.ID); err == nil {
+			t.Errorf("Session past its absolute expiry should be rejected")
+		}
+	})
+
+	t.Run("Rotation replaces the session ID", func(t *testing.T) {
+		session, _ := sessionManager.CreateSession(user.ID, "Browser", "10.0.0.1")
+
+		rotated, err := sessionManager.RotateSession(session.ID)
+		if err != nil {
+			t.Fatalf("Failed to rotate session: %v", err)
+		}
+		if rotated.ID == session.ID {
+			t.Fatalf("Rotated session should have a new ID")
+		}
+		if _, err := sessionManager.GetSession(session.ID); err == nil {
+			t.Errorf("Old session ID should stop working after rotation")
+		}
+		if _, err := sessionManager.GetSession(rotated.ID); err != nil {
+			t.Errorf("New session ID should work: %v", err)
+		}
+		if !rotated.AbsoluteExpiresAt.Equal(session.AbsoluteExpiresAt) || rotated.UserAgent != "Browser" {
+			t.Errorf("Rotation should keep absolute expiry and device metadata")
+		}
+	})
+
+	t.Run("Login discards the previous session", func(t *testing.T) {
+		previous, _ := sessionManager.CreateSession(user.ID, "", "")
+
+		jsonPayload, _ := json.Marshal(map[string]string{
+			"email":    "test@example.com",
+			"password": "password123",
+		})
+		req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(jsonPayload))
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: previous.ID})
+
+		rr := httptest.NewRecorder()
+		authHandler.Login(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if _, err := sessionManager.GetSession(previous.ID); err == nil {
+			t.Errorf("Session from before login should be discarded")
+		}
+	})
+}
+
 // Benchmark tests
 func BenchmarkPasswordHashing(b *testing.B) {
 	password := "testpassword123"
@@ -0,0 +1,136 @@
+// backend/tests/compression_test.go
+package tests
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/models"
+)
+
+func TestCompressionAndConditionalGET(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	uploadsPath := t.TempDir()
+	handler := newTestRouterWithConfig(t, database, sessionManager, func(cfg *config.Config) {
+		cfg.UploadsPath = uploadsPath
+	})
+	user, session := createTestUser(t, userRepo, sessionManager, "compression@test.com", true)
+
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	decodes := func(t *testing.T, body io.Reader) {
+		var doc map[string]interface{}
+		if err := json.NewDecoder(body).Decode(&doc); err != nil || doc["openapi"] == nil {
+			t.Errorf("Expected the decompressed OpenAPI document, got error %v", err)
+		}
+	}
+
+	t.Run("Brotli is preferred", func(t *testing.T) {
+		rr := get("/api/v1/openapi.json", map[string]string{"Accept-Encoding": "gzip, deflate, br"})
+		if rr.Header().Get("Content-Encoding") != "br" {
+			t.Fatalf("Expected brotli encoding, got %q", rr.Header().Get("Content-Encoding"))
+		}
+		if !slices.Contains(rr.Header().Values("Vary"), "Accept-Encoding") {
+			t.Errorf("Expected Vary: Accept-Encoding, got %q", rr.Header().Values("Vary"))
+		}
+		decodes(t, brotli.NewReader(rr.Body))
+	})
+
+	t.Run("Gzip when brotli is refused", func(t *testing.T) {
+		rr := get("/api/v1/openapi.json", map[string]string{"Accept-Encoding": "br;q=0, *"})
+		if rr.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Expected gzip encoding, got %q", rr.Header().Get("Content-Encoding"))
+		}
+		reader, err := gzip.NewReader(rr.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip body: %v", err)
+		}
+		decodes(t, reader)
+	})
+
+	t.Run("Identity without Accept-Encoding or for small bodies", func(t *testing.T) {
+		rr := get("/api/v1/openapi.json", nil)
+		if rr.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Expected no encoding, got %q", rr.Header().Get("Content-Encoding"))
+		}
+		decodes(t, rr.Body)
+
+		rr = get("/api/v1/no-such-route", map[string]string{"Accept-Encoding": "gzip"})
+		if rr.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Expected a small error body to stay uncompressed, got %q", rr.Header().Get("Content-Encoding"))
+		}
+	})
+
+	t.Run("Profiles answer 304 for a matching ETag", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+		rr := get(path, nil)
+		etag := rr.Header().Get("ETag")
+		if rr.Code != http.StatusOK || etag == "" {
+			t.Fatalf("Expected 200 with an ETag, got %d %q", rr.Code, etag)
+		}
+
+		rr = get(path, map[string]string{"If-None-Match": etag})
+		if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+			t.Errorf("Expected an empty 304, got %d: %s", rr.Code, rr.Body.String())
+		}
+		rr = get(path, map[string]string{"If-None-Match": "W/" + etag})
+		if rr.Code != http.StatusNotModified {
+			t.Errorf("Expected weak comparison to match, got %d", rr.Code)
+		}
+
+		if err := userRepo.UpdateProfile(user.ID, map[string]interface{}{"about_me": "Changed"}); err != nil {
+			t.Fatalf("Failed to update profile: %v", err)
+		}
+		rr = get(path, map[string]string{"If-None-Match": etag})
+		if rr.Code != http.StatusOK || rr.Header().Get("ETag") == etag {
+			t.Errorf("Expected 200 with a new ETag after a change, got %d %q", rr.Code, rr.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("Uploads support ETag and Last-Modified", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Join(uploadsPath, "posts"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(uploadsPath, "posts", "photo.png"), []byte("\x89PNG\r\n\x1a\nimage"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		rr := get("/uploads/posts/photo.png", nil)
+		etag, lastModified := rr.Header().Get("ETag"), rr.Header().Get("Last-Modified")
+		if rr.Code != http.StatusOK || etag == "" || lastModified == "" {
+			t.Fatalf("Expected 200 with ETag and Last-Modified, got %d %q %q", rr.Code, etag, lastModified)
+		}
+
+		if rr := get("/uploads/posts/photo.png", map[string]string{"If-None-Match": etag}); rr.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for a matching ETag, got %d", rr.Code)
+		}
+		if rr := get("/uploads/posts/photo.png", map[string]string{"If-Modified-Since": lastModified}); rr.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for If-Modified-Since, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// backend/tests/cors_test.go
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/models"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouterWithConfig(t, database, sessionManager, func(cfg *config.Config) {
+		cfg.AllowedOrigins = []string{"https://app.ripple.test", "https://*.staging.ripple.test"}
+		cfg.CORSAllowedMethods = []string{"GET", "POST", "PUT", "DELETE"}
+		cfg.CORSAllowedHeaders = []string{"Content-Type", "Authorization", "X-Request-ID"}
+		cfg.CORSExposedHeaders = []string{"X-Request-ID", "ETag"}
+		cfg.CORSMaxAge = 10 * time.Minute
+	})
+	_, session := createTestUser(t, userRepo, sessionManager, "cors@test.com", true)
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/auth/profile", nil)
+		req.Header.Set("Origin", origin)
+		if method == "OPTIONS" {
+			req.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Exact and wildcard origins are allowed", func(t *testing.T) {
+		for _, origin := range []string{"https://app.ripple.test", "https://pr-42.staging.ripple.test", "https://a.b.staging.ripple.test"} {
+			rr := request("GET", origin)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("%s: expected status 200, got %d", origin, rr.Code)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != origin {
+				t.Errorf("%s: expected the origin to be allowed, got %q", origin, got)
+			}
+			if rr.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Errorf("%s: expected credentials to be allowed", origin)
+			}
+			if got := rr.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID, ETag" {
+				t.Errorf("%s: unexpected exposed headers %q", origin, got)
+			}
+		}
+	})
+
+	t.Run("Other origins are not allowed", func(t *testing.T) {
+		origins := []string{
+			"https://evil.test",
+			"http://app.ripple.test",
+			"https://staging.ripple.test",
+			"https://evil.test/.staging.ripple.test",
+			"https://x.staging.ripple.test:8443",
+			"https://evilstaging.ripple.test",
+		}
+		for _, origin := range origins {
+			rr := request("GET", origin)
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("%s: expected no CORS access, got %q", origin, got)
+			}
+			if !slices.Contains(rr.Header().Values("Vary"), "Origin") {
+				t.Errorf("%s: expected Vary: Origin even when not allowed", origin)
+			}
+		}
+	})
+
+	t.Run("Preflight responses are configurable and cacheable", func(t *testing.T) {
+		rr := request("OPTIONS", "https://pr-42.staging.ripple.test")
+		if rr.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", rr.Code)
+		}
+		expected := map[string]string{
+			"Access-Control-Allow-Origin":  "https://pr-42.staging.ripple.test",
+			"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE",
+			"Access-Control-Allow-Headers": "Content-Type, Authorization, X-Request-ID",
+			"Access-Control-Max-Age":       "600",
+		}
+		for name, value := range expected {
+			if got := rr.Header().Get(name); got != value {
+				t.Errorf("Expected %s %q, got %q", name, value, got)
+			}
+		}
+		if vary := strings.Join(rr.Header().Values("Vary"), ", "); !strings.Contains(vary, "Access-Control-Request-Method") {
+			t.Errorf("Expected preflights to vary by the requested method, got %q", vary)
+		}
+
+		rr = request("OPTIONS", "https://evil.test")
+		if rr.Header().Get("Access-Control-Allow-Methods") != "" || rr.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected no CORS headers for an unknown origin, got %v", rr.Header())
+		}
+	})
+
+	t.Run("Wildcard origins pass the CSRF check", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/auth/email/resend", nil)
+		req.Header.Set("Origin", "https://pr-42.staging.ripple.test")
+		req.Header.Set("Sec-Fetch-Site", "cross-site")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code == http.StatusForbidden {
+			t.Errorf("Expected a trusted wildcard origin to pass the CSRF check, got %s", rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// backend/tests/csrf_test.go
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+)
+
+func TestCSRFProtection(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+
+	user, session := createTestUser(t, userRepo, sessionManager, "csrf@test.com", true)
+	token, _, _ := sessionManager.CreateAccessToken(user.ID, "bot", []string{auth.ScopePostsRead, auth.ScopePostsWrite}, time.Now().Add(time.Hour))
+
+	request := func(method, path string, headers map[string]string) *httptest.ResponseRecorder {
+		var body *strings.Reader
+		if method == "POST" {
+			body = strings.NewReader(`{"content":"hello","privacy_level":"public"}`)
+		} else {
+			body = strings.NewReader("")
+		}
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	blocked := func(rr *httptest.ResponseRecorder) bool {
+		if rr.Code != http.StatusForbidden {
+			return false
+		}
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		errorBody, _ := response["error"].(map[string]interface{})
+		return errorBody["code"] == constants.CodeCSRFCheckFailed
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		blocked bool
+	}{
+		{"Cross-site origin", "POST", map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site"}, true},
+		{"Cross-site without origin", "POST", map[string]string{"Sec-Fetch-Site": "cross-site"}, true},
+		{"Foreign origin in older browser", "POST", map[string]string{"Origin": "https://evil.example"}, true},
+		{"Same-site but untrusted origin", "POST", map[string]string{"Origin": "http://localhost:4000", "Sec-Fetch-Site": "same-site"}, true},
+		{"Trusted frontend origin", "POST", map[string]string{"Origin": "http://localhost:3000", "Sec-Fetch-Site": "same-site"}, false},
+		{"Same origin", "POST", map[string]string{"Origin": "http://example.com", "Sec-Fetch-Site": "same-origin"}, false},
+		{"Non-browser client", "POST", nil, false},
+		{"Personal access token", "POST", map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site", "Authorization": "Bearer " + token}, false},
+		{"Safe method", "GET", map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/api/posts"
+			if tt.method == "GET" {
+				path = "/api/posts/feed"
+			}
+			rr := request(tt.method, path, tt.headers)
+			if blocked(rr) != tt.blocked {
+				t.Errorf("Expected blocked=%v, got status %d: %s", tt.blocked, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// backend/tests/data_export_test.go
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/models"
+)
+
+func TestDataExport(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	exportRepo := models.NewDataExportRepository(database.DB)
+	notificationRepo := models.NewNotificationRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+
+	uploadsPath := t.TempDir()
+	exportsPath := t.TempDir()
+	exportHandler := handlers.NewDataExportHandler(exportRepo, notificationRepo, exportsPath, uploadsPath,
+		"http://localhost:8000", time.Hour)
+
+	user, session := createTestUser(t, userRepo, sessionManager, "export@test.com", true)
+	other, otherSession := createTestUser(t, userRepo, sessionManager, "other@test.com", true)
+
+	os.MkdirAll(filepath.Join(uploadsPath, "posts"), 0755)
+	os.WriteFile(filepath.Join(uploadsPath, "posts", "holiday.jpg"), []byte("holiday photo"), 0644)
+	database.DB.Exec(`INSERT INTO posts (user_id, content, image_path) VALUES (?, 'My holiday', '/uploads/posts/holiday.jpg')`, user.ID)
+	database.DB.Exec(`INSERT INTO messages (sender_id, receiver_id, content) VALUES (?, ?, 'Hello there')`, other.ID, user.ID)
+
+	serve := func(h http.HandlerFunc, method, target, cookie string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		rr := httptest.NewRecorder()
+		sessionManager.AuthMiddleware(h).ServeHTTP(rr, req)
+		return rr
+	}
+
+	var exportID string
+
+	t.Run("Request builds archive and notifies user", func(t *testing.T) {
+		rr := serve(exportHandler.RequestExport, "POST", "/api/auth/account/export", session.ID)
+		if rr.Code != http.StatusAccepted {
+			t.Fatalf("Expected status 202, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// The archive is built in the background
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			exports, _ := exportRepo.GetUserExports(user.ID)
+			if len(exports) == 1 && exports[0].Status == models.DataExportReady {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Export was not built in time")
+			}
+			exportHandler.ProcessPendingExports()
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		notifications, _ := notificationRepo.GetUserNotifications(user.ID, 10, 0)
+		if len(notifications) != 1 || notifications[0].Type != models.NotificationDataExportReady {
+			t.Fatalf("Expected a data export notification, got %+v", notifications)
+		}
+		match := regexp.MustCompile(`/export/download\?id=(\d+)$`).FindStringSubmatch(notifications[0].Message)
+		if match == nil {
+			t.Fatalf("Expected a download link without a token in notification: %s", notifications[0].Message)
+		}
+		exportID = match[1]
+	})
+
+	downloadPath := func() string { return "/api/v1/auth/account/export/download?id=" + exportID }
+
+	t.Run("Only the owner can download", func(t *testing.T) {
+		if rr := serve(exportHandler.DownloadExport, "GET", downloadPath(), otherSession.ID); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for another user, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Archive contains data and uploads", func(t *testing.T) {
+		rr := serve(exportHandler.DownloadExport, "GET", downloadPath(), session.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("Content-Type") != "application/zip" {
+			t.Errorf("Expected zip content type, got %s", rr.Header().Get("Content-Type"))
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		if err != nil {
+			t.Fatalf("Invalid zip: %v", err)
+		}
+		files := make(map[string]string)
+		for _, f := range archive.File {
+			rc, _ := f.Open()
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(content)
+		}
+
+		for _, name := range []string{"profile.json", "posts.json", "comments.json", "likes.json", "follows.json",
+			"group_memberships.json", "event_responses.json", "messages.json", "group_messages.json", "notifications.json"} {
+			if _, ok := files[name]; !ok {
+				t.Errorf("Expected %s in archive", name)
+			}
+		}
+
+		var profile map[string]interface{}
+		json.Unmarshal([]byte(files["profile.json"]), &profile)
+		if profile["email"] != user.Email {
+			t.Errorf("Expected profile email %s, got %v", user.Email, profile["email"])
+		}
+		if strings.Contains(files["profile.json"], "password") {
+			t.Errorf("Password hash must not be exported")
+		}
+		if !strings.Contains(files["posts.json"], "My holiday") || !strings.Contains(files["messages.json"], "Hello there") {
+			t.Errorf("Expected posts and messages in archive")
+		}
+		if files["uploads/posts/holiday.jpg"] != "holiday photo" {
+			t.Errorf("Expected uploaded file in archive")
+		}
+	})
+
+	t.Run("Expired link is rejected and archive removed", func(t *testing.T) {
+		database.DB.Exec(`UPDATE data_exports SET expires_at = ? WHERE user_id = ?`, time.Now().Add(-time.Minute), user.ID)
+
+		if rr := serve(exportHandler.DownloadExport, "GET", downloadPath(), session.ID); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for expired link, got %d", rr.Code)
+		}
+
+		if err := exportHandler.CleanupExpiredExports(); err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		if entries, _ := os.ReadDir(exportsPath); len(entries) != 0 {
+			t.Errorf("Expected archive to be removed, found %d files", len(entries))
+		}
+	})
+}
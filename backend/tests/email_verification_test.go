@@ -0,0 +1,118 @@
+// backend/tests/email_verification_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+func TestEmailVerification(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	followRepo := models.NewFollowRepository(database.DB)
+	postRepo := models.NewPostRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+
+	mailDir := t.TempDir()
+	mailer, err := mail.NewFileMailer("test@ripple.local", mailDir)
+	if err != nil {
+		t.Fatalf("Failed to create mailer: %v", err)
+	}
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, models.NewTwoFactorRepository(database.DB),
+		models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mailer, "http://localhost:3000")
+
+	postJSON := func(handler http.Handler, payload any, cookie string) *httptest.ResponseRecorder {
+		jsonPayload, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/api/auth/email", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Stands in for any route that requires a verified email (posting, messaging)
+	restricted := sessionManager.AuthMiddleware(sessionManager.RequireVerifiedEmail(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	resend := sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.ResendVerificationEmail))
+	verify := http.HandlerFunc(authHandler.VerifyEmail)
+
+	rr := postJSON(http.HandlerFunc(authHandler.Register), map[string]string{
+		"email":         "verify@test.com",
+		"password":      "password123",
+		"first_name":    "Verify",
+		"last_name":     "Me",
+		"date_of_birth": "1990-01-01",
+	}, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	session := rr.Result().Cookies()[0].Value
+
+	mails := readMails(t, mailDir)
+	if len(mails) != 1 || !strings.Contains(mails[0], "http://localhost:3000/verify-email?token=") {
+		t.Fatalf("Expected one verification email, got %v", mails)
+	}
+	firstToken := resetTokenPattern.FindStringSubmatch(mails[0])[1]
+
+	t.Run("Unverified user is restricted", func(t *testing.T) {
+		rr := postJSON(restricted, nil, session)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", rr.Code)
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		if code := response["error"].(map[string]interface{})["code"]; code != constants.CodeEmailNotVerified {
+			t.Errorf("Expected error code %s, got %v", constants.CodeEmailNotVerified, code)
+		}
+	})
+
+	t.Run("Resend invalidates earlier link", func(t *testing.T) {
+		rr := postJSON(resend, nil, session)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		if rr := postJSON(verify, map[string]string{"token": firstToken}, ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected old token to be rejected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Verify with latest link", func(t *testing.T) {
+		mails := readMails(t, mailDir)
+		if len(mails) != 2 {
+			t.Fatalf("Expected two emails, got %d", len(mails))
+		}
+		token := resetTokenPattern.FindStringSubmatch(mails[1])[1]
+
+		if rr := postJSON(verify, map[string]string{"token": token}, ""); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr := postJSON(verify, map[string]string{"token": token}, ""); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected used token to be rejected, got %d", rr.Code)
+		}
+
+		if rr := postJSON(restricted, nil, session); rr.Code != http.StatusOK {
+			t.Errorf("Expected verified user to pass, got %d", rr.Code)
+		}
+		if rr := postJSON(resend, nil, session); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected resend to be rejected once verified, got %d", rr.Code)
+		}
+	})
+}
@@ -66,7 +66,8 @@ func TestEventSystem(t *testing.T) {
 		}
 		jsonPayload, _ := json.Marshal(payload)
 		
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/groups/%d/events", group.ID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/%d", group.ID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(group.ID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -93,7 +94,8 @@ func TestEventSystem(t *testing.T) {
 	})
 
 	t.Run("Get event", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/%d", createdEventID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/get/%d", createdEventID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -117,7 +119,8 @@ func TestEventSystem(t *testing.T) {
 	})
 
 	t.Run("Get group events", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/events", group.ID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/group/%d", group.ID), nil)
+		req.SetPathValue("id", fmt.Sprint(group.ID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
 		rr := httptest.NewRecorder()
@@ -140,7 +143,8 @@ func TestEventSystem(t *testing.T) {
 		payload := map[string]string{"response": "going"}
 		jsonPayload, _ := json.Marshal(payload)
 		
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/%d/respond", createdEventID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/respond/%d", createdEventID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
@@ -156,7 +160,8 @@ func TestEventSystem(t *testing.T) {
 		payload := map[string]string{"response": "not_going"}
 		jsonPayload, _ := json.Marshal(payload)
 		
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/%d/respond", createdEventID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/respond/%d", createdEventID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -172,7 +177,8 @@ func TestEventSystem(t *testing.T) {
 		payload := map[string]string{"response": "going"}
 		jsonPayload, _ := json.Marshal(payload)
 		
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/%d/respond", createdEventID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/respond/%d", createdEventID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -185,7 +191,8 @@ func TestEventSystem(t *testing.T) {
 	})
 
 	t.Run("Get event responses", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/%d/responses", createdEventID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/responses/%d", createdEventID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -216,7 +223,8 @@ func TestEventSystem(t *testing.T) {
 		user3, session3 := createTestUser(t, userRepo, sessionManager, "charlie@test.com", true)
 		_ = user3 // Avoid unused variable warning
 
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/%d", createdEventID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/events/get/%d", createdEventID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdEventID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session3.ID})
 
 		rr := httptest.NewRecorder()
@@ -238,7 +246,8 @@ func TestEventSystem(t *testing.T) {
 		}
 		jsonPayload, _ := json.Marshal(payload)
 		
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/groups/%d/events", group.ID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/events/%d", group.ID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(group.ID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -71,7 +71,7 @@ func TestGroupChatFunctionality(t *testing.T) {
 	}
 
 	// Create sessions for users
-	session1, err := sessionManager.CreateSession(createdUser1.ID)
+	session1, err := sessionManager.CreateSession(createdUser1.ID, "test-agent", "127.0.0.1")
 	if err != nil {
 		t.Fatalf("Failed to create session for user1: %v", err)
 	}
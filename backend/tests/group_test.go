@@ -68,6 +68,7 @@ func TestGroupSystem(t *testing.T) {
 
 	t.Run("Get group", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d", createdGroupID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -131,7 +132,8 @@ func TestGroupSystem(t *testing.T) {
 	})
 
 	t.Run("Get pending join requests (creator)", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/join-requests", createdGroupID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/requests", createdGroupID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -253,6 +255,7 @@ func TestGroupSystem(t *testing.T) {
 
 	t.Run("Get group members", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/members", createdGroupID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -278,6 +281,7 @@ func TestGroupSystem(t *testing.T) {
 		jsonPayload, _ := json.Marshal(payload)
 		
 		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/groups/%d/posts", createdGroupID), bytes.NewBuffer(jsonPayload))
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -291,6 +295,7 @@ func TestGroupSystem(t *testing.T) {
 
 	t.Run("Get group posts", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/posts", createdGroupID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
 		rr := httptest.NewRecorder()
@@ -315,6 +320,7 @@ func TestGroupSystem(t *testing.T) {
 		_ = user4 // Avoid unused variable warning
 
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/groups/%d/posts", createdGroupID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdGroupID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session4.ID})
 
 		rr := httptest.NewRecorder()
@@ -0,0 +1,101 @@
+// backend/tests/health_test.go
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/db"
+	"ripple/pkg/handlers"
+	"ripple/pkg/websocket"
+)
+
+func TestHealthChecks(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	migrationVersion, err := db.LatestMigrationVersion("../pkg/db/migrations/sqlite")
+	if err != nil {
+		t.Fatalf("Failed to read migration version: %v", err)
+	}
+
+	probe := func(h http.HandlerFunc) (int, handlers.HealthResponse) {
+		rr := httptest.NewRecorder()
+		h(rr, httptest.NewRequest("GET", "/readyz", nil))
+		var response handlers.HealthResponse
+		json.NewDecoder(rr.Body).Decode(&response)
+		return rr.Code, response
+	}
+
+	newHub := func(t *testing.T) *websocket.Hub {
+		hub := websocket.NewHub(database.DB)
+		go hub.Run()
+		t.Cleanup(hub.Stop)
+		return hub
+	}
+
+	t.Run("Probes are mounted on the router", func(t *testing.T) {
+		handler := newTestRouter(t, database, auth.NewSessionManager(database.DB))
+		for _, path := range []string{"/healthz", "/readyz"} {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+			if rr.Code != http.StatusOK {
+				t.Errorf("Expected status 200 for %s, got %d: %s", path, rr.Code, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("Ready when every check passes", func(t *testing.T) {
+		health := handlers.NewHealthHandler(database, migrationVersion, t.TempDir(), newHub(t))
+		status, response := probe(health.Readyz)
+		if status != http.StatusOK || response.Status != "ready" {
+			t.Fatalf("Expected ready, got %d %+v", status, response)
+		}
+		for _, check := range []string{"database", "migrations", "uploads", "websocket"} {
+			if response.Checks[check] != "ok" {
+				t.Errorf("Expected %s check to pass, got %q", check, response.Checks[check])
+			}
+		}
+	})
+
+	t.Run("Unexpected migration version", func(t *testing.T) {
+		health := handlers.NewHealthHandler(database, migrationVersion+1, t.TempDir(), newHub(t))
+		status, response := probe(health.Readyz)
+		if status != http.StatusServiceUnavailable || response.Checks["migrations"] != "failing" {
+			t.Errorf("Expected migrations check to fail, got %d %+v", status, response)
+		}
+	})
+
+	t.Run("Uploads directory is not writable", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing")
+		health := handlers.NewHealthHandler(database, migrationVersion, missing, newHub(t))
+		status, response := probe(health.Readyz)
+		if status != http.StatusServiceUnavailable || response.Checks["uploads"] != "failing" {
+			t.Errorf("Expected uploads check to fail, got %d %+v", status, response)
+		}
+	})
+
+	t.Run("Hub loop is not running", func(t *testing.T) {
+		health := handlers.NewHealthHandler(database, migrationVersion, t.TempDir(), websocket.NewHub(database.DB))
+		status, response := probe(health.Readyz)
+		if status != http.StatusServiceUnavailable || response.Checks["websocket"] != "failing" {
+			t.Errorf("Expected websocket check to fail, got %d %+v", status, response)
+		}
+	})
+
+	t.Run("Readiness fails during shutdown while liveness holds", func(t *testing.T) {
+		health := handlers.NewHealthHandler(database, migrationVersion, t.TempDir(), newHub(t))
+		health.SetShuttingDown()
+
+		if status, response := probe(health.Readyz); status != http.StatusServiceUnavailable || response.Status != "shutting_down" {
+			t.Errorf("Expected readiness to fail during shutdown, got %d %+v", status, response)
+		}
+		if status, _ := probe(health.Healthz); status != http.StatusOK {
+			t.Errorf("Expected liveness to pass during shutdown, got %d", status)
+		}
+	})
+}
@@ -141,6 +141,7 @@ func TestPhase4Integration(t *testing.T) {
 		postJsonPayload, _ := json.Marshal(postPayload)
 		
 		req, _ = http.NewRequest("POST", fmt.Sprintf("/api/groups/%d/posts", groupID), bytes.NewBuffer(postJsonPayload))
+		req.SetPathValue("id", fmt.Sprint(groupID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
@@ -160,7 +161,8 @@ func TestPhase4Integration(t *testing.T) {
 		}
 		eventJsonPayload, _ := json.Marshal(eventPayload)
 		
-		req, _ = http.NewRequest("POST", fmt.Sprintf("/api/groups/%d/events", groupID), bytes.NewBuffer(eventJsonPayload))
+		req, _ = http.NewRequest("POST", fmt.Sprintf("/api/events/%d", groupID), bytes.NewBuffer(eventJsonPayload))
+		req.SetPathValue("id", fmt.Sprint(groupID))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
@@ -189,7 +191,8 @@ func TestPhase4Integration(t *testing.T) {
 			responsePayload := map[string]string{"response": resp.response}
 			responseJsonPayload, _ := json.Marshal(responsePayload)
 			
-			req, _ = http.NewRequest("POST", fmt.Sprintf("/api/events/%d/respond", eventID), bytes.NewBuffer(responseJsonPayload))
+			req, _ = http.NewRequest("POST", fmt.Sprintf("/api/events/respond/%d", eventID), bytes.NewBuffer(responseJsonPayload))
+			req.SetPathValue("id", fmt.Sprint(eventID))
 			req.Header.Set("Content-Type", "application/json")
 			req.AddCookie(&http.Cookie{Name: "session_id", Value: resp.userSession})
 
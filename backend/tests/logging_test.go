@@ -0,0 +1,187 @@
+// backend/tests/logging_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/logging"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+// syncBuffer collects log output written from several goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// records returns the JSON log records with the given message
+func (b *syncBuffer) records(t *testing.T, message string) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Log line is not JSON: %q", line)
+		}
+		if record["msg"] == message {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestRequestLogging(t *testing.T) {
+	previous := slog.Default()
+	defer func() {
+		slog.SetDefault(previous)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	output := &syncBuffer{}
+	logging.SetupWriter(output, "debug", "json")
+
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+	user, session := createTestUser(t, userRepo, sessionManager, "logging@test.com", true)
+
+	t.Run("Generates a request ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/auth/profile", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if len(rr.Header().Get("X-Request-ID")) != 32 {
+			t.Errorf("Expected a generated request ID, got %q", rr.Header().Get("X-Request-ID"))
+		}
+	})
+
+	t.Run("Propagates a valid request ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/auth/profile", nil)
+		req.Header.Set("X-Request-ID", "upstream-123")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Header().Get("X-Request-ID") != "upstream-123" {
+			t.Errorf("Expected request ID to be propagated, got %q", rr.Header().Get("X-Request-ID"))
+		}
+	})
+
+	t.Run("Replaces a malformed request ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/auth/profile", nil)
+		req.Header.Set("X-Request-ID", "bad id\nforged=1")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if id := rr.Header().Get("X-Request-ID"); id == "" || strings.ContainsAny(id, " \n=") {
+			t.Errorf("Expected malformed request ID to be replaced, got %q", id)
+		}
+	})
+
+	t.Run("Access log records the request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d", user.ID), nil)
+		req.Header.Set("X-Request-ID", "access-log-test")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var record map[string]interface{}
+		for _, r := range output.records(t, "request") {
+			if r["request_id"] == "access-log-test" {
+				record = r
+			}
+		}
+		if record == nil {
+			t.Fatalf("No access log record for the request")
+		}
+		if record["method"] != "GET" || record["route"] != "/api/v1/users/{id}" {
+			t.Errorf("Unexpected method or route: %v", record)
+		}
+		if record["status"] != float64(rr.Code) {
+			t.Errorf("Expected status %d, got %v", rr.Code, record["status"])
+		}
+		if record["user_id"] != float64(user.ID) {
+			t.Errorf("Expected user_id %d, got %v", user.ID, record["user_id"])
+		}
+		if _, ok := record["duration_ms"].(float64); !ok {
+			t.Errorf("Expected latency in the access log, got %v", record)
+		}
+	})
+
+	t.Run("Session cookies never reach the log", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/auth/logout", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if strings.Contains(output.String(), session.ID) {
+			t.Errorf("Session ID found in log output")
+		}
+	})
+
+	t.Run("Sensitive attributes are redacted", func(t *testing.T) {
+		slog.Info("redaction check", "session_id", "secret-session", "new_password", "hunter2", "user_id", 7)
+
+		records := output.records(t, "redaction check")
+		if len(records) != 1 {
+			t.Fatalf("Expected one record, got %d", len(records))
+		}
+		if records[0]["session_id"] != logging.Redacted || records[0]["new_password"] != logging.Redacted {
+			t.Errorf("Expected secrets to be redacted, got %v", records[0])
+		}
+		if records[0]["user_id"] != float64(7) {
+			t.Errorf("Expected other attributes to be kept, got %v", records[0])
+		}
+		if strings.Contains(output.String(), "hunter2") {
+			t.Errorf("Password found in log output")
+		}
+	})
+
+	t.Run("Mail bodies are only logged at debug level", func(t *testing.T) {
+		defer logging.SetupWriter(output, "debug", "json")
+		message := &mail.Message{To: "logging@test.com", Subject: "Reset your password", Body: "Open /reset-password?token=mail-secret"}
+
+		infoOutput := &syncBuffer{}
+		logging.SetupWriter(infoOutput, "info", "json")
+		mail.NewLogMailer("test@ripple.local").Send(message)
+		if !strings.Contains(infoOutput.String(), "Reset your password") {
+			t.Errorf("Expected the recipient and subject to be logged, got %s", infoOutput.String())
+		}
+		if strings.Contains(infoOutput.String(), "mail-secret") {
+			t.Errorf("Mail body found in info log output")
+		}
+
+		debugOutput := &syncBuffer{}
+		logging.SetupWriter(debugOutput, "debug", "json")
+		mail.NewLogMailer("test@ripple.local").Send(message)
+		if !strings.Contains(debugOutput.String(), "mail-secret") {
+			t.Errorf("Expected the mail body at debug level, got %s", debugOutput.String())
+		}
+	})
+}
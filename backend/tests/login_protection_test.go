@@ -0,0 +1,141 @@
+// backend/tests/login_protection_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/constants"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+func TestLoginBruteForceProtection(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+
+	mailDir := t.TempDir()
+	mailer, err := mail.NewFileMailer("test@ripple.local", mailDir)
+	if err != nil {
+		t.Fatalf("Failed to create mailer: %v", err)
+	}
+
+	// A fresh handler and repository per request, like after a server restart
+	newHandler := func() *handlers.AuthHandler {
+		return handlers.NewAuthHandler(userRepo, models.NewFollowRepository(database.DB), models.NewPostRepository(database.DB),
+			models.NewTwoFactorRepository(database.DB), models.NewEmailVerificationRepository(database.DB),
+			models.NewLoginAttemptRepository(database.DB), sessionManager, mailer, "http://localhost:3000")
+	}
+
+	login := func(email, password, remoteAddr string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonPayload, _ := json.Marshal(map[string]string{"email": email, "password": password})
+		req, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		newHandler().Login(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	errorCode := func(response map[string]interface{}) interface{} {
+		if apiErr, ok := response["error"].(map[string]interface{}); ok {
+			return apiErr["code"]
+		}
+		return nil
+	}
+
+	expireLockout := func(key string) {
+		_, err := database.DB.Exec(`UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?`, time.Now().Add(-time.Second), key)
+		if err != nil {
+			t.Fatalf("Failed to expire lockout: %v", err)
+		}
+	}
+
+	createTestUser(t, userRepo, sessionManager, "locked@test.com", true)
+
+	t.Run("Account locks after repeated failures", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			if rr, _ := login("locked@test.com", "wrongpassword", "10.0.0.1:1234"); rr.Code != http.StatusUnauthorized {
+				t.Fatalf("Attempt %d: expected status 401, got %d", i+1, rr.Code)
+			}
+		}
+
+		rr, response := login("locked@test.com", "wrongpassword", "10.0.0.1:1234")
+		if rr.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status 429, got %d", rr.Code)
+		}
+		if errorCode(response) != constants.CodeAccountLocked {
+			t.Errorf("Expected error code %s, got %v", constants.CodeAccountLocked, errorCode(response))
+		}
+		if rr.Header().Get("Retry-After") == "" {
+			t.Errorf("Expected Retry-After header")
+		}
+
+		// Even the right password is refused while locked, from any IP
+		if rr, _ := login("locked@test.com", "password123", "10.0.0.2:1234"); rr.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected locked account to reject correct password, got %d", rr.Code)
+		}
+
+		mails := readMails(t, mailDir)
+		if len(mails) != 1 || !strings.Contains(mails[0], "temporarily locked") {
+			t.Errorf("Expected one lockout email, got %v", mails)
+		}
+	})
+
+	t.Run("Lockout grows after it expires", func(t *testing.T) {
+		expireLockout("account:locked@test.com")
+
+		if rr, _ := login("locked@test.com", "wrongpassword", "10.0.0.1:1234"); rr.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected immediate relock, got %d", rr.Code)
+		}
+
+		var lockedUntil time.Time
+		database.DB.QueryRow(`SELECT locked_until FROM login_attempts WHERE attempt_key = ?`, "account:locked@test.com").Scan(&lockedUntil)
+		if remaining := time.Until(lockedUntil); remaining < 9*time.Minute {
+			t.Errorf("Expected second lockout to be about 10 minutes, got %v", remaining)
+		}
+	})
+
+	t.Run("Successful login resets the counter", func(t *testing.T) {
+		expireLockout("account:locked@test.com")
+
+		if rr, _ := login("locked@test.com", "password123", "10.0.0.1:1234"); rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr, _ := login("locked@test.com", "wrongpassword", "10.0.0.1:1234"); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected counter to start over, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Client IP locks across accounts", func(t *testing.T) {
+		var rr *httptest.ResponseRecorder
+		var response map[string]interface{}
+		for i := 0; i < 20; i++ {
+			rr, response = login(fmt.Sprintf("unknown%d@test.com", i), "wrongpassword", "10.0.0.3:1234")
+		}
+		if rr.Code != http.StatusTooManyRequests || errorCode(response) != constants.CodeTooManyLoginAttempts {
+			t.Fatalf("Expected IP lockout, got %d %v", rr.Code, errorCode(response))
+		}
+
+		if rr, _ := login("other@test.com", "password123", "10.0.0.3:1234"); rr.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected locked IP to be refused, got %d", rr.Code)
+		}
+		if rr, _ := login("other@test.com", "password123", "10.0.0.4:1234"); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected other IPs to be unaffected, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+// backend/tests/metrics_test.go
+package tests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/metrics"
+	"ripple/pkg/models"
+	"ripple/pkg/websocket"
+)
+
+func TestMetrics(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+	user, session := createTestUser(t, userRepo, sessionManager, "metrics@test.com", true)
+
+	scrape := func(remoteAddr, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Scrapes require the allowlist or token", func(t *testing.T) {
+		if rr := scrape("203.0.113.7:4000", ""); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for unknown client, got %d", rr.Code)
+		}
+		if rr := scrape("203.0.113.7:4000", "wrong-token"); rr.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for wrong token, got %d", rr.Code)
+		}
+		if rr := scrape("203.0.113.7:4000", "test-metrics-token"); rr.Code != http.StatusOK {
+			t.Errorf("Expected status 200 with token, got %d", rr.Code)
+		}
+
+		rr := scrape("127.0.0.1:4000", "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for allowlisted client, got %d", rr.Code)
+		}
+		if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+			t.Errorf("Unexpected content type %q", rr.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("Counts HTTP requests by route", func(t *testing.T) {
+		before := metrics.HTTPRequests.Value("/api/v1/users/{id}", "GET", "200")
+		durationsBefore := metrics.HTTPRequestDuration.Count("/api/v1/users/{id}", "GET", "200")
+
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d", user.ID), nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+
+		if got := metrics.HTTPRequests.Value("/api/v1/users/{id}", "GET", "200"); got != before+1 {
+			t.Errorf("Expected request counter %v, got %v", before+1, got)
+		}
+		if got := metrics.HTTPRequestDuration.Count("/api/v1/users/{id}", "GET", "200"); got != durationsBefore+1 {
+			t.Errorf("Expected one latency observation, got %d", got-durationsBefore)
+		}
+
+		body := scrape("127.0.0.1:4000", "").Body.String()
+		if !strings.Contains(body, `ripple_http_requests_total{route="/api/v1/users/{id}",method="GET",status="200"}`) {
+			t.Errorf("Request counter missing from scrape output")
+		}
+		if !strings.Contains(body, `ripple_http_request_duration_seconds_bucket{route="/api/v1/users/{id}",method="GET",status="200",le="+Inf"}`) {
+			t.Errorf("Latency histogram missing from scrape output")
+		}
+	})
+
+	t.Run("Unmatched paths share one label", func(t *testing.T) {
+		before := metrics.HTTPRequests.Value("unmatched", "GET", "404")
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/no-such-page", nil))
+		if got := metrics.HTTPRequests.Value("unmatched", "GET", "404"); got != before+1 {
+			t.Errorf("Expected unmatched request to be counted once, got %v", got-before)
+		}
+	})
+
+	t.Run("Records database query durations", func(t *testing.T) {
+		before := metrics.DBQueryDuration.Count("select", "users")
+		if _, err := userRepo.GetUserByID(user.ID); err != nil {
+			t.Fatalf("Failed to load user: %v", err)
+		}
+		if metrics.DBQueryDuration.Count("select", "users") <= before {
+			t.Errorf("Expected a query duration for users")
+		}
+	})
+
+	t.Run("Counts notifications by type", func(t *testing.T) {
+		notificationRepo := models.NewNotificationRepository(database.DB)
+		before := metrics.NotificationsCreated.Value(models.NotificationEventReminder)
+
+		if err := notificationRepo.CreateEventReminderNotification(user.ID, 1, "Meetup", 2); err != nil {
+			t.Fatalf("Failed to create notification: %v", err)
+		}
+		if err := notificationRepo.BulkCreateNotifications([]int{user.ID, user.ID}, models.NotificationEventReminder, "Soon", "Soon", nil, nil); err != nil {
+			t.Fatalf("Failed to create notifications: %v", err)
+		}
+
+		if got := metrics.NotificationsCreated.Value(models.NotificationEventReminder); got != before+3 {
+			t.Errorf("Expected 3 more event reminders, got %v", got-before)
+		}
+	})
+
+	t.Run("Exposes hub gauges", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+		hub := websocket.NewHub(database.DB)
+		hub.RegisterMetrics(registry)
+
+		var out strings.Builder
+		registry.Write(&out)
+		for _, line := range []string{"ripple_websocket_clients 0", "ripple_websocket_group_subscriptions 0"} {
+			if !strings.Contains(out.String(), line) {
+				t.Errorf("Expected %q in output:\n%s", line, out.String())
+			}
+		}
+	})
+}
+
+func TestMetricsRegistry(t *testing.T) {
+	registry := metrics.NewRegistry()
+	histogram := registry.NewHistogram("test_duration_seconds", "Test durations.", []float64{0.1, 1}, "kind")
+	counter := registry.NewCounter("test_total", "Test counter.", "name")
+
+	histogram.Observe(0.05, "a")
+	histogram.Observe(0.5, "a")
+	histogram.Observe(5, "a")
+	counter.Inc("quote\"d")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.RemoteAddr = "10.1.2.3:9999"
+	rr := httptest.NewRecorder()
+	handlers.NewMetricsHandler(registry, []string{"10.0.0.0/8"}, "").ServeMetrics(rr, req)
+	body, _ := io.ReadAll(rr.Body)
+
+	expected := []string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{kind="a",le="0.1"} 1`,
+		`test_duration_seconds_bucket{kind="a",le="1"} 2`,
+		`test_duration_seconds_bucket{kind="a",le="+Inf"} 3`,
+		`test_duration_seconds_sum{kind="a"} 5.55`,
+		`test_duration_seconds_count{kind="a"} 3`,
+		"# TYPE test_total counter",
+		`test_total{name="quote\"d"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, body)
+		}
+	}
+}
@@ -84,13 +84,16 @@ func createTestUser(t *testing.T, userRepo *models.UserRepository, sessionManage
 	if err != nil {
 		t.Fatalf("Failed to create test user: %v", err)
 	}
+	if err := userRepo.MarkEmailVerified(user.ID); err != nil {
+		t.Fatalf("Failed to verify test user email: %v", err)
+	}
 
 	// Update user's privacy setting
 	updates := map[string]interface{}{"is_public": isPublic}
 	userRepo.UpdateProfile(user.ID, updates)
 	user.IsPublic = isPublic
 
-	session, err := sessionManager.CreateSession(user.ID)
+	session, err := sessionManager.CreateSession(user.ID, "test-agent", "127.0.0.1")
 	if err != nil {
 		t.Fatalf("Failed to create test session: %v", err)
 	}
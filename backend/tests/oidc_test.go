@@ -0,0 +1,386 @@
+// backend/tests/oidc_test.go
+package tests
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/models"
+	"ripple/pkg/oidc"
+)
+
+const (
+	mockClientID     = "ripple-test"
+	mockClientSecret = "test-secret"
+	mockRedirectURL  = "http://localhost:8000/api/auth/oidc/callback"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect provider: it logs in whoever is
+// set in nextClaims and checks PKCE and client credentials at the token endpoint.
+type mockOIDCProvider struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey
+	signingKey *rsa.PrivateKey
+
+	mu         sync.Mutex
+	nextClaims map[string]any
+	codes      map[string]mockAuthorization
+}
+
+type mockAuthorization struct {
+	claims        map[string]any
+	nonce         string
+	codeChallenge string
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	mock := &mockOIDCProvider{key: key, signingKey: key, codes: make(map[string]mockAuthorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 mock.server.URL,
+			"authorization_endpoint": mock.server.URL + "/authorize",
+			"token_endpoint":         mock.server.URL + "/token",
+			"jwks_uri":               mock.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("client_id") != mockClientID || query.Get("code_challenge_method") != "S256" {
+			http.Error(w, "bad authorization request", http.StatusBadRequest)
+			return
+		}
+
+		code, _, _ := auth.GenerateToken()
+		mock.mu.Lock()
+		mock.codes[code] = mockAuthorization{
+			claims:        mock.nextClaims,
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+		}
+		mock.mu.Unlock()
+
+		redirect := query.Get("redirect_uri") + "?code=" + url.QueryEscape(code) + "&state=" + url.QueryEscape(query.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		r.ParseForm()
+
+		mock.mu.Lock()
+		authorization, ok := mock.codes[r.PostForm.Get("code")]
+		delete(mock.codes, r.PostForm.Get("code"))
+		mock.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if clientID != mockClientID || secret != mockClientSecret || !ok ||
+			oidc.CodeChallengeS256(r.PostForm.Get("code_verifier")) != authorization.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := map[string]any{
+			"iss":   mock.server.URL,
+			"aud":   mockClientID,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": authorization.nonce,
+		}
+		for k, v := range authorization.claims {
+			claims[k] = v
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "unused",
+			"token_type":   "Bearer",
+			"id_token":     mock.signIDToken(t, claims),
+		})
+	})
+
+	mock.server = httptest.NewServer(mux)
+	return mock
+}
+
+func (m *mockOIDCProvider) signIDToken(t *testing.T, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.signingKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign ID token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	mock := newMockOIDCProvider(t)
+	defer mock.server.Close()
+
+	userRepo := models.NewUserRepository(database.DB)
+	identityRepo := models.NewIdentityRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		IssuerURL:    mock.server.URL,
+		ClientID:     mockClientID,
+		ClientSecret: mockClientSecret,
+		RedirectURL:  mockRedirectURL,
+	})
+	oidcHandler := handlers.NewOIDCHandler(provider, userRepo, identityRepo, models.NewTwoFactorRepository(database.DB),
+		sessionManager, "http://localhost:3000")
+
+	noRedirects := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	// authorize runs the browser side of the flow and returns the callback URL
+	// from the provider with the state cookie set by Login
+	authorize := func(claims map[string]any) (string, *http.Cookie) {
+		mock.mu.Lock()
+		mock.nextClaims = claims
+		mock.mu.Unlock()
+
+		rr := httptest.NewRecorder()
+		oidcHandler.Login(rr, httptest.NewRequest("GET", "/api/auth/oidc/login", nil))
+		if rr.Code != http.StatusFound {
+			t.Fatalf("Expected redirect to provider, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var stateCookie *http.Cookie
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "oidc_state" {
+				stateCookie = cookie
+			}
+		}
+		if stateCookie == nil {
+			t.Fatalf("Expected a state cookie")
+		}
+
+		resp, err := noRedirects.Get(rr.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Authorization request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("Provider rejected authorization request: %d", resp.StatusCode)
+		}
+		return resp.Header.Get("Location"), stateCookie
+	}
+
+	callback := func(callbackURL string, stateCookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", callbackURL, nil)
+		if stateCookie != nil {
+			req.AddCookie(&http.Cookie{Name: stateCookie.Name, Value: stateCookie.Value})
+		}
+		rr := httptest.NewRecorder()
+		oidcHandler.Callback(rr, req)
+		return rr
+	}
+
+	login := func(claims map[string]any) *httptest.ResponseRecorder {
+		return callback(authorize(claims))
+	}
+
+	sessionCookie := func(rr *httptest.ResponseRecorder) *http.Cookie {
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == auth.SessionCookieName {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	countUsers := func() int {
+		var count int
+		database.DB.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count)
+		return count
+	}
+
+	t.Run("First login creates account", func(t *testing.T) {
+		rr := login(map[string]any{
+			"sub":            "subject-1",
+			"email":          "Alice@Example.com",
+			"email_verified": true,
+			"given_name":     "Alice",
+			"family_name":    "Liddell",
+			"birthdate":      "1995-05-04",
+		})
+		if rr.Code != http.StatusFound || rr.Header().Get("Location") != "http://localhost:3000/" {
+			t.Fatalf("Expected redirect to frontend, got %d %s", rr.Code, rr.Header().Get("Location"))
+		}
+
+		cookie := sessionCookie(rr)
+		if cookie == nil {
+			t.Fatalf("Expected session cookie")
+		}
+		session, err := sessionManager.GetSession(cookie.Value)
+		if err != nil {
+			t.Fatalf("Expected valid session: %v", err)
+		}
+
+		user, err := userRepo.GetUserByID(session.UserID)
+		if err != nil {
+			t.Fatalf("Failed to get user: %v", err)
+		}
+		if user.Email != "alice@example.com" || user.FirstName != "Alice" || user.LastName != "Liddell" {
+			t.Errorf("Expected profile to be pre-filled, got %s %s %s", user.Email, user.FirstName, user.LastName)
+		}
+		if user.DateOfBirth.Format("2006-01-02") != "1995-05-04" {
+			t.Errorf("Expected birthdate from provider, got %v", user.DateOfBirth)
+		}
+		if !user.IsEmailVerified() {
+			t.Errorf("Expected provider-verified email to be marked verified")
+		}
+	})
+
+	t.Run("Second login reuses account", func(t *testing.T) {
+		before := countUsers()
+		rr := login(map[string]any{"sub": "subject-1", "email": "alice@example.com", "email_verified": true})
+		if rr.Code != http.StatusFound || sessionCookie(rr) == nil {
+			t.Fatalf("Expected successful login, got %d %s", rr.Code, rr.Header().Get("Location"))
+		}
+		if countUsers() != before {
+			t.Errorf("Expected no new account")
+		}
+	})
+
+	t.Run("Verified email links existing account", func(t *testing.T) {
+		existing, _ := createTestUser(t, userRepo, sessionManager, "bob@example.com", true)
+
+		rr := login(map[string]any{"sub": "subject-2", "email": "bob@example.com", "email_verified": "true", "name": "Bob B"})
+		if rr.Code != http.StatusFound || sessionCookie(rr) == nil {
+			t.Fatalf("Expected successful login, got %d %s", rr.Code, rr.Header().Get("Location"))
+		}
+
+		userID, _ := identityRepo.GetUserIDByIdentity("mock", "subject-2")
+		if userID != existing.ID {
+			t.Errorf("Expected identity linked to user %d, got %d", existing.ID, userID)
+		}
+	})
+
+	t.Run("Unverified email does not take over account", func(t *testing.T) {
+		createTestUser(t, userRepo, sessionManager, "carol@example.com", true)
+
+		rr := login(map[string]any{"sub": "subject-3", "email": "carol@example.com", "email_verified": false})
+		if !strings.Contains(rr.Header().Get("Location"), "error=oidc_account_exists") {
+			t.Errorf("Expected account_exists error, got %s", rr.Header().Get("Location"))
+		}
+		if sessionCookie(rr) != nil {
+			t.Errorf("No session should be created")
+		}
+	})
+
+	t.Run("Unverified local account is not linked", func(t *testing.T) {
+		// Someone registered the victim's email with a password of their own
+		hashedPassword, _ := auth.HashPassword("attacker-password")
+		squatter, err := userRepo.CreateUser(&models.CreateUserRequest{
+			Email:       "dave@example.com",
+			FirstName:   "Dave",
+			LastName:    "Squatter",
+			DateOfBirth: "1990-01-01",
+		}, hashedPassword)
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+
+		rr := login(map[string]any{"sub": "subject-4", "email": "dave@example.com", "email_verified": true})
+		if !strings.Contains(rr.Header().Get("Location"), "error=oidc_account_exists") {
+			t.Errorf("Expected account_exists error, got %s", rr.Header().Get("Location"))
+		}
+		if sessionCookie(rr) != nil {
+			t.Errorf("No session should be created")
+		}
+
+		if userID, _ := identityRepo.GetUserIDByIdentity("mock", "subject-4"); userID != 0 {
+			t.Errorf("Expected identity not to be linked, got user %d", userID)
+		}
+		user, _ := userRepo.GetUserByID(squatter.ID)
+		if user.IsEmailVerified() {
+			t.Errorf("Expected the squatted account to stay unverified")
+		}
+	})
+
+	t.Run("State cannot be replayed", func(t *testing.T) {
+		callbackURL, stateCookie := authorize(map[string]any{"sub": "subject-1", "email": "alice@example.com"})
+		if rr := callback(callbackURL, stateCookie); sessionCookie(rr) == nil {
+			t.Fatalf("Expected first callback to succeed, got %s", rr.Header().Get("Location"))
+		}
+
+		rr := callback(callbackURL, stateCookie)
+		if !strings.Contains(rr.Header().Get("Location"), "error=oidc_invalid_state") {
+			t.Errorf("Expected invalid state error, got %s", rr.Header().Get("Location"))
+		}
+	})
+
+	t.Run("State is bound to the browser that started the login", func(t *testing.T) {
+		// An attacker's own login, finished in the victim's browser, carries
+		// no state cookie or the cookie of the victim's own login
+		callbackURL, stateCookie := authorize(map[string]any{"sub": "subject-1", "email": "alice@example.com"})
+		if !stateCookie.HttpOnly || stateCookie.SameSite != http.SameSiteLaxMode || stateCookie.Path != "/api/auth/oidc/callback" || stateCookie.MaxAge <= 0 {
+			t.Errorf("Expected a short-lived HttpOnly, SameSite=Lax cookie scoped to the callback, got %+v", stateCookie)
+		}
+		_, victimCookie := authorize(map[string]any{"sub": "subject-2", "email": "bob@example.com"})
+
+		for name, cookie := range map[string]*http.Cookie{"missing": nil, "mismatched": victimCookie} {
+			rr := callback(callbackURL, cookie)
+			if !strings.Contains(rr.Header().Get("Location"), "error=oidc_invalid_state") || sessionCookie(rr) != nil {
+				t.Errorf("Expected a %s state cookie to be refused, got %s", name, rr.Header().Get("Location"))
+			}
+		}
+
+		rr := callback(callbackURL, stateCookie)
+		if sessionCookie(rr) == nil {
+			t.Fatalf("Expected the matching state cookie to be accepted, got %s", rr.Header().Get("Location"))
+		}
+		for _, cookie := range rr.Result().Cookies() {
+			if cookie.Name == "oidc_state" && cookie.MaxAge >= 0 {
+				t.Errorf("Expected the state cookie to be cleared, got %+v", cookie)
+			}
+		}
+	})
+
+	t.Run("Forged ID token is rejected", func(t *testing.T) {
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		mock.signingKey = otherKey
+		defer func() { mock.signingKey = mock.key }()
+
+		rr := login(map[string]any{"sub": "subject-1", "email": "alice@example.com"})
+		if !strings.Contains(rr.Header().Get("Location"), "error=oidc_failed") {
+			t.Errorf("Expected login failure, got %s", rr.Header().Get("Location"))
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// backend/tests/openapi_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/models"
+)
+
+func TestOpenAPI(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	_, session := createTestUser(t, userRepo, sessionManager, "openapi@test.com", true)
+
+	t.Run("Document describes every route", func(t *testing.T) {
+		handler := newTestRouter(t, database, sessionManager)
+		req, _ := http.NewRequest("GET", "/api/v1/openapi.json", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+
+		var doc struct {
+			OpenAPI    string                                `json:"openapi"`
+			Paths      map[string]map[string]json.RawMessage `json:"paths"`
+			Components struct {
+				Schemas map[string]struct {
+					Properties map[string]interface{} `json:"properties"`
+					Required   []string               `json:"required"`
+				} `json:"schemas"`
+			} `json:"components"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("Failed to decode document: %v", err)
+		}
+		if doc.OpenAPI != "3.0.3" {
+			t.Errorf("Expected OpenAPI 3.0.3, got %q", doc.OpenAPI)
+		}
+
+		operations := map[string]string{
+			"/api/v1/auth/register":                "post",
+			"/api/v1/groups/{id}":                  "put",
+			"/api/v1/groups/posts/{id}/comments":   "post",
+			"/api/v1/admin/content/{type}/{id}":    "delete",
+			"/api/v1/chat/messages/private/{id}":   "get",
+			"/api/v1/auth/sessions/revoke/{id}":    "delete",
+			"/api/v1/auth/account/export/download": "get",
+			"/api/v1/openapi.json":                 "get",
+		}
+		for path, method := range operations {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("Expected %s %s to be documented", method, path)
+			}
+		}
+
+		if _, ok := doc.Components.Schemas["APIResponse"]; !ok {
+			t.Error("Expected the APIResponse envelope in the components")
+		}
+		comment, ok := doc.Components.Schemas["CreateCommentRequest"]
+		if !ok || comment.Properties["postId"] == nil {
+			t.Errorf("Expected CreateCommentRequest with a postId property, got %+v", comment)
+		}
+	})
+
+	request := func(handler http.Handler, path string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	t.Run("Request validation", func(t *testing.T) {
+		handler := newTestRouterWithConfig(t, database, sessionManager, func(cfg *config.Config) {
+			cfg.ValidateRequests = true
+		})
+
+		rr, response := request(handler, "/api/v1/posts", `{"content": 42, "privacy_level": "friends"}`)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d: %s", rr.Code, rr.Body.String())
+		}
+		errorBody, _ := response["error"].(map[string]interface{})
+		if errorBody["code"] != "VALIDATION_ERROR" {
+			t.Fatalf("Expected a validation error, got %s", rr.Body.String())
+		}
+		details, _ := errorBody["details"].(map[string]interface{})
+		if details["content"] != "must be a string" || details["privacy_level"] == nil {
+			t.Errorf("Expected errors for content and privacy_level, got %s", rr.Body.String())
+		}
+
+		rr, response = request(handler, "/api/v1/groups", `{"title": `)
+		if errorBody, _ := response["error"].(map[string]interface{}); rr.Code != http.StatusBadRequest || errorBody["message"] != "Invalid JSON format" {
+			t.Errorf("Expected 400 for malformed JSON, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		rr, _ = request(handler, "/api/v1/posts", `{"content": "Valid post", "privacy_level": "public"}`)
+		if rr.Code != http.StatusCreated {
+			t.Errorf("Expected a valid body to reach the handler, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		oversized := `{"content": "` + strings.Repeat("a", 2<<20) + `"}`
+		if rr, _ = request(handler, "/api/v1/posts", oversized); rr.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 for an oversized body, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Validation is off by default", func(t *testing.T) {
+		handler := newTestRouter(t, database, sessionManager)
+		rr, response := request(handler, "/api/v1/posts", `{"content": 42}`)
+		if errorBody, _ := response["error"].(map[string]interface{}); rr.Code != http.StatusBadRequest || errorBody["message"] != "Invalid JSON format" {
+			t.Errorf("Expected the handler's own decoding error, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
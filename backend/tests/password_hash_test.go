@@ -0,0 +1,147 @@
+// backend/tests/password_hash_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHashFormats(t *testing.T) {
+	t.Run("New hashes use argon2id", func(t *testing.T) {
+		hash, err := auth.HashPassword("password123")
+		if err != nil {
+			t.Fatalf("Failed to hash password: %v", err)
+		}
+		if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$") {
+			t.Errorf("Expected argon2id hash with default parameters, got %s", hash)
+		}
+		if err := auth.CheckPassword("password123", hash); err != nil {
+			t.Errorf("Expected password to verify: %v", err)
+		}
+		if err := auth.CheckPassword("password124", hash); err == nil {
+			t.Errorf("Wrong password should not verify")
+		}
+		if auth.NeedsRehash(hash) {
+			t.Errorf("Current hash should not need a rehash")
+		}
+	})
+
+	t.Run("Legacy bcrypt hashes still verify", func(t *testing.T) {
+		legacy, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+		if err := auth.CheckPassword("password123", string(legacy)); err != nil {
+			t.Errorf("Expected bcrypt hash to verify: %v", err)
+		}
+		if err := auth.CheckPassword("password124", string(legacy)); err == nil {
+			t.Errorf("Wrong password should not verify")
+		}
+		if !auth.NeedsRehash(string(legacy)) {
+			t.Errorf("bcrypt hash should need a rehash")
+		}
+	})
+
+	t.Run("Weaker parameters need a rehash", func(t *testing.T) {
+		auth.SetArgon2Params(auth.Argon2Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1})
+		weak, _ := auth.HashPassword("password123")
+		auth.SetArgon2Params(auth.DefaultArgon2Params)
+
+		if err := auth.CheckPassword("password123", weak); err != nil {
+			t.Errorf("Hash with old parameters should still verify: %v", err)
+		}
+		if !auth.NeedsRehash(weak) {
+			t.Errorf("Hash with weaker parameters should need a rehash")
+		}
+	})
+
+	t.Run("Malformed hashes are rejected", func(t *testing.T) {
+		if err := auth.CheckPassword("password123", "$argon2id$v=19$m=65536$broken"); err == nil {
+			t.Errorf("Malformed hash should not verify")
+		}
+		if err := auth.CheckPassword("password123", "$argon2id$v=19$m=65536,t=3,p=0$c2FsdHNhbHRzYWx0c2FsdA$a2V5"); err == nil {
+			t.Errorf("Hash with zero parallelism should not verify")
+		}
+	})
+
+	t.Run("Out of range parameters are refused", func(t *testing.T) {
+		invalid := [][3]int64{
+			{64 * 1024, 0, 2},   // no iterations
+			{64 * 1024, 3, 0},   // no lanes
+			{64 * 1024, 3, 256}, // would wrap to 0 lanes
+			{15, 3, 2},          // less than 8 KiB per lane
+			{1 << 32, 3, 2},     // would wrap to 0 KiB
+		}
+		for _, params := range invalid {
+			if _, err := auth.NewArgon2Params(params[0], params[1], params[2]); err == nil {
+				t.Errorf("Expected m=%d,t=%d,p=%d to be refused", params[0], params[1], params[2])
+			}
+		}
+
+		params, err := auth.NewArgon2Params(16, 1, 2)
+		if err != nil || params.SaltLength == 0 || params.KeyLength == 0 {
+			t.Fatalf("Expected the minimum parameters to be accepted, got %+v: %v", params, err)
+		}
+
+		if err := auth.SetArgon2Params(auth.Argon2Params{Memory: 64 * 1024, Iterations: 3}); err == nil {
+			t.Errorf("Expected SetArgon2Params to refuse zero parallelism")
+		}
+		if _, err := auth.HashPassword("password123"); err != nil {
+			t.Errorf("Expected hashing to keep working after a refused change: %v", err)
+		}
+	})
+}
+
+func TestPasswordHashUpgradeOnLogin(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user, err := userRepo.CreateUser(&models.CreateUserRequest{
+		Email:       "legacy@test.com",
+		FirstName:   "Legacy",
+		LastName:    "User",
+		DateOfBirth: "1990-01-01",
+	}, string(legacy))
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	login := func(password string) int {
+		body, _ := json.Marshal(map[string]string{"email": "legacy@test.com", "password": password})
+		req := httptest.NewRequest("POST", "/api/auth/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := login("wrongpassword"); code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401, got %d", code)
+	}
+	if stored, _ := userRepo.GetUserByID(user.ID); stored.PasswordHash != string(legacy) {
+		t.Errorf("Failed login must not change the hash")
+	}
+
+	if code := login("password123"); code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+
+	stored, _ := userRepo.GetUserByID(user.ID)
+	if !strings.HasPrefix(stored.PasswordHash, "$argon2id$") {
+		t.Errorf("Expected hash to be upgraded to argon2id, got %s", stored.PasswordHash)
+	}
+	if code := login("password123"); code != http.StatusOK {
+		t.Errorf("Expected login with upgraded hash to succeed, got %d", code)
+	}
+}
@@ -0,0 +1,181 @@
+// backend/tests/password_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+var resetTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+// readMails returns the contents of every email written by a FileMailer, oldest first
+func readMails(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatalf("Failed to list mail files: %v", err)
+	}
+	sort.Strings(files)
+
+	var mails []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read mail file: %v", err)
+		}
+		mails = append(mails, string(content))
+	}
+	return mails
+}
+
+func TestPasswordManagement(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	resetRepo := models.NewPasswordResetRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+
+	mailer, err := mail.NewFileMailer("test@ripple.local", t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create mailer: %v", err)
+	}
+	passwordHandler := handlers.NewPasswordHandler(userRepo, resetRepo, sessionManager, mailer, "http://localhost:3000")
+
+	user, session := createTestUser(t, userRepo, sessionManager, "password@test.com", true)
+	otherSession, _ := sessionManager.CreateSession(user.ID, "Other Device", "10.0.0.9")
+
+	postJSON := func(handler http.Handler, path string, payload any, cookie string) *httptest.ResponseRecorder {
+		jsonPayload, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	changePassword := sessionManager.AuthMiddleware(http.HandlerFunc(passwordHandler.ChangePassword))
+
+	t.Run("Change password with wrong current password", func(t *testing.T) {
+		rr := postJSON(changePassword, "/api/auth/password/change", map[string]string{
+			"current_password": "wrongpassword",
+			"new_password":     "newpassword123",
+		}, session.ID)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Change password", func(t *testing.T) {
+		rr := postJSON(changePassword, "/api/auth/password/change", map[string]string{
+			"current_password": "password123",
+			"new_password":     "newpassword123",
+		}, session.ID)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		updated, _ := userRepo.GetUserByID(user.ID)
+		if err := auth.CheckPassword("newpassword123", updated.PasswordHash); err != nil {
+			t.Errorf("New password should be accepted")
+		}
+		if _, err := sessionManager.GetSession(otherSession.ID); err == nil {
+			t.Errorf("Other sessions should be revoked after a password change")
+		}
+
+		cookies := rr.Result().Cookies()
+		if len(cookies) == 0 {
+			t.Fatalf("Expected a rotated session cookie")
+		}
+		if _, err := sessionManager.GetSession(cookies[0].Value); err != nil {
+			t.Errorf("Current session should continue under its new ID: %v", err)
+		}
+	})
+
+	forgotPassword := http.HandlerFunc(passwordHandler.ForgotPassword)
+	resetPassword := http.HandlerFunc(passwordHandler.ResetPassword)
+
+	t.Run("Forgot password for unknown email does not send mail", func(t *testing.T) {
+		before := len(readMails(t, mailer.Dir()))
+		rr := postJSON(forgotPassword, "/api/auth/password/forgot", map[string]string{
+			"email": "nobody@test.com",
+		}, "")
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rr.Code)
+		}
+		if len(readMails(t, mailer.Dir())) != before {
+			t.Errorf("No email should be sent for unknown accounts")
+		}
+	})
+
+	t.Run("Reset password with emailed token", func(t *testing.T) {
+		resetSession, _ := sessionManager.CreateSession(user.ID, "", "")
+
+		rr := postJSON(forgotPassword, "/api/auth/password/forgot", map[string]string{
+			"email": "password@test.com",
+		}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+
+		mails := readMails(t, mailer.Dir())
+		match := resetTokenPattern.FindStringSubmatch(mails[len(mails)-1])
+		if match == nil {
+			t.Fatalf("Reset email does not contain a token: %s", mails[len(mails)-1])
+		}
+		token := match[1]
+
+		rr = postJSON(resetPassword, "/api/auth/password/reset", map[string]string{
+			"token":        token,
+			"new_password": "resetpassword123",
+		}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		updated, _ := userRepo.GetUserByID(user.ID)
+		if err := auth.CheckPassword("resetpassword123", updated.PasswordHash); err != nil {
+			t.Errorf("Reset password should be accepted")
+		}
+		if _, err := sessionManager.GetSession(resetSession.ID); err == nil {
+			t.Errorf("All sessions should be revoked after a password reset")
+		}
+
+		// Tokens are single-use
+		rr = postJSON(resetPassword, "/api/auth/password/reset", map[string]string{
+			"token":        token,
+			"new_password": "anotherpassword123",
+		}, "")
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected reused token to be rejected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Reset password with invalid token", func(t *testing.T) {
+		rr := postJSON(resetPassword, "/api/auth/password/reset", map[string]string{
+			"token":        "not-a-real-token",
+			"new_password": "resetpassword123",
+		}, "")
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rr.Code)
+		}
+	})
+}
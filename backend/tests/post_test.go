@@ -125,6 +125,7 @@ func TestPostSystem(t *testing.T) {
 
 	t.Run("Get single post", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/posts/%d", createdPostID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdPostID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
 		rr := httptest.NewRecorder()
@@ -145,11 +146,12 @@ func TestPostSystem(t *testing.T) {
 
 	t.Run("Create comment", func(t *testing.T) {
 		payload := map[string]interface{}{
+			"postId":  createdPostID,
 			"content": "This is a comment",
 		}
 		jsonPayload, _ := json.Marshal(payload)
 
-		req, _ := http.NewRequest("POST", fmt.Sprintf("/api/posts/%d/comments", createdPostID), bytes.NewBuffer(jsonPayload))
+		req, _ := http.NewRequest("POST", "/api/posts/comments/create", bytes.NewBuffer(jsonPayload))
 		req.Header.Set("Content-Type", "application/json")
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session2.ID})
 
@@ -162,7 +164,8 @@ func TestPostSystem(t *testing.T) {
 	})
 
 	t.Run("Get comments", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/posts/%d/comments", createdPostID), nil)
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/api/posts/comments/%d", createdPostID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdPostID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -182,7 +185,8 @@ func TestPostSystem(t *testing.T) {
 	})
 
 	t.Run("Delete post", func(t *testing.T) {
-		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/posts/%d", createdPostID), nil)
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/api/posts/delete/%d", createdPostID), nil)
+		req.SetPathValue("id", fmt.Sprint(createdPostID))
 		req.AddCookie(&http.Cookie{Name: "session_id", Value: session1.ID})
 
 		rr := httptest.NewRecorder()
@@ -0,0 +1,127 @@
+// backend/tests/rate_limit_test.go
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/handlers"
+	"ripple/pkg/models"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := handlers.NewRateLimiter("test", config.RateLimit{Requests: 3, Period: 3 * time.Second})
+	limiter.SetClock(func() time.Time { return now })
+
+	t.Run("Bucket allows a burst up to the limit", func(t *testing.T) {
+		for i := 2; i >= 0; i-- {
+			result := limiter.Allow("a")
+			if !result.Allowed || result.Remaining != i {
+				t.Fatalf("Expected request to be allowed with %d remaining, got %+v", i, result)
+			}
+		}
+
+		result := limiter.Allow("a")
+		if result.Allowed {
+			t.Fatalf("Expected fourth request to be limited")
+		}
+		if result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+			t.Errorf("Expected retry after 1s and reset after 3s, got %v and %v", result.RetryAfter, result.Reset)
+		}
+
+		if !limiter.Allow("b").Allowed {
+			t.Errorf("Expected other keys to have their own bucket")
+		}
+	})
+
+	t.Run("Tokens refill over time", func(t *testing.T) {
+		now = now.Add(time.Second)
+		if !limiter.Allow("a").Allowed {
+			t.Errorf("Expected one token after one second")
+		}
+		if limiter.Allow("a").Allowed {
+			t.Errorf("Expected only one token after one second")
+		}
+	})
+
+	t.Run("Idle buckets are evicted", func(t *testing.T) {
+		if limiter.Len() != 2 {
+			t.Fatalf("Expected 2 buckets, got %d", limiter.Len())
+		}
+		now = now.Add(10 * time.Second)
+		limiter.Allow("c")
+		if limiter.Len() != 1 {
+			t.Errorf("Expected idle buckets to be evicted, got %d", limiter.Len())
+		}
+	})
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	_, session := createTestUser(t, userRepo, sessionManager, "limited@test.com", true)
+	_, otherSession := createTestUser(t, userRepo, sessionManager, "other@test.com", true)
+
+	limiter := handlers.NewRateLimiter("posts", config.RateLimit{Requests: 2, Period: time.Minute})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	authenticated := sessionManager.AuthMiddleware(handlers.RateLimitMiddleware(limiter)(ok))
+	anonymous := handlers.RateLimitMiddleware(limiter)(ok)
+
+	serve := func(h http.Handler, cookie string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/posts", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Limits each user", func(t *testing.T) {
+		rr := serve(authenticated, session.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if rr.Header().Get("X-RateLimit-Limit") != "2" || rr.Header().Get("X-RateLimit-Remaining") != "1" {
+			t.Errorf("Unexpected rate limit headers: %v", rr.Header())
+		}
+		serve(authenticated, session.ID)
+
+		rr = serve(authenticated, session.ID)
+		if rr.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status 429, got %d", rr.Code)
+		}
+		if rr.Header().Get("Retry-After") != "30" || rr.Header().Get("X-RateLimit-Remaining") != "0" {
+			t.Errorf("Unexpected headers on limited response: %v", rr.Header())
+		}
+
+		if rr := serve(authenticated, otherSession.ID); rr.Code != http.StatusOK {
+			t.Errorf("Expected another user to be unaffected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Limits anonymous clients by IP", func(t *testing.T) {
+		serve(anonymous, "")
+		serve(anonymous, "")
+		if rr := serve(anonymous, ""); rr.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected status 429 for the client IP, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Disabled limiter passes everything", func(t *testing.T) {
+		disabled := handlers.RateLimitMiddleware(handlers.NewRateLimiter("off", config.RateLimit{}))(ok)
+		for i := 0; i < 5; i++ {
+			if rr := serve(disabled, ""); rr.Code != http.StatusOK || rr.Header().Get("X-RateLimit-Limit") != "" {
+				t.Fatalf("Expected disabled limiter to pass requests without headers")
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// backend/tests/routing_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/models"
+)
+
+func TestRouting(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	handler := newTestRouter(t, database, sessionManager)
+	_, session := createTestUser(t, userRepo, sessionManager, "routing@test.com", true)
+
+	request := func(method, path string, payload any) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	errorMessage := func(response map[string]interface{}) interface{} {
+		if errorBody, ok := response["error"].(map[string]interface{}); ok {
+			return errorBody["message"]
+		}
+		return nil
+	}
+
+	t.Run("Unsupported methods get 405 with Allow", func(t *testing.T) {
+		rr, response := request("DELETE", "/api/auth/profile", nil)
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("Expected status 405, got %d", rr.Code)
+		}
+		if allow := rr.Header().Get("Allow"); allow != "GET, HEAD" {
+			t.Errorf("Expected Allow header 'GET, HEAD', got %q", allow)
+		}
+		if errorMessage(response) != "Method not allowed" {
+			t.Errorf("Expected JSON error body, got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("Allow lists every method of a path", func(t *testing.T) {
+		rr, _ := request("DELETE", "/api/groups/1", nil)
+		if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "GET, HEAD, PUT" {
+			t.Errorf("Expected 405 allowing GET, HEAD and PUT, got %d %q", rr.Code, rr.Header().Get("Allow"))
+		}
+	})
+
+	t.Run("Unknown API routes get a JSON 404", func(t *testing.T) {
+		rr, response := request("GET", "/api/no-such-route", nil)
+		if rr.Code != http.StatusNotFound || errorMessage(response) != "Not found" {
+			t.Errorf("Expected JSON 404, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("Invalid IDs are rejected the same way everywhere", func(t *testing.T) {
+		cases := map[string]string{
+			"/api/users/abc":                "Invalid user ID",
+			"/api/groups/0":                 "Invalid group ID",
+			"/api/posts/-3":                 "Invalid post ID",
+			"/api/events/get/1.5":           "Invalid event ID",
+			"/api/follow/followers/me":      "Invalid user ID",
+			"/api/groups/posts/x/comments":  "Invalid post ID",
+			"/api/chat/messages/group/none": "Invalid group ID",
+		}
+		for path, message := range cases {
+			rr, response := request("GET", path, nil)
+			if rr.Code != http.StatusBadRequest || errorMessage(response) != message {
+				t.Errorf("%s: expected 400 %q, got %d: %s", path, message, rr.Code, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("Nested group routes", func(t *testing.T) {
+		rr, response := request("POST", "/api/groups", map[string]string{"title": "Routing Group", "description": "Nested routes"})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		groupID := int(response["data"].(map[string]interface{})["group"].(map[string]interface{})["id"].(float64))
+
+		rr, response = request("POST", fmt.Sprintf("/api/groups/%d/posts", groupID), map[string]string{"content": "Hello group"})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 creating a post, got %d: %s", rr.Code, rr.Body.String())
+		}
+		postID := int(response["data"].(map[string]interface{})["post"].(map[string]interface{})["ID"].(float64))
+
+		rr, response = request("GET", fmt.Sprintf("/api/groups/%d/posts", groupID), nil)
+		if rr.Code != http.StatusOK || response["data"].(map[string]interface{})["count"] != float64(1) {
+			t.Errorf("Expected one group post, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		path := fmt.Sprintf("/api/groups/posts/%d/comments", postID)
+		if rr, _ := request("POST", path, map[string]string{"content": "Nice"}); rr.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 commenting, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr, _ := request("GET", path, nil); rr.Code != http.StatusOK {
+			t.Errorf("Expected status 200 listing comments, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		if rr, _ := request("GET", fmt.Sprintf("/api/groups/%d/members", groupID), nil); rr.Code != http.StatusOK {
+			t.Errorf("Expected status 200 listing members, got %d", rr.Code)
+		}
+	})
+}
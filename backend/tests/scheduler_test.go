@@ -0,0 +1,139 @@
+// backend/tests/scheduler_test.go
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ripple/pkg/scheduler"
+)
+
+func TestScheduler(t *testing.T) {
+	waitFor := func(t *testing.T, condition func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Condition not met in time")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	statusOf := func(s *scheduler.Scheduler, name string) scheduler.JobStatus {
+		for _, status := range s.Status() {
+			if status.Name == name {
+				return status
+			}
+		}
+		return scheduler.JobStatus{}
+	}
+
+	t.Run("Jobs run periodically and record their status", func(t *testing.T) {
+		s := scheduler.New()
+		var runs atomic.Int32
+		s.Register("counter", 20*time.Millisecond, func() error {
+			runs.Add(1)
+			return nil
+		})
+		s.Register("broken", 20*time.Millisecond, func() error {
+			return errors.New("database is locked")
+		})
+		s.Register("panicking", 20*time.Millisecond, func() error {
+			panic("nil map")
+		})
+		s.Start()
+		defer s.Stop(context.Background())
+
+		waitFor(t, func() bool { return runs.Load() >= 3 })
+		waitFor(t, func() bool { return statusOf(s, "panicking").Failures >= 1 })
+
+		counter := statusOf(s, "counter")
+		if counter.LastStartedAt == nil || counter.LastFinishedAt == nil || counter.NextRunAt == nil {
+			t.Errorf("Expected run times to be recorded, got %+v", counter)
+		}
+		broken := statusOf(s, "broken")
+		if broken.Failures == 0 || broken.LastError != "database is locked" {
+			t.Errorf("Expected failure to be recorded, got %+v", broken)
+		}
+		if statuses := s.Status(); len(statuses) != 3 || statuses[0].Name != "broken" {
+			t.Errorf("Expected statuses sorted by name, got %+v", statuses)
+		}
+	})
+
+	t.Run("Runs never overlap", func(t *testing.T) {
+		s := scheduler.New()
+		s.SetJitter(0)
+		release := make(chan struct{})
+		var running, maxRunning atomic.Int32
+		s.Register("slow", 10*time.Millisecond, func() error {
+			if n := running.Add(1); n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			<-release
+			running.Add(-1)
+			return nil
+		})
+		s.Start()
+
+		waitFor(t, func() bool { return statusOf(s, "slow").Skipped >= 2 })
+		if s.RunNow("slow") {
+			t.Errorf("Expected manual run to be refused while the job is running")
+		}
+		close(release)
+
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop failed: %v", err)
+		}
+		if maxRunning.Load() != 1 {
+			t.Errorf("Expected at most one concurrent run, got %d", maxRunning.Load())
+		}
+	})
+
+	t.Run("Stop waits for running jobs until the deadline", func(t *testing.T) {
+		s := scheduler.New()
+		s.SetJitter(0)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s.Register("stuck", time.Hour, func() error {
+			close(started)
+			<-release
+			return nil
+		})
+		s.Start()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := s.Stop(ctx); err == nil {
+			t.Errorf("Expected Stop to report the running job")
+		}
+
+		close(release)
+		if err := s.Stop(context.Background()); err != nil {
+			t.Errorf("Expected Stop to succeed once the job finished: %v", err)
+		}
+		if s.RunNow("stuck") {
+			t.Errorf("Expected no runs after Stop")
+		}
+	})
+
+	t.Run("Registration is validated", func(t *testing.T) {
+		s := scheduler.New()
+		if err := s.Register("zero", 0, func() error { return nil }); err == nil {
+			t.Errorf("Expected zero interval to be rejected")
+		}
+		s.Register("job", time.Minute, func() error { return nil })
+		if err := s.Register("job", time.Minute, func() error { return nil }); err == nil {
+			t.Errorf("Expected duplicate name to be rejected")
+		}
+		s.Start()
+		defer s.Stop(context.Background())
+		if err := s.Register("late", time.Minute, func() error { return nil }); err == nil {
+			t.Errorf("Expected registration after Start to be rejected")
+		}
+	})
+}
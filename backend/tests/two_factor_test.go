@@ -0,0 +1,210 @@
+// backend/tests/two_factor_test.go
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/handlers"
+	"ripple/pkg/mail"
+	"ripple/pkg/models"
+)
+
+func TestTOTPAlgorithm(t *testing.T) {
+	// RFC 6238 appendix B test vectors (SHA1), truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := auth.GenerateTOTPCode(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("Failed to generate code: %v", err)
+		}
+		if code != expected {
+			t.Errorf("At %d expected %s, got %s", unix, expected, code)
+		}
+	}
+
+	now := time.Now()
+	code, _ := auth.GenerateTOTPCode(secret, now.Add(-30*time.Second))
+	if _, ok := auth.ValidateTOTP(secret, code, now); !ok {
+		t.Errorf("Code from the previous step should be accepted")
+	}
+	code, _ = auth.GenerateTOTPCode(secret, now.Add(-2*time.Minute))
+	if _, ok := auth.ValidateTOTP(secret, code, now); ok {
+		t.Errorf("Code from two minutes ago should be rejected")
+	}
+
+	uri := auth.TOTPURI(secret, "Ripple", "user@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Ripple:user@example.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("Unexpected otpauth URI: %s", uri)
+	}
+}
+
+func TestTwoFactorAuthentication(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	followRepo := models.NewFollowRepository(database.DB)
+	postRepo := models.NewPostRepository(database.DB)
+	twoFactorRepo := models.NewTwoFactorRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	authHandler := handlers.NewAuthHandler(userRepo, followRepo, postRepo, twoFactorRepo, models.NewEmailVerificationRepository(database.DB), models.NewLoginAttemptRepository(database.DB), sessionManager, mail.NewLogMailer("test@ripple.local"), "http://localhost:3000")
+
+	_, session := createTestUser(t, userRepo, sessionManager, "twofactor@test.com", true)
+	currentSession := session.ID
+
+	postJSON := func(handler http.Handler, payload any, cookie string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonPayload, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/api/auth/2fa", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: cookie})
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	login := func() (*httptest.ResponseRecorder, map[string]interface{}) {
+		return postJSON(http.HandlerFunc(authHandler.Login), map[string]string{
+			"email":    "twofactor@test.com",
+			"password": "password123",
+		}, "")
+	}
+
+	var secret string
+	var recoveryCodes []interface{}
+
+	t.Run("Setup returns secret and otpauth URI", func(t *testing.T) {
+		rr, response := postJSON(sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.SetupTwoFactor)), nil, currentSession)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		data := response["data"].(map[string]interface{})
+		secret = data["secret"].(string)
+		if !strings.HasPrefix(data["otpauth_uri"].(string), "otpauth://totp/") {
+			t.Errorf("Expected otpauth URI, got %v", data["otpauth_uri"])
+		}
+
+		// Not enabled until confirmed
+		if rr, _ := login(); rr.Header().Get("Set-Cookie") == "" {
+			t.Errorf("Login should not require 2FA before confirmation")
+		}
+	})
+
+	t.Run("Confirm with wrong code", func(t *testing.T) {
+		rr, _ := postJSON(sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.ConfirmTwoFactor)),
+			map[string]string{"code": "000000"}, currentSession)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Confirm returns recovery codes", func(t *testing.T) {
+		code, _ := auth.GenerateTOTPCode(secret, time.Now().Add(-30*time.Second))
+		rr, response := postJSON(sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.ConfirmTwoFactor)),
+			map[string]string{"code": code}, currentSession)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		recoveryCodes = response["data"].(map[string]interface{})["recovery_codes"].([]interface{})
+		if len(recoveryCodes) != 10 {
+			t.Errorf("Expected 10 recovery codes, got %d", len(recoveryCodes))
+		}
+
+		cookies := rr.Result().Cookies()
+		if len(cookies) == 0 {
+			t.Fatalf("Expected session to be rotated after enabling 2FA")
+		}
+		currentSession = cookies[0].Value
+	})
+
+	t.Run("Login requires second factor", func(t *testing.T) {
+		rr, response := login()
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rr.Code)
+		}
+		if rr.Header().Get("Set-Cookie") != "" {
+			t.Errorf("No session should be created before the second factor")
+		}
+
+		data := response["data"].(map[string]interface{})
+		if data["two_factor_required"] != true || data["challenge_token"] == "" {
+			t.Fatalf("Expected two-factor challenge, got %v", data)
+		}
+		challenge := data["challenge_token"].(string)
+
+		verify := http.HandlerFunc(authHandler.VerifyTwoFactor)
+		rr, _ = postJSON(verify, map[string]string{"challenge_token": challenge, "code": "000000"}, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected wrong code to be rejected, got %d", rr.Code)
+		}
+
+		code, _ := auth.GenerateTOTPCode(secret, time.Now())
+		rr, _ = postJSON(verify, map[string]string{"challenge_token": challenge, "code": code}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("Set-Cookie") == "" {
+			t.Errorf("Expected session cookie after second factor")
+		}
+
+		// The challenge cannot be reused
+		rr, _ = postJSON(verify, map[string]string{"challenge_token": challenge, "code": code}, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected used challenge to be rejected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Login with recovery code", func(t *testing.T) {
+		_, response := login()
+		challenge := response["data"].(map[string]interface{})["challenge_token"].(string)
+
+		verify := http.HandlerFunc(authHandler.VerifyTwoFactor)
+		recoveryCode := strings.ToUpper(recoveryCodes[0].(string))
+		rr, _ := postJSON(verify, map[string]string{"challenge_token": challenge, "recovery_code": recoveryCode}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// Recovery codes are single-use
+		_, response = login()
+		challenge = response["data"].(map[string]interface{})["challenge_token"].(string)
+		rr, _ = postJSON(verify, map[string]string{"challenge_token": challenge, "recovery_code": recoveryCode}, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected used recovery code to be rejected, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Disable two-factor", func(t *testing.T) {
+		rr, _ := postJSON(sessionManager.AuthMiddleware(http.HandlerFunc(authHandler.DisableTwoFactor)), map[string]string{
+			"password":      "password123",
+			"recovery_code": recoveryCodes[1].(string),
+		}, currentSession)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		if rr, _ := login(); rr.Header().Get("Set-Cookie") == "" {
+			t.Errorf("Login should not require 2FA after disabling it")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+// backend/tests/upload_test.go
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/constants"
+	"ripple/pkg/models"
+)
+
+func TestUploadHardening(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	uploadsPath := t.TempDir()
+	handler := newTestRouterWithConfig(t, database, sessionManager, func(cfg *config.Config) {
+		cfg.UploadsPath = uploadsPath
+	})
+	_, session := createTestUser(t, userRepo, sessionManager, "uploads@test.com", true)
+
+	var pngImage, jpegImage bytes.Buffer
+	picture := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	png.Encode(&pngImage, picture)
+	jpeg.Encode(&jpegImage, picture, nil)
+
+	upload := func(filename, contentType string, content []byte) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Disposition", `form-data; name="image"; filename="`+filename+`"`)
+		partHeader.Set("Content-Type", contentType)
+		part, _ := form.CreatePart(partHeader)
+		part.Write(content)
+		form.Close()
+
+		req := httptest.NewRequest("POST", "/api/v1/upload/post", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr, response
+	}
+
+	rejected := func(t *testing.T, rr *httptest.ResponseRecorder, response map[string]interface{}, message string) {
+		t.Helper()
+		errorBody, _ := response["error"].(map[string]interface{})
+		if rr.Code != http.StatusBadRequest || errorBody["message"] != message {
+			t.Errorf("Expected 400 %q, got %d: %s", message, rr.Code, rr.Body.String())
+		}
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		return rr
+	}
+
+	t.Run("Images are stored under the detected type and served sandboxed", func(t *testing.T) {
+		rr, response := upload("photo.html", "image/png", pngImage.Bytes())
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		path := response["data"].(map[string]interface{})["file_path"].(string)
+		if !strings.HasSuffix(path, ".png") {
+			t.Errorf("Expected the stored name to end in .png, got %s", path)
+		}
+
+		rr = serve(path)
+		if rr.Code != http.StatusOK || !bytes.Equal(rr.Body.Bytes(), pngImage.Bytes()) {
+			t.Fatalf("Expected the image back, got %d", rr.Code)
+		}
+		expected := map[string]string{
+			"Content-Type":           "image/png",
+			"X-Content-Type-Options": "nosniff",
+		}
+		for name, value := range expected {
+			if got := rr.Header().Get(name); got != value {
+				t.Errorf("Expected %s %q, got %q", name, value, got)
+			}
+		}
+		if csp := rr.Header().Get("Content-Security-Policy"); !strings.HasPrefix(csp, "sandbox") {
+			t.Errorf("Expected a sandbox CSP, got %q", csp)
+		}
+		if disposition := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "inline") {
+			t.Errorf("Expected inline disposition, got %q", disposition)
+		}
+
+		if rr, _ := upload("photo.jpg", "image/jpg", jpegImage.Bytes()); rr.Code != http.StatusOK {
+			t.Errorf("Expected image/jpg to be accepted for a JPEG, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("Declared types must match the content", func(t *testing.T) {
+		rr, response := upload("photo.png", "image/png", jpegImage.Bytes())
+		rejected(t, rr, response, constants.ErrFileTypeMismatch)
+
+		truncated := append([]byte{}, pngImage.Bytes()[:12]...)
+		rr, response = upload("photo.png", "image/png", truncated)
+		rejected(t, rr, response, constants.ErrFileTypeMismatch)
+	})
+
+	t.Run("SVG and HTML are refused", func(t *testing.T) {
+		svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+		rr, response := upload("image.svg", "image/svg+xml", svg)
+		rejected(t, rr, response, constants.ErrInvalidFileType)
+
+		rr, response = upload("image.png", "image/png", svg)
+		rejected(t, rr, response, constants.ErrInvalidFileType)
+
+		rr, response = upload("page.gif", "image/gif", []byte("GIF89a<html><body>hi</body></html>"))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected a GIF header over HTML to be refused, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("Polyglots are refused", func(t *testing.T) {
+		withScript := append(append([]byte{}, pngImage.Bytes()...), []byte("<SCRIPT>alert(document.cookie)</SCRIPT>")...)
+		rr, response := upload("photo.png", "image/png", withScript)
+		rejected(t, rr, response, constants.ErrUnsafeFileContent)
+
+		var archive bytes.Buffer
+		archive.Write(pngImage.Bytes())
+		zipWriter := zip.NewWriter(&archive)
+		entry, _ := zipWriter.Create("payload.html")
+		entry.Write([]byte("hello"))
+		zipWriter.Close()
+		rr, response = upload("photo.png", "image/png", archive.Bytes())
+		rejected(t, rr, response, constants.ErrUnsafeFileContent)
+	})
+
+	t.Run("No directory listings or hidden files", func(t *testing.T) {
+		os.MkdirAll(filepath.Join(uploadsPath, "posts"), 0755)
+		os.WriteFile(filepath.Join(uploadsPath, "posts", ".secret"), []byte("secret"), 0644)
+
+		for _, path := range []string{"/uploads/", "/uploads/posts/", "/uploads/posts", "/uploads/posts/.secret"} {
+			if rr := serve(path); rr.Code != http.StatusNotFound {
+				t.Errorf("%s: expected status 404, got %d: %s", path, rr.Code, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("Other files are only downloaded", func(t *testing.T) {
+		os.WriteFile(filepath.Join(uploadsPath, "posts", "legacy.html"), []byte("<script>alert(1)</script>"), 0644)
+
+		rr := serve("/uploads/posts/legacy.html")
+		if rr.Header().Get("Content-Type") != "application/octet-stream" {
+			t.Errorf("Expected application/octet-stream, got %q", rr.Header().Get("Content-Type"))
+		}
+		if disposition := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment") {
+			t.Errorf("Expected attachment disposition, got %q", disposition)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// backend/tests/versioning_test.go
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/config"
+	"ripple/pkg/metrics"
+	"ripple/pkg/models"
+)
+
+func TestAPIVersioning(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	sunset := time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC)
+	handler := newTestRouterWithConfig(t, database, sessionManager, func(cfg *config.Config) {
+		cfg.LegacyAPISunset = sunset
+	})
+	user, session := createTestUser(t, userRepo, sessionManager, "versioning@test.com", true)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Versioned routes are not deprecated", func(t *testing.T) {
+		rr := get(fmt.Sprintf("/api/v1/users/%d", user.ID))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("Deprecation") != "" || rr.Header().Get("Sunset") != "" {
+			t.Errorf("Unexpected deprecation headers: %v", rr.Header())
+		}
+	})
+
+	t.Run("Unversioned paths are deprecated aliases", func(t *testing.T) {
+		before := metrics.DeprecatedRequests.Value("/api/users/{id}", "GET")
+
+		rr := get(fmt.Sprintf("/api/users/%d", user.ID))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got := rr.Header().Get("Deprecation"); got == "" || got[0] != '@' {
+			t.Errorf("Expected a Deprecation date, got %q", got)
+		}
+		if got := rr.Header().Get("Sunset"); got != "Wed, 30 Jun 2027 00:00:00 GMT" {
+			t.Errorf("Expected the configured Sunset date, got %q", got)
+		}
+		expectedLink := fmt.Sprintf(`</api/v1/users/%d>; rel="successor-version"`, user.ID)
+		if got := rr.Header().Get("Link"); got != expectedLink {
+			t.Errorf("Expected Link %q, got %q", expectedLink, got)
+		}
+
+		if got := metrics.DeprecatedRequests.Value("/api/users/{id}", "GET"); got != before+1 {
+			t.Errorf("Expected the alias use to be counted, got %v after %v", got, before)
+		}
+	})
+
+	t.Run("Earlier shapes of group routes are deprecated legacy routes", func(t *testing.T) {
+		send := func(method, path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			var response map[string]interface{}
+			json.Unmarshal(rr.Body.Bytes(), &response)
+			return rr, response
+		}
+		idOf := func(response map[string]interface{}, key string) int {
+			data, _ := response["data"].(map[string]interface{})
+			item, _ := data[key].(map[string]interface{})
+			id, ok := item["id"].(float64)
+			if !ok {
+				// Group posts are encoded with their Go field names
+				id, _ = item["ID"].(float64)
+			}
+			return int(id)
+		}
+		deprecatedAs := func(t *testing.T, rr *httptest.ResponseRecorder, status int, successor string) {
+			t.Helper()
+			if rr.Code != status {
+				t.Fatalf("Expected status %d, got %d: %s", status, rr.Code, rr.Body.String())
+			}
+			if rr.Header().Get("Deprecation") == "" || rr.Header().Get("Sunset") != "Wed, 30 Jun 2027 00:00:00 GMT" {
+				t.Errorf("Expected Deprecation and Sunset headers, got %v", rr.Header())
+			}
+			if expected := fmt.Sprintf(`<%s>; rel="successor-version"`, successor); rr.Header().Get("Link") != expected {
+				t.Errorf("Expected Link %q, got %q", expected, rr.Header().Get("Link"))
+			}
+		}
+
+		_, response := send("POST", "/api/v1/groups", `{"title": "Legacy routes"}`)
+		groupID := idOf(response, "group")
+		if groupID == 0 {
+			t.Fatalf("Failed to create group: %v", response)
+		}
+
+		before := metrics.DeprecatedRequests.Value("/api/groups/posts/{id}", "POST")
+		rr, response := send("POST", fmt.Sprintf("/api/groups/posts/%d", groupID), `{"content": "Old client post"}`)
+		deprecatedAs(t, rr, http.StatusCreated, fmt.Sprintf("/api/v1/groups/%d/posts", groupID))
+		postID := idOf(response, "post")
+		if got := metrics.DeprecatedRequests.Value("/api/groups/posts/{id}", "POST"); got != before+1 {
+			t.Errorf("Expected the legacy route use to be counted, got %v after %v", got, before)
+		}
+
+		rr, _ = send("GET", fmt.Sprintf("/api/groups/posts/get/%d", groupID), "")
+		deprecatedAs(t, rr, http.StatusOK, fmt.Sprintf("/api/v1/groups/%d/posts", groupID))
+
+		rr, _ = send("POST", fmt.Sprintf("/api/groups/comments/%d", postID), `{"content": "Old client comment"}`)
+		deprecatedAs(t, rr, http.StatusCreated, fmt.Sprintf("/api/v1/groups/posts/%d/comments", postID))
+
+		rr, _ = send("GET", fmt.Sprintf("/api/groups/comments/get/%d", postID), "")
+		deprecatedAs(t, rr, http.StatusOK, fmt.Sprintf("/api/v1/groups/posts/%d/comments", postID))
+
+		// The creator may not leave, but the route is still found
+		rr, _ = send("POST", fmt.Sprintf("/api/groups/leave/%d", groupID), "")
+		deprecatedAs(t, rr, http.StatusForbidden, fmt.Sprintf("/api/v1/groups/%d/leave", groupID))
+
+		// Literal segments of current routes win over the wildcards of legacy ones
+		rr, response = send("POST", "/api/groups/posts/like", fmt.Sprintf(`{"post_id": %d}`, postID))
+		deprecatedAs(t, rr, http.StatusOK, "/api/v1/groups/posts/like")
+		if data, _ := response["data"].(map[string]interface{}); data["liked"] != true {
+			t.Errorf("Expected the post to be liked, got %s", rr.Body.String())
+		}
+
+		if rr, _ := send("GET", fmt.Sprintf("/api/v1/groups/posts/get/%d", groupID), ""); rr.Code != http.StatusNotFound {
+			t.Errorf("Expected legacy shapes not to exist under /api/v1, got %d", rr.Code)
+		}
+	})
+
+	t.Run("Unknown paths are not flagged", func(t *testing.T) {
+		rr := get("/api/no-such-route")
+		if rr.Code != http.StatusNotFound || rr.Header().Get("Deprecation") != "" {
+			t.Errorf("Expected a plain 404, got %d with %v", rr.Code, rr.Header())
+		}
+	})
+}
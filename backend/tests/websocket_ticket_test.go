@@ -0,0 +1,138 @@
+// backend/tests/websocket_ticket_test.go
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"ripple/pkg/auth"
+	"ripple/pkg/models"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+func TestWebSocketTickets(t *testing.T) {
+	database, cleanup := setupTestDB()
+	defer cleanup()
+
+	userRepo := models.NewUserRepository(database.DB)
+	sessionManager := auth.NewSessionManager(database.DB)
+	server := httptest.NewServer(newTestRouter(t, database, sessionManager))
+	defer server.Close()
+
+	user, session := createTestUser(t, userRepo, sessionManager, "ticket@test.com", true)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	issueTicket := func(t *testing.T, sessionID string) string {
+		t.Helper()
+		req, _ := http.NewRequest("POST", server.URL+"/api/auth/ws-ticket", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Ticket request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+
+		var response struct {
+			Data struct {
+				Ticket string `json:"ticket"`
+			} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return response.Data.Ticket
+	}
+
+	dial := func(query string) (*gorilla.Conn, int) {
+		conn, resp, err := gorilla.DefaultDialer.Dial(wsURL+query, nil)
+		if err != nil {
+			if resp != nil {
+				return nil, resp.StatusCode
+			}
+			t.Fatalf("Dial failed: %v", err)
+		}
+		return conn, http.StatusSwitchingProtocols
+	}
+
+	t.Run("Ticket requires a session", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/auth/ws-ticket", "application/json", nil)
+		if err != nil {
+			t.Fatalf("Ticket request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Ticket opens exactly one connection", func(t *testing.T) {
+		ticket := issueTicket(t, session.ID)
+
+		var stored int
+		database.DB.QueryRow(`SELECT COUNT(*) FROM websocket_tickets WHERE ticket_hash = ?`, auth.HashToken(ticket)).Scan(&stored)
+		if stored != 1 {
+			t.Errorf("Expected only the ticket hash to be stored")
+		}
+
+		conn, status := dial("?ticket=" + url.QueryEscape(ticket))
+		if status != http.StatusSwitchingProtocols {
+			t.Fatalf("Expected connection with ticket, got %d", status)
+		}
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var message map[string]interface{}
+		for message["type"] != "connection_status" {
+			if err := conn.ReadJSON(&message); err != nil {
+				t.Fatalf("Failed to read connection status: %v", err)
+			}
+		}
+		if data := message["data"].(map[string]interface{}); data["user_id"] != float64(user.ID) {
+			t.Errorf("Expected connection for user %d, got %v", user.ID, data["user_id"])
+		}
+
+		if _, status := dial("?ticket=" + url.QueryEscape(ticket)); status != http.StatusUnauthorized {
+			t.Errorf("Expected reused ticket to be rejected, got %d", status)
+		}
+	})
+
+	t.Run("Expired ticket is rejected", func(t *testing.T) {
+		ticket := issueTicket(t, session.ID)
+		database.DB.Exec(`UPDATE websocket_tickets SET expires_at = ?`, time.Now().Add(-time.Second))
+
+		if _, status := dial("?ticket=" + url.QueryEscape(ticket)); status != http.StatusUnauthorized {
+			t.Errorf("Expected expired ticket to be rejected, got %d", status)
+		}
+		if err := sessionManager.CleanupExpiredWebSocketTickets(); err != nil {
+			t.Fatalf("Cleanup failed: %v", err)
+		}
+		var remaining int
+		database.DB.QueryRow(`SELECT COUNT(*) FROM websocket_tickets`).Scan(&remaining)
+		if remaining != 0 {
+			t.Errorf("Expected expired tickets to be removed, found %d", remaining)
+		}
+	})
+
+	t.Run("Ticket dies with its session", func(t *testing.T) {
+		_, otherSessionID := createTestSession(t, sessionManager, user.ID)
+		ticket := issueTicket(t, otherSessionID)
+		sessionManager.DeleteSession(otherSessionID)
+
+		if _, status := dial("?ticket=" + url.QueryEscape(ticket)); status != http.StatusUnauthorized {
+			t.Errorf("Expected ticket of revoked session to be rejected, got %d", status)
+		}
+	})
+
+	t.Run("Session ID in the URL is not accepted", func(t *testing.T) {
+		if _, status := dial("?session_id=" + url.QueryEscape(session.ID)); status != http.StatusUnauthorized {
+			t.Errorf("Expected session_id query parameter to be rejected, got %d", status)
+		}
+	})
+}
@@ -31,7 +31,7 @@ function ChatContent() {
     try {
       setLoading(true)
       setError(null)
-      const response = await fetch(`${API_URL}/api/chat/conversations`, {
+      const response = await fetch(`${API_URL}/api/v1/chat/conversations`, {
         credentials: 'include',
       })
       if (!response.ok) throw new Error('Failed to fetch conversations')
@@ -54,7 +54,7 @@ function ChatContent() {
     try {
       setUsersLoading(true)
       setUsersError(null)
-      const response = await fetch(`${API_URL}/api/chat/followed-users`, {
+      const response = await fetch(`${API_URL}/api/v1/chat/followed-users`, {
         credentials: 'include',
       })
       if (!response.ok) throw new Error('Failed to fetch followed users')
@@ -22,7 +22,7 @@ export default function EventsPage() {
   const fetchUserGroups = async () => {
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/user`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/user`, {
         credentials: 'include',
       })
 
@@ -33,7 +33,7 @@ export default function GroupDetailPage() {
   // Fetch group details
   const fetchGroup = async () => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}`, {
         credentials: 'include',
       })
 
@@ -56,7 +56,7 @@ export default function GroupDetailPage() {
   // Fetch group members
   const fetchMembers = async () => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/${groupId}/members`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/members`, {
         credentials: 'include',
       })
 
@@ -131,7 +131,7 @@ export default function GroupDetailPage() {
     }
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/leave/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/leave`, {
         method: 'POST',
         credentials: 'include',
       })
@@ -21,7 +21,7 @@ export default function BrowseGroupsPage() {
   // Fetch all public groups
   const fetchGroups = async () => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/all?limit=50`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/all?limit=50`, {
         credentials: 'include',
       })
 
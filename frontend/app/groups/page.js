@@ -20,7 +20,7 @@ export default function GroupsPage() {
   const fetchGroups = async () => {
     try {
       const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'
-      const response = await fetch(`${API_URL}/api/groups/user`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/user`, {
         credentials: 'include',
       })
 
@@ -51,7 +51,7 @@ export default function ProfilePage() {
           profileData = { data: user }
         } else {
           // Use the user profile endpoint for other users
-          const profileResponse = await fetch(`${API_URL}/api/users/${targetUserId}`, {
+          const profileResponse = await fetch(`${API_URL}/api/v1/users/${targetUserId}`, {
             credentials: 'include'
           })
 
@@ -80,7 +80,7 @@ export default function ProfilePage() {
   // Handle privacy toggle
   const handlePrivacyToggle = async (isPublic) => {
     try {
-      const response = await fetch(`${API_URL}/api/auth/profile/update`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/profile/update`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json'
@@ -43,7 +43,7 @@ function SearchContent() {
       // Search users
       let users = []
       try {
-        const usersResponse = await fetch(`${API_URL}/api/auth/search?q=${encodeURIComponent(searchTerm)}`, {
+        const usersResponse = await fetch(`${API_URL}/api/v1/auth/search?q=${encodeURIComponent(searchTerm)}`, {
           credentials: 'include',
         })
 
@@ -61,7 +61,7 @@ function SearchContent() {
       // Search groups  
       let groups = []
       try {
-        const groupsResponse = await fetch(`${API_URL}/api/groups/search?q=${encodeURIComponent(searchTerm)}`, {
+        const groupsResponse = await fetch(`${API_URL}/api/v1/groups/search?q=${encodeURIComponent(searchTerm)}`, {
           credentials: 'include',
         })
 
@@ -81,7 +81,7 @@ function SearchContent() {
       // Search posts
       let posts = []
       try {
-        const postsResponse = await fetch(`${API_URL}/api/posts/search?q=${encodeURIComponent(searchTerm)}`, {
+        const postsResponse = await fetch(`${API_URL}/api/v1/posts/search?q=${encodeURIComponent(searchTerm)}`, {
           credentials: 'include',
         })
 
@@ -142,7 +142,7 @@ function SearchContent() {
 
   const performFollowToggle = async (userId, isFollowing) => {
     try {
-      const endpoint = isFollowing ? '/api/unfollow' : '/api/follow'
+      const endpoint = isFollowing ? '/api/v1/unfollow' : '/api/v1/follow'
       const response = await fetch(`${API_URL}${endpoint}`, {
         method: 'POST',
         headers: {
@@ -197,7 +197,7 @@ function SearchContent() {
 
   const handleGroupJoin = async (groupId) => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/join`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/join`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
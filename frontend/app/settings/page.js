@@ -83,7 +83,7 @@ export default function SettingsPage() {
     setError('')
 
     try {
-      const response = await fetch(`${API_URL}/api/auth/profile/update`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/profile/update`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json',
@@ -130,7 +130,7 @@ export default function SettingsPage() {
     setError('')
 
     try {
-      const response = await fetch(`${API_URL}/api/auth/change-password`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/change-password`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -179,7 +179,7 @@ export default function SettingsPage() {
 
     setLoading(true)
     try {
-      const response = await fetch(`${API_URL}/api/auth/delete-account`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/delete-account`, {
         method: 'DELETE',
         credentials: 'include'
       })
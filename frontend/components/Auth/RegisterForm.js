@@ -188,7 +188,7 @@ export default function RegisterForm() {
         avatarFormData.append('avatar', fileInputRef.current.files[0])
 
         // Upload avatar
-        const avatarResponse = await fetch(`${API_URL}/api/upload/avatar`, {
+        const avatarResponse = await fetch(`${API_URL}/api/v1/upload/avatar`, {
           method: 'POST',
           body: avatarFormData,
           credentials: 'include', // Important for cookies
@@ -198,7 +198,7 @@ export default function RegisterForm() {
           const avatarData = await avatarResponse.json()
           const filePath = avatarData.file_path || (avatarData.data && avatarData.data.file_path)
           if (filePath) {
-            const profileUpdateResponse = await fetch(`${API_URL}/api/auth/profile/update`, {
+            const profileUpdateResponse = await fetch(`${API_URL}/api/v1/auth/profile/update`, {
               method: 'PUT',
               headers: { 'Content-Type': 'application/json' },
               body: JSON.stringify({ avatar_path: filePath }),
@@ -65,8 +65,8 @@ export default function ChatMain({ conversation, onConversationStarted }) {
       setError(null)
       try {
         const endpoint = conversation.isGroup
-          ? `${API_URL}/api/chat/messages/group/${conversation.id}`
-          : `${API_URL}/api/chat/messages/private/${conversation.id}`
+          ? `${API_URL}/api/v1/chat/messages/group/${conversation.id}`
+          : `${API_URL}/api/v1/chat/messages/private/${conversation.id}`
 
         const response = await fetch(endpoint, {
           credentials: 'include',
@@ -236,7 +236,7 @@ export default function ChatMain({ conversation, onConversationStarted }) {
         const formData = new FormData()
         formData.append('image', selectedImage)
 
-        const response = await fetch(`${API_URL}/api/upload/chat`, {
+        const response = await fetch(`${API_URL}/api/v1/upload/chat`, {
           method: 'POST',
           body: formData,
           credentials: 'include',
@@ -286,8 +286,8 @@ export default function ChatMain({ conversation, onConversationStarted }) {
       // WebSocket not available, fallback to REST API
       try {
         const endpoint = conversation.isGroup 
-          ? `${API_URL}/api/chat/messages/group`
-          : `${API_URL}/api/chat/messages/private`
+          ? `${API_URL}/api/v1/chat/messages/group`
+          : `${API_URL}/api/v1/chat/messages/private`
         
         const requestBody = conversation.isGroup 
           ? { group_id: conversation.id, content: messageContent }
@@ -23,7 +23,7 @@ export default function GroupChatInfo({ group, onClose }) {
   const fetchGroupMembers = async () => {
     try {
       setLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/${group.id}/members`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${group.id}/members`, {
         credentials: 'include',
       })
 
@@ -48,7 +48,7 @@ export default function GroupChatInfo({ group, onClose }) {
     if (!inviteEmail.trim()) return
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/invite`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/invite`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
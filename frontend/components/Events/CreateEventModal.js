@@ -75,7 +75,7 @@ export default function CreateEventModal({ isOpen, onClose, onSuccess, groupId,
       // Combine date and time into ISO format
       const eventDateTime = new Date(formData.event_date + 'T' + formData.event_time).toISOString()
 
-      const response = await fetch(`${API_URL}/api/events/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/events/${groupId}`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -46,7 +46,7 @@ export default function EventCard({ event, onRespond, showGroupInfo = false }) {
 
     setIsLoading(true)
     try {
-      const apiResponse = await fetch(`${API_URL}/api/events/respond/${event.id}`, {
+      const apiResponse = await fetch(`${API_URL}/api/v1/events/respond/${event.id}`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -24,8 +24,8 @@ export default function EventList({ groupId, showGroupInfo = false, title = "Eve
       }
 
       const url = groupId
-        ? `${API_URL}/api/events/group/${groupId}?limit=${EVENTS_PER_PAGE}&offset=${offset}`
-        : `${API_URL}/api/events?limit=${EVENTS_PER_PAGE}&offset=${offset}`
+        ? `${API_URL}/api/v1/events/group/${groupId}?limit=${EVENTS_PER_PAGE}&offset=${offset}`
+        : `${API_URL}/api/v1/events?limit=${EVENTS_PER_PAGE}&offset=${offset}`
 
       const response = await fetch(url, {
         credentials: 'include',
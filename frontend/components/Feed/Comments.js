@@ -16,7 +16,7 @@ export default function Comments({ postId }) {
   const fetchComments = async () => {
     try {
       const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'
-      const response = await fetch(`${API_URL}/api/posts/comments/${postId}`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/comments/${postId}`, {
         credentials: 'include',
       })
 
@@ -65,7 +65,7 @@ export default function Comments({ postId }) {
         const formData = new FormData()
         formData.append('image', imageFile)
 
-        const uploadResponse = await fetch(`${API_URL}/api/upload/comment`, {
+        const uploadResponse = await fetch(`${API_URL}/api/v1/upload/comment`, {
           method: 'POST',
           body: formData,
           credentials: 'include',
@@ -80,7 +80,7 @@ export default function Comments({ postId }) {
       }
 
       // Create the comment
-      const response = await fetch(`${API_URL}/api/posts/comments/create`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/comments/create`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
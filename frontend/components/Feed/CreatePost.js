@@ -21,7 +21,7 @@ export default function CreatePost({ onPostCreated }) {
     if (privacy === 'private' && user?.id) {
       const fetchFollowers = async () => {
         try {
-          const response = await fetch(`${API_URL}/api/follow/followers/${user.id}`, { credentials: 'include' })
+          const response = await fetch(`${API_URL}/api/v1/follow/followers/${user.id}`, { credentials: 'include' })
           if (!response.ok) throw new Error('Failed to fetch followers')
           const result = await response.json()
           setFollowers(result.data?.followers || [])
@@ -70,7 +70,7 @@ export default function CreatePost({ onPostCreated }) {
         const formData = new FormData()
         formData.append('image', mediaFile)
 
-        const response = await fetch(`${API_URL}/api/upload/post`, {
+        const response = await fetch(`${API_URL}/api/v1/upload/post`, {
           method: 'POST',
           body: formData,
           credentials: 'include',
@@ -93,7 +93,7 @@ export default function CreatePost({ onPostCreated }) {
       if (privacy === 'private' && selectedFollowers.length > 0) {
         postBody.allowed_users = selectedFollowers
       }
-      const responsePost = await fetch(`${API_URL}/api/posts`, {
+      const responsePost = await fetch(`${API_URL}/api/v1/posts`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
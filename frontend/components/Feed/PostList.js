@@ -29,9 +29,9 @@ const PostList = forwardRef(function PostList(_, ref) {
       setIsLoading(true)
       let url
       if (typeof _.userId !== 'undefined' && _.userId !== null) {
-        url = `${API_URL}/api/posts/user/${_.userId}`
+        url = `${API_URL}/api/v1/posts/user/${_.userId}`
       } else {
-        url = `${API_URL}/api/posts/feed`
+        url = `${API_URL}/api/v1/posts/feed`
       }
       const response = await fetch(url, {
         credentials: 'include',
@@ -93,7 +93,7 @@ const PostList = forwardRef(function PostList(_, ref) {
 
     try {
       // Use the single toggle endpoint from main branch
-      const response = await fetch(`${API_URL}/api/posts/like`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/like`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -209,7 +209,7 @@ const PostList = forwardRef(function PostList(_, ref) {
 
     try {
       const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8000'
-      const response = await fetch(`${API_URL}/api/posts/delete/${postId}`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/delete/${postId}`, {
         method: 'DELETE',
         credentials: 'include',
       })
@@ -232,7 +232,7 @@ const PostList = forwardRef(function PostList(_, ref) {
 
     try {
       const API_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8000'
-      const response = await fetch(`${API_URL}/api/posts/update`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/update`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json',
@@ -266,7 +266,7 @@ const PostList = forwardRef(function PostList(_, ref) {
   }
   const handleLike = async (postId) => {
     try {
-      const response = await fetch(`${API_URL}/api/posts/like`, {
+      const response = await fetch(`${API_URL}/api/v1/posts/like`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -35,7 +35,7 @@ export default function BrowseGroupCard({ group, onJoinSuccess }) {
     setError('')
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/join`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/join`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
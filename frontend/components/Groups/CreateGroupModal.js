@@ -103,7 +103,7 @@ export default function CreateGroupModal({ isOpen, onClose, onGroupCreated }) {
     }
 
     const endpoint = type === 'avatar' ? 'group-avatar' : 'group-cover'
-    const response = await fetch(`${API_URL}/api/upload/${endpoint}`, {
+    const response = await fetch(`${API_URL}/api/v1/upload/${endpoint}`, {
       method: 'POST',
       body: formData,
       credentials: 'include',
@@ -160,7 +160,7 @@ export default function CreateGroupModal({ isOpen, onClose, onGroupCreated }) {
         coverPath = await uploadFile(coverFile, 'cover')
       }
 
-      const response = await fetch(`${API_URL}/api/groups`, {
+      const response = await fetch(`${API_URL}/api/v1/groups`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -68,7 +68,7 @@ export default function CreateGroupPost({ groupId, onPostCreated }) {
         const formData = new FormData()
         formData.append('image', imageFile)
 
-        const uploadResponse = await fetch(`${API_URL}/api/upload/post`, {
+        const uploadResponse = await fetch(`${API_URL}/api/v1/upload/post`, {
           method: 'POST',
           body: formData,
           credentials: 'include',
@@ -83,7 +83,7 @@ export default function CreateGroupPost({ groupId, onPostCreated }) {
       }
 
       // Create the group post
-      const response = await fetch(`${API_URL}/api/groups/posts/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/posts`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
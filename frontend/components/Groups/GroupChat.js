@@ -50,7 +50,7 @@ export default function GroupChat({ groupId, groupTitle }) {
   const fetchMessages = async () => {
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/chat/messages/group/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/chat/messages/group/${groupId}`, {
         credentials: 'include',
       })
 
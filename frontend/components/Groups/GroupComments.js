@@ -29,7 +29,7 @@ export default function GroupComments({ postId, isGroupMember, onCommentAdded })
   const fetchComments = async () => {
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/comments/get/${postId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/posts/${postId}/comments`, {
         credentials: 'include',
       })
 
@@ -73,7 +73,7 @@ export default function GroupComments({ postId, isGroupMember, onCommentAdded })
         const formData = new FormData()
         formData.append('image', imageFile)
 
-        const uploadResponse = await fetch(`${API_URL}/api/upload/comment`, {
+        const uploadResponse = await fetch(`${API_URL}/api/v1/upload/comment`, {
           method: 'POST',
           body: formData,
           credentials: 'include',
@@ -87,7 +87,7 @@ export default function GroupComments({ postId, isGroupMember, onCommentAdded })
         imagePath = uploadData.data.file_path
       }
 
-      const response = await fetch(`${API_URL}/api/groups/comments/${postId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/posts/${postId}/comments`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -78,7 +78,7 @@ export default function GroupPost({ post, onPostDeleted, isGroupMember }) {
 
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/posts/delete/${post.ID}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/posts/delete/${post.ID}`, {
         method: 'DELETE',
         credentials: 'include',
       })
@@ -105,7 +105,7 @@ export default function GroupPost({ post, onPostDeleted, isGroupMember }) {
 
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/posts/update`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/posts/update`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json',
@@ -147,7 +147,7 @@ export default function GroupPost({ post, onPostDeleted, isGroupMember }) {
     setIsLiked(!prevLiked)
     setLikeCount(prev => prevLiked ? prev - 1 : prev + 1)
     try {
-      const response = await fetch(`${API_URL}/api/groups/posts/like`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/posts/like`, {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({ post_id: post.ID }),
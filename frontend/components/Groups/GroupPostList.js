@@ -15,7 +15,7 @@ export default function GroupPostList({ groupId, isGroupMember }) {
   const fetchPosts = async () => {
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/posts/get/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/posts`, {
         credentials: 'include',
       })
 
@@ -116,7 +116,7 @@ export default function GroupSettingsModal({ isOpen, onClose, onGroupUpdated, gr
     const fieldName = endpoint === 'group-avatar' ? 'group-avatar' : 'group-cover'
     formData.append(fieldName, file)
 
-    const response = await fetch(`${API_URL}/api/upload/${endpoint}`, {
+    const response = await fetch(`${API_URL}/api/v1/upload/${endpoint}`, {
       method: 'POST',
       body: formData,
       credentials: 'include'
@@ -150,7 +150,7 @@ export default function GroupSettingsModal({ isOpen, onClose, onGroupUpdated, gr
       }
 
       // Update group
-      const response = await fetch(`${API_URL}/api/groups/${group.id}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${group.id}`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json',
@@ -28,7 +28,7 @@ export default function InviteUsersModal({ isOpen, onClose, onSuccess, groupId,
 
     try {
       const response = await fetch(
-        `${API_URL}/api/auth/search?q=${encodeURIComponent(query)}&limit=20`,
+        `${API_URL}/api/v1/auth/search?q=${encodeURIComponent(query)}&limit=20`,
         {
           credentials: 'include',
         }
@@ -87,7 +87,7 @@ export default function InviteUsersModal({ isOpen, onClose, onSuccess, groupId,
     try {
       const userIds = selectedUsers.map(user => user.id)
       
-      const response = await fetch(`${API_URL}/api/groups/${groupId}/invite`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/invite`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
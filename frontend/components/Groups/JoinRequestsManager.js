@@ -16,7 +16,7 @@ export default function JoinRequestsManager({ groupId, isVisible, onRequestHandl
 
     try {
       setIsLoading(true)
-      const response = await fetch(`${API_URL}/api/groups/${groupId}/requests`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}/requests`, {
         credentials: 'include',
       })
 
@@ -40,7 +40,7 @@ export default function JoinRequestsManager({ groupId, isVisible, onRequestHandl
     setProcessingIds(prev => new Set(prev).add(membershipId))
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/handle`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/handle`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -13,7 +13,7 @@ export default function PendingInvitations({ onInvitationHandled }) {
   // Fetch pending invitations
   const fetchInvitations = async () => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/invitations`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/invitations`, {
         credentials: 'include',
       })
 
@@ -38,7 +38,7 @@ export default function PendingInvitations({ onInvitationHandled }) {
     setProcessingIds(prev => new Set(prev).add(membershipId))
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/handle`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/handle`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -32,7 +32,7 @@ export default function SearchDropdown({
       setIsLoading(true)
       setError('')
       
-      const response = await fetch(`${API_URL}/api/auth/search?q=${encodeURIComponent(query)}`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/search?q=${encodeURIComponent(query)}`, {
         credentials: 'include',
         headers: {
           'Content-Type': 'application/json',
@@ -70,7 +70,7 @@ export default function NotificationItem({ notification }) {
         throw new Error('Invalid follow request data')
       }
 
-      const response = await fetch(`${process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'}/api/follow/handle`, {
+      const response = await fetch(`${process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'}/api/v1/follow/handle`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json'
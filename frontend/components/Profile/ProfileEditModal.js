@@ -68,7 +68,7 @@ export default function ProfileEditModal({ profile, isOpen, onClose, onSave }) {
     const fieldName = endpoint === 'avatar' ? 'avatar' : 'cover'
     formData.append(fieldName, file)
 
-    const response = await fetch(`${API_URL}/api/upload/${endpoint}`, {
+    const response = await fetch(`${API_URL}/api/v1/upload/${endpoint}`, {
       method: 'POST',
       body: formData,
       credentials: 'include'
@@ -103,7 +103,7 @@ export default function ProfileEditModal({ profile, isOpen, onClose, onSave }) {
       }
 
       // Update profile
-      const response = await fetch(`${API_URL}/api/auth/profile/update`, {
+      const response = await fetch(`${API_URL}/api/v1/auth/profile/update`, {
         method: 'PUT',
         headers: {
           'Content-Type': 'application/json'
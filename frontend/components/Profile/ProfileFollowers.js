@@ -16,7 +16,7 @@ export default function ProfileFollowers({ userId }) {
 
       try {
         const response = await fetch(
-          `${API_URL}/api/follow/followers/${userId}`,
+          `${API_URL}/api/v1/follow/followers/${userId}`,
           { credentials: 'include' }
         )
         
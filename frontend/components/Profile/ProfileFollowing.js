@@ -16,7 +16,7 @@ export default function ProfileFollowing({ userId }) {
 
       try {
         const response = await fetch(
-          `${API_URL}/api/follow/following/${userId}`,
+          `${API_URL}/api/v1/follow/following/${userId}`,
           { credentials: 'include' }
         )
         
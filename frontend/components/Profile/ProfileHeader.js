@@ -24,7 +24,7 @@ export default function ProfileHeader({ profile, isCurrentUser, onPrivacyToggle,
       if (isCurrentUser || !profile?.id) return
 
       try {
-        const response = await fetch(`${API_URL}/api/follow/status/${profile.id}`, {
+        const response = await fetch(`${API_URL}/api/v1/follow/status/${profile.id}`, {
           credentials: 'include'
         })
 
@@ -64,8 +64,8 @@ export default function ProfileHeader({ profile, isCurrentUser, onPrivacyToggle,
 
     try {
       const endpoint = followStatus.is_following
-        ? `${API_URL}/api/unfollow`
-        : `${API_URL}/api/follow`
+        ? `${API_URL}/api/v1/unfollow`
+        : `${API_URL}/api/v1/follow`
 
       const response = await fetch(endpoint, {
         method: 'POST',
@@ -19,7 +19,7 @@ export default function OnlineFriends() {
   const fetchOnlineFriends = useCallback(async () => {
     try {
       setLoading(true)
-      const response = await fetch(`${API_URL}/api/chat/online`, {
+      const response = await fetch(`${API_URL}/api/v1/chat/online`, {
         credentials: 'include',
       })
 
@@ -25,7 +25,7 @@ export default function SuggestedGroups() {
       setIsLoading(true)
       setError('')
 
-      const response = await fetch(`${API_URL}/api/groups/recommendations?limit=3`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/recommendations?limit=3`, {
         credentials: 'include',
         headers: {
           'Content-Type': 'application/json',
@@ -58,7 +58,7 @@ export default function SuggestedGroups() {
     try {
       setJoiningGroups(prev => new Set([...prev, groupId]))
 
-      const response = await fetch(`${API_URL}/api/groups/join`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/join`, {
         method: 'POST',
         credentials: 'include',
         headers: {
@@ -87,7 +87,7 @@ export function GroupChatNotificationProvider({ children }) {
     }
 
     try {
-      const response = await fetch(`${API_URL}/api/groups/${groupId}`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/${groupId}`, {
         credentials: 'include',
       })
 
@@ -27,7 +27,7 @@ export function NotificationProvider({ children }) {
 
     try {
       setLoading(true)
-      const response = await fetch(`${API_URL}/api/notifications`, {
+      const response = await fetch(`${API_URL}/api/v1/notifications`, {
         credentials: 'include'
       })
 
@@ -46,7 +46,7 @@ export function NotificationProvider({ children }) {
   // Mark notification as read
   const markAsRead = async (notificationId) => {
     try {
-      const response = await fetch(`${API_URL}/api/notifications/read/${notificationId}`, {
+      const response = await fetch(`${API_URL}/api/v1/notifications/read/${notificationId}`, {
         method: 'PUT',
         credentials: 'include'
       })
@@ -69,7 +69,7 @@ export function NotificationProvider({ children }) {
   // Mark all notifications as read
   const markAllAsRead = async () => {
     try {
-      const response = await fetch(`${API_URL}/api/notifications/read-all`, {
+      const response = await fetch(`${API_URL}/api/v1/notifications/read-all`, {
         method: 'PUT',
         credentials: 'include'
       })
@@ -88,7 +88,7 @@ export function NotificationProvider({ children }) {
   // Handle group invitation/join request response
   const handleGroupInvitation = async (membershipId, action) => {
     try {
-      const response = await fetch(`${API_URL}/api/groups/handle`, {
+      const response = await fetch(`${API_URL}/api/v1/groups/handle`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json'
@@ -117,7 +117,7 @@ export function NotificationProvider({ children }) {
   // Handle follow request response
   const handleFollowRequest = async (followId, action) => {
     try {
-      const response = await fetch(`${API_URL}/api/follow/handle`, {
+      const response = await fetch(`${API_URL}/api/v1/follow/handle`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json'